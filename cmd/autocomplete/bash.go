@@ -28,18 +28,18 @@ _wt_completion() {
     _init_completion || return
 
     case $prev in
-        add|workon)
+        add|workon|refresh|refresh-files|open|logs|wait|up|down)
             # Complete with branch names from current repo worktrees
             if command -v wt >/dev/null 2>&1; then
-                local branches=($(wt tree list 2>/dev/null | grep "🔸" | head -20))
+                local branches=($(wt tree list --names 2>/dev/null | head -20))
                 COMPREPLY=($(compgen -W "${branches[*]}" -- "$cur"))
             fi
             return
             ;;
         remove)
             # Complete with available worktrees that can be removed
-            if command -v wt >/dev/null 2>&1 && command -v jq >/dev/null 2>&1; then
-                local worktrees=($(wt tree list --json 2>/dev/null | jq -r '.[]' 2>/dev/null))
+            if command -v wt >/dev/null 2>&1; then
+                local worktrees=($(wt tree list --names 2>/dev/null))
                 COMPREPLY=($(compgen -W "${worktrees[*]}" -- "$cur"))
             fi
             return
@@ -48,6 +48,59 @@ _wt_completion() {
             # No completion for URLs
             return
             ;;
+        --file|--output|-o)
+            # Complete workspace files
+            _filedir
+            return
+            ;;
+        --to|--home)
+            # Complete directories
+            _filedir -d
+            return
+            ;;
+        restore)
+            # Complete backup archives
+            _filedir
+            return
+            ;;
+        -f)
+            # 'wt apply -f' takes a workspace file, elsewhere -f is a boolean
+            if [[ ${words[1]} == apply ]]; then
+                _filedir
+                return
+            fi
+            ;;
+        --hook)
+            COMPREPLY=($(compgen -W "post-worktree-add work-on" -- "$cur"))
+            return
+            ;;
+        env)
+            # 'wt env' takes a branch, 'wt repo env' variables
+            if [[ ${words[1]} == env ]] && command -v wt >/dev/null 2>&1; then
+                local branches=($(wt tree list --names 2>/dev/null | head -20))
+                COMPREPLY=($(compgen -W "${branches[*]}" -- "$cur"))
+            fi
+            return
+            ;;
+        show|edit)
+            # 'wt hooks show/edit' take a hook, 'wt config show/edit' nothing
+            if [[ ${words[1]} == hooks ]]; then
+                COMPREPLY=($(compgen -W "post-worktree-add work-on" -- "$cur"))
+            fi
+            return
+            ;;
+        --template|-t)
+            COMPREPLY=($(compgen -W "auto node go python rust gradle docker-compose" -- "$cur"))
+            return
+            ;;
+        --mode)
+            COMPREPLY=($(compgen -W "reflink hardlink" -- "$cur"))
+            return
+            ;;
+        doctor)
+            COMPREPLY=($(compgen -W "--fix --json" -- "$cur"))
+            return
+            ;;
         use)
             # Complete with repository aliases
             if command -v wt >/dev/null 2>&1; then
@@ -61,7 +114,7 @@ _wt_completion() {
     case $cword in
         1)
             # First level commands
-            local commands="init doctor config repo tree autocomplete"
+            local commands="init doctor config repo tree status sync du dedupe exec env pr pick ui open logs hooks apply export backup migrate-home autocomplete"
             COMPREPLY=($(compgen -W "$commands" -- "$cur"))
             ;;
         2)
@@ -70,10 +123,16 @@ _wt_completion() {
                     COMPREPLY=($(compgen -W "edit show" -- "$cur"))
                     ;;
                 repo)
-                    COMPREPLY=($(compgen -W "clone list remove use" -- "$cur"))
+                    COMPREPLY=($(compgen -W "clone list remove use ports env" -- "$cur"))
                     ;;
                 tree)
-                    COMPREPLY=($(compgen -W "add remove list workon" -- "$cur"))
+                    COMPREPLY=($(compgen -W "add remove list workon wait up down refresh refresh-files" -- "$cur"))
+                    ;;
+                hooks)
+                    COMPREPLY=($(compgen -W "list show edit init templates" -- "$cur"))
+                    ;;
+                backup)
+                    COMPREPLY=($(compgen -W "create restore" -- "$cur"))
                     ;;
                 autocomplete)
                     COMPREPLY=($(compgen -W "bash zsh" -- "$cur"))
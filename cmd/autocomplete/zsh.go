@@ -43,7 +43,10 @@ _wt() {
                 repo)
                     case $words[2] in
                         clone)
-                            _message "repository URL"
+                            _arguments \
+                                "(-a --alias)"{-a,--alias}"[Alias for the repository]:alias:" \
+                                "(-t --template)"{-t,--template}"[Generate the post-worktree-add script from templates]:template:(auto node go python rust gradle docker-compose)" \
+                                "1:repository URL:"
                             ;;
                         use|remove)
                             _wt_repos
@@ -53,27 +56,117 @@ _wt() {
                                 "clone[Clone repository]" \
                                 "list[List repositories]" \
                                 "remove[Remove repository]" \
-                                "use[Use repository]"
+                                "use[Use repository]" \
+                                "ports[Show or set port slots]" \
+                                "env[Show or set worktree variables]"
                             ;;
                     esac
                     ;;
                 tree)
                     case $words[2] in
-                        add|workon)
+                        add|workon|wait|up|refresh|refresh-files)
                             _wt_branches
                             ;;
+                        down)
+                            _arguments \
+                                "(-v --volumes)"{-v,--volumes}"[Remove the project's volumes as well]" \
+                                "1:branch:_wt_branches"
+                            ;;
                         remove)
-                            _wt_worktrees
+                            _arguments \
+                                "(-v --volumes)"{-v,--volumes}"[Remove the compose stack's volumes as well]" \
+                                "1:worktree:_wt_worktrees"
                             ;;
                         *)
                             _values "tree commands" \
                                 "add[Add worktree]" \
                                 "remove[Remove worktree]" \
                                 "list[List worktrees]" \
-                                "workon[Work on worktree]"
+                                "workon[Work on worktree]" \
+                                "wait[Wait for the post-worktree-add script]" \
+                                "up[Bring up the compose stack]" \
+                                "down[Tear down the compose stack]" \
+                                "refresh[Re-fetch pull request head]" \
+                                "refresh-files[Re-apply file rules]"
+                            ;;
+                    esac
+                    ;;
+                open)
+                    _wt_branches
+                    ;;
+                env)
+                    _arguments \
+                        "--write[Write .wt.env instead of printing]" \
+                        "1:branch:_wt_branches"
+                    ;;
+                logs)
+                    _arguments \
+                        "--hook[Only show logs of this hook]:hook:(post-worktree-add work-on)" \
+                        "(-l --list)"{-l,--list}"[List the runs instead of printing the latest log]" \
+                        "1:branch:_wt_branches"
+                    ;;
+                hooks)
+                    case $words[2] in
+                        show|edit)
+                            _values "hooks" "post-worktree-add" "work-on"
+                            ;;
+                        init)
+                            _arguments \
+                                "(-t --template)"{-t,--template}"[Templates to generate the script from]:template:(auto node go python rust gradle docker-compose)" \
+                                "(-f --force)"{-f,--force}"[Replace a script changed by hand]"
+                            ;;
+                        *)
+                            _values "hooks commands" \
+                                "list[List hooks]" \
+                                "show[Show what a hook runs]" \
+                                "edit[Edit a hook script]" \
+                                "init[Generate the post-worktree-add script from templates]" \
+                                "templates[List built-in templates]"
                             ;;
                     esac
                     ;;
+                apply)
+                    _arguments \
+                        "(-f --file)"{-f,--file}"[Workspace file]:file:_files" \
+                        "--dry-run[Report what would change]"
+                    ;;
+                backup)
+                    case $words[2] in
+                        create|restore)
+                            _files
+                            ;;
+                        *)
+                            _values "backup commands" \
+                                "create[Back up to an archive]" \
+                                "restore[Restore from an archive]"
+                            ;;
+                    esac
+                    ;;
+                doctor)
+                    _arguments \
+                        "--fix=-[Repair the problems found]::check:(config-file state-file directories work-on-script missing-repos post-add-scripts hook-permissions worktree-links prunable-worktrees)" \
+                        "--json[Output in JSON format]"
+                    ;;
+                du)
+                    _arguments \
+                        "(--all)*--repo[Repository to measure]:repository:_wt_repos" \
+                        "(--repo)--all[Measure every repository]" \
+                        "--json[Output in JSON format]" \
+                        "--stale-days[Days after which a worktree is stale]:days:"
+                    ;;
+                dedupe)
+                    _arguments \
+                        "(--all)*--repo[Repository to deduplicate]:repository:_wt_repos" \
+                        "(--repo)--all[Deduplicate every repository]" \
+                        "--mode[How copies are linked]:mode:(reflink hardlink)" \
+                        "--dry-run[Report what would be linked]"
+                    ;;
+                migrate-home)
+                    _arguments \
+                        "(--xdg)--to[Move everything into this directory]:directory:_directories" \
+                        "(--to)--xdg[Move everything into the XDG directories]" \
+                        "--dry-run[Show the moves without making them]"
+                    ;;
                 autocomplete)
                     _values "shell types" \
                         "bash[Install bash completion]" \
@@ -91,13 +184,29 @@ _wt_commands() {
         "config:Manage configuration"
         "repo:Manage repositories"
         "tree:Manage worktrees"
+        "status:Show status of all repositories"
+        "sync:Update worktrees from upstream"
+        "du:Show disk usage of worktrees"
+        "dedupe:Link identical files across worktrees"
+        "exec:Run a command across worktrees"
+        "env:Print a worktree's environment"
+        "pr:Manage pull requests"
+        "pick:Pick a worktree interactively"
+        "ui:Open the terminal UI"
+        "open:Open a worktree in an editor"
+        "logs:Show the output of hook scripts"
+        "hooks:Manage hook scripts"
+        "apply:Apply a workspace file"
+        "export:Write the current setup as a workspace file"
+        "backup:Back up and restore the whole setup"
+        "migrate-home:Move config, state, repos and worktrees"
         "autocomplete:Install shell completion"
     )
     _describe "commands" commands
 }
 
 _wt_branches() {
-    local branches=(${(f)"$(wt tree list 2>/dev/null | grep "🔸" | head -20)"})
+    local branches=(${(f)"$(wt tree list --names 2>/dev/null | head -20)"})
     _describe "branches" branches
 }
 
@@ -107,11 +216,8 @@ _wt_repos() {
 }
 
 _wt_worktrees() {
-    local worktrees_json="$(wt tree list --json 2>/dev/null)"
-    if [[ -n "$worktrees_json" ]]; then
-        local worktrees=(${(f)"$(echo $worktrees_json | jq -r '.[]' 2>/dev/null)"})
-        _describe "worktrees" worktrees
-    fi
+    local worktrees=(${(f)"$(wt tree list --names 2>/dev/null)"})
+    _describe "worktrees" worktrees
 }
 
 _wt
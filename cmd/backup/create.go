@@ -0,0 +1,50 @@
+package backup
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/backup"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+var CreateCmd = &cobra.Command{
+	Use:   "create [path]",
+	Short: "Back up config, state, scripts and worktrees to an archive",
+	Long: `Write config.json, state.json, the scripts directory and a manifest of every worktree (repository, branch, HEAD, upstream and dirty status) to a .tar.gz archive.
+
+Commits that have not been pushed are included as git bundles so the worktrees can be restored at their recorded commits. Use --patches to also include uncommitted changes, untracked files included.
+
+The archive defaults to wt-backup-<timestamp>.tar.gz in the current directory. It contains the config as is, API tokens included, so keep it somewhere private.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBackupCreate,
+}
+
+func runBackupCreate(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+	includePatches, _ := cmd.Flags().GetBool("patches")
+
+	archivePath := backup.DefaultArchiveName(time.Now())
+	if len(args) == 1 {
+		archivePath = args[0]
+	}
+
+	m, err := backup.Create(appState, archivePath, backup.CreateOptions{IncludePatches: includePatches})
+	if err != nil {
+		output.Error("Backup failed: %v", err)
+		os.Exit(1)
+	}
+
+	worktrees := 0
+	for _, repo := range m.Repos {
+		worktrees += len(repo.Worktrees)
+	}
+	output.Success("Backed up %d repositories and %d worktrees to: %s", len(m.Repos), worktrees, archivePath)
+	return nil
+}
+
+func init() {
+	CreateCmd.Flags().Bool("patches", false, "Include uncommitted changes of dirty worktrees as patches")
+}
@@ -0,0 +1,33 @@
+package backup
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/backup"
+	"worktree-manager/internal/output"
+)
+
+var RestoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore a backup, re-cloning repositories and recreating worktrees",
+	Long: `Restore config.json, state.json and the scripts from a backup made with 'wt backup create', clone the repositories that are missing, and recreate each worktree at its recorded commit with its upstream. Patches of uncommitted changes in the backup are applied to the recreated worktrees.
+
+Worktrees that already exist are left alone. Restoring over an existing setup needs --force.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBackupRestore,
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+
+	if _, err := backup.Restore(args[0], backup.RestoreOptions{Force: force}); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func init() {
+	RestoreCmd.Flags().BoolP("force", "f", false, "Replace an existing config, state and scripts")
+}
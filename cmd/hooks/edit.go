@@ -0,0 +1,71 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/hooks"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+var EditCmd = &cobra.Command{
+	Use:   "edit <hook>",
+	Short: "Edit a hook script",
+	Long:  `Open the script of a hook in the configured editor, creating it first if it does not exist. The post-worktree-add script belongs to the active repository; the work-on script is shared by all repositories.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHooksEdit,
+}
+
+func runHooksEdit(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	hook := args[0]
+	if err := hooks.ValidateName(hook); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	// Only the post-worktree-add script belongs to a repository
+	var repoAlias string
+	if hook == consts.HookPostWorktreeAdd {
+		activeRepo, err := appState.GetActiveRepo()
+		if err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		repoAlias = activeRepo.Alias
+	}
+
+	scriptPath := hooks.ScriptPath(hook, repoAlias)
+	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
+		if err := hooks.WriteScript(scriptPath, hooks.DefaultScript(hook, repoAlias)); err != nil {
+			output.Error("Failed to create %s: %v", scriptPath, err)
+			os.Exit(1)
+		}
+		output.Info("Created %s", scriptPath)
+	}
+
+	editor := strings.Fields(cfg.ConfigEditor)
+	if len(editor) == 0 {
+		editor = strings.Fields(consts.GetConfigDefaults().ConfigEditor)
+	}
+
+	editorCmd := exec.Command(editor[0], append(editor[1:], scriptPath)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+
+	if err := editorCmd.Run(); err != nil {
+		output.Error("Failed to run editor: %v", err)
+		os.Exit(1)
+	}
+
+	output.Success("Hook script edited: %s", scriptPath)
+	return nil
+}
@@ -0,0 +1,71 @@
+package hooks
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/hooks"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+var InitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate the post-worktree-add script from templates",
+	Long: `Generate the post-worktree-add script of the active repository from built-in templates, such as node, go, python, rust, gradle and docker-compose. Several templates can be combined with commas, e.g. --template go,docker-compose.
+
+By default the templates are chosen from the files in the repository: package.json selects node, go.mod go, pyproject.toml python and so on. Use 'wt hooks templates' to see them all. A script that was changed by hand is only replaced with --force.`,
+	Args: cobra.NoArgs,
+	RunE: runHooksInit,
+}
+
+func init() {
+	InitCmd.Flags().StringP("template", "t", hooks.TemplateAuto, "Templates to generate the script from, comma separated, or 'auto' to detect them")
+	InitCmd.Flags().BoolP("force", "f", false, "Replace a script that was changed by hand")
+}
+
+func runHooksInit(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	template, _ := cmd.Flags().GetString("template")
+	force, _ := cmd.Flags().GetBool("force")
+
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	names, err := hooks.ResolveTemplates(template, activeRepo.Dir)
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	if len(names) == 0 {
+		output.Error("No template matches the files in %s\n\n💡 Choose one with --template; 'wt hooks templates' lists them", activeRepo.Dir)
+		os.Exit(1)
+	}
+
+	hook, err := hooks.Inspect(cfg, activeRepo, consts.HookPostWorktreeAdd)
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	if hook.State == hooks.ScriptCustom && !force {
+		output.Error("%s was changed by hand\n\n💡 Use --force to replace it, or 'wt hooks edit %s' to change it", hook.Script, consts.HookPostWorktreeAdd)
+		os.Exit(1)
+	}
+
+	if err := hooks.WriteScript(hook.Script, hooks.RenderPostWorktreeAdd(activeRepo.Alias, names)); err != nil {
+		output.Error("Failed to write %s: %v", hook.Script, err)
+		os.Exit(1)
+	}
+
+	output.Success("Generated the post-worktree-add script of '%s' from %s: %s", activeRepo.Alias, strings.Join(names, ", "), hook.Script)
+	output.Hint("Use 'wt hooks edit %s' to adjust it", consts.HookPostWorktreeAdd)
+	return nil
+}
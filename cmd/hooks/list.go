@@ -0,0 +1,58 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/hooks"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the hooks of the active repository",
+	Long:  `List the hooks wt runs for the active repository, with their scripts, the commands declared for them in the config and the repository's state, and their timeouts. A script is empty when it has only comments, and a template script when it is unchanged since 'wt hooks init' generated it.`,
+	Args:  cobra.NoArgs,
+	RunE:  runHooksList,
+}
+
+func runHooksList(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	rows := make([][]string, 0, len(hooks.Names))
+	for _, name := range hooks.Names {
+		hook, err := hooks.Inspect(cfg, activeRepo, name)
+		if err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		rows = append(rows, []string{hook.Name, describeScriptState(hook), fmt.Sprint(len(hook.Commands)), describeTimeout(hook), hook.Script})
+	}
+	output.Table([]string{"HOOK", "SCRIPT", "COMMANDS", "TIMEOUT", "PATH"}, rows)
+	return nil
+}
+
+func describeScriptState(hook hooks.Hook) string {
+	if hook.State == hooks.ScriptTemplate {
+		return fmt.Sprintf("%s (%s)", hook.State, strings.Join(hook.Templates, ", "))
+	}
+	return hook.State
+}
+
+func describeTimeout(hook hooks.Hook) string {
+	if hook.Timeout == 0 {
+		return "none"
+	}
+	return hook.Timeout.String()
+}
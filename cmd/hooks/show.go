@@ -0,0 +1,93 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/hooks"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+var ShowCmd = &cobra.Command{
+	Use:   "show <hook>",
+	Short: "Show what a hook runs",
+	Long:  `Print the commands declared for a hook of the active repository, in the order they run, followed by its script. Hooks are post-worktree-add and work-on.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHooksShow,
+}
+
+func runHooksShow(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	hook, err := hooks.Inspect(cfg, activeRepo, args[0])
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	if len(hook.Commands) > 0 {
+		output.Info("Commands:")
+		for _, command := range hook.Commands {
+			fmt.Fprintf(output.Stdout(), "  %s\n", describeCommand(command))
+		}
+	}
+
+	if hook.State == hooks.ScriptMissing {
+		output.Info("Script: %s (missing)", hook.Script)
+		return nil
+	}
+	output.Info("Script: %s (%s)", hook.Script, describeScriptState(hook))
+
+	file, err := os.Open(hook.Script)
+	if err != nil {
+		output.Error("Failed to open %s: %v", hook.Script, err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(output.Stdout(), file); err != nil {
+		output.Error("Failed to read %s: %v", hook.Script, err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+// describeCommand renders a hook command on one line, with where, how and when it runs
+func describeCommand(command state.HookCommand) string {
+	parts := []string{command.Run}
+	if command.Dir != "" {
+		parts = append(parts, "in "+command.Dir)
+	}
+	for _, name := range slices.Sorted(maps.Keys(command.Env)) {
+		parts = append(parts, fmt.Sprintf("with %s=%s", name, command.Env[name]))
+	}
+	if condition := command.If; condition != nil {
+		if condition.Exists != "" {
+			parts = append(parts, "if "+condition.Exists+" exists")
+		}
+		if condition.NotExists != "" {
+			parts = append(parts, "if "+condition.NotExists+" does not exist")
+		}
+		if condition.Branch != "" {
+			parts = append(parts, "on branches "+condition.Branch)
+		}
+		if condition.Operation != "" {
+			parts = append(parts, "on "+condition.Operation)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,38 @@
+package hooks
+
+import (
+	"slices"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/hooks"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+var TemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List the built-in post-worktree-add templates",
+	Long:  `List the templates 'wt hooks init' and 'wt repo clone --template' generate post-worktree-add scripts from. Templates detected from the files in the active repository are marked.`,
+	Args:  cobra.NoArgs,
+	RunE:  runHooksTemplates,
+}
+
+func runHooksTemplates(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	var detected []string
+	if activeRepo, err := appState.GetActiveRepo(); err == nil {
+		detected = hooks.Detect(activeRepo.Dir)
+	}
+
+	var rows [][]string
+	for _, template := range hooks.Templates() {
+		mark := ""
+		if slices.Contains(detected, template.Name) {
+			mark = "✓"
+		}
+		rows = append(rows, []string{template.Name, mark, template.Description})
+	}
+	output.Table([]string{"TEMPLATE", "DETECTED", "DESCRIPTION"}, rows)
+	return nil
+}
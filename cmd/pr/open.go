@@ -0,0 +1,50 @@
+package pr
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var OpenCmd = &cobra.Command{
+	Use:   "open <branch>",
+	Short: "Open a pull request for a worktree's branch",
+	Long:  `Push the branch of a worktree to origin and open a pull request (merge request on GitLab) for it. The title defaults to the last commit subject and the base to the detected base branch.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOpen,
+}
+
+func init() {
+	OpenCmd.Flags().StringP("title", "t", "", "Pull request title (defaults to the last commit subject)")
+	OpenCmd.Flags().StringP("body", "b", "", "Pull request description")
+	OpenCmd.Flags().String("base", "", "Branch to merge into (defaults to main or master)")
+	OpenCmd.Flags().BoolP("draft", "d", false, "Open the pull request as a draft")
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	branch := args[0]
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	title, _ := cmd.Flags().GetString("title")
+	body, _ := cmd.Flags().GetString("body")
+	base, _ := cmd.Flags().GetString("base")
+	draft, _ := cmd.Flags().GetBool("draft")
+
+	opts := worktree.PullRequestOptions{
+		Title: title,
+		Body:  body,
+		Base:  base,
+		Draft: draft,
+	}
+
+	if err := worktree.OpenPullRequest(cfg, appState, branch, opts); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
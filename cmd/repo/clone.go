@@ -2,12 +2,13 @@ package repo
 
 import (
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"worktree-manager/internal/consts"
 	gitutils "worktree-manager/internal/git"
+	"worktree-manager/internal/hooks"
 	"worktree-manager/internal/output"
 	"worktree-manager/internal/state"
 )
@@ -15,9 +16,11 @@ import (
 var CloneCmd = &cobra.Command{
 	Use:   "clone <url>",
 	Short: "Clone a repository for use with worktrees",
-	Long:  `Clone a repository as a bare repository for use with worktrees. The repository alias will be automatically derived from the repository name, or you can specify a custom alias using the --alias flag.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runRepoClone,
+	Long: `Clone a repository as a bare repository for use with worktrees. The repository alias will be automatically derived from the repository name, or you can specify a custom alias using the --alias flag.
+
+Use --template to generate the post-worktree-add script from built-in templates, such as node or go,docker-compose, or 'auto' to choose them from the files in the repository. 'wt hooks templates' lists them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRepoClone,
 }
 
 func runRepoClone(cmd *cobra.Command, args []string) error {
@@ -29,6 +32,13 @@ func runRepoClone(cmd *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
+	template, _ := cmd.Flags().GetString("template")
+	// Unknown templates are reported before anything is cloned
+	if err := hooks.ValidateTemplates(template); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
 	var alias string
 	if customAlias != "" {
 		alias = customAlias
@@ -60,11 +70,7 @@ func runRepoClone(cmd *cobra.Command, args []string) error {
 
 	output.Progress("Cloning repository: %s", url)
 
-	gitCmd := exec.Command("git", "clone", url, repoDir)
-	gitCmd.Stdout = os.Stdout
-	gitCmd.Stderr = os.Stderr
-
-	if err := gitCmd.Run(); err != nil {
+	if err := gitutils.Clone(url, repoDir, nil); err != nil {
 		output.Error("Failed to clone repository: %v", err)
 		os.Exit(1)
 	}
@@ -80,9 +86,38 @@ func runRepoClone(cmd *cobra.Command, args []string) error {
 	}
 
 	output.Success("Repository '%s' cloned and configured with alias '%s' at: %s", url, alias, repoDir)
+
+	if template == "" {
+		if detected := hooks.Detect(repoDir); len(detected) > 0 {
+			output.Hint("Detected %s; use 'wt hooks init' to generate the post-worktree-add script from templates", strings.Join(detected, ", "))
+		}
+		return nil
+	}
+	applyTemplates(alias, repoDir, template)
 	return nil
 }
 
+// applyTemplates generates the post-worktree-add script of a freshly cloned repository from templates
+func applyTemplates(alias, repoDir, template string) {
+	names, err := hooks.ResolveTemplates(template, repoDir)
+	if err != nil {
+		output.Warning("%v", err)
+		return
+	}
+	if len(names) == 0 {
+		output.Warning("No template matches the files in %s; the post-worktree-add script is left empty", repoDir)
+		return
+	}
+
+	scriptPath := hooks.ScriptPath(consts.HookPostWorktreeAdd, alias)
+	if err := hooks.WriteScript(scriptPath, hooks.RenderPostWorktreeAdd(alias, names)); err != nil {
+		output.Warning("Failed to generate the post-worktree-add script: %v", err)
+		return
+	}
+	output.Success("Generated the post-worktree-add script from %s: %s", strings.Join(names, ", "), scriptPath)
+}
+
 func init() {
 	CloneCmd.Flags().StringP("alias", "a", "", "Custom alias for the repository (defaults to repository name)")
+	CloneCmd.Flags().StringP("template", "t", "", "Generate the post-worktree-add script from these templates, comma separated, or 'auto'")
 }
@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var EnvCmd = &cobra.Command{
+	Use:   "env [<name>=<value>...]",
+	Short: "Show or set the variables of the active repository's worktrees",
+	Long: `Show or set variables every worktree of the active repository is given, in hooks, 'wt exec', 'wt env' and .wt.env files. Values are templates rendered for each worktree with .Alias, .Branch, .IssueKey, .Index and .Ports, and the slug, lower and upper functions.
+
+For example 'wt repo env DATABASE_URL=postgres://localhost:{{.Ports.db}}/app_{{.Branch | slug}}' gives every worktree a database of its own.`,
+	RunE: runRepoEnv,
+}
+
+func init() {
+	EnvCmd.Flags().StringSlice("unset", nil, "Remove these variables")
+}
+
+func runRepoEnv(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	unset, _ := cmd.Flags().GetStringSlice("unset")
+
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	if len(args) > 0 || len(unset) > 0 {
+		env := maps.Clone(activeRepo.Env)
+		if env == nil {
+			env = state.EnvTemplates{}
+		}
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				output.Error("Invalid variable '%s'\n\n💡 Use <name>=<value>, e.g. DB_NAME=app_{{.Branch | slug}}", arg)
+				os.Exit(1)
+			}
+			env[name] = value
+		}
+		for _, name := range unset {
+			delete(env, name)
+		}
+
+		// Templates are checked before anything is saved
+		if err := worktree.ValidateRepoEnv(&state.Repo{Env: env}); err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		if err := appState.SetEnvTemplates(activeRepo.Alias, env); err != nil {
+			output.Error("Failed to set variables: %v", err)
+			os.Exit(1)
+		}
+		activeRepo.Env = env
+		output.Success("Variables of '%s' updated", activeRepo.Alias)
+		worktree.RefreshRepoEnvFiles(appState, activeRepo)
+	}
+
+	if len(activeRepo.Env) == 0 {
+		output.Info("No variables set for '%s'", activeRepo.Alias)
+		output.Hint("Use 'wt repo env <name>=<value>' to add one")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(activeRepo.Env))
+	for _, name := range slices.Sorted(maps.Keys(activeRepo.Env)) {
+		rows = append(rows, []string{name, activeRepo.Env[name]})
+	}
+	output.Table([]string{"NAME", "VALUE"}, rows)
+	return nil
+}
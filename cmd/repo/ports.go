@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var PortsCmd = &cobra.Command{
+	Use:   "ports [<name>=<base-port>...]",
+	Short: "Show or set the port slots of the active repository",
+	Long: `Show or set the named ports the services of the active repository listen on, so that several worktrees can run them at once. Every worktree is given an index from 1 that no other worktree of the repository holds, and is passed each port as WT_PORT_<NAME>, its base port plus its index, along with WT_WORKTREE_INDEX and a docker compose project name of its own as WT_COMPOSE_PROJECT. Hooks and 'wt exec' receive them; 'wt tree list' shows them.
+
+For example 'wt repo ports web=3000 db=5500' gives the worktree with index 2 WT_PORT_WEB=3002 and WT_PORT_DB=5502. Keep base ports further apart than the number of worktrees you keep at once.`,
+	RunE: runRepoPorts,
+}
+
+func init() {
+	PortsCmd.Flags().StringSlice("unset", nil, "Remove these port slots")
+}
+
+func runRepoPorts(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	unset, _ := cmd.Flags().GetStringSlice("unset")
+
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	if len(args) > 0 || len(unset) > 0 {
+		ports := maps.Clone(activeRepo.Ports)
+		if ports == nil {
+			ports = state.PortSlots{}
+		}
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg, "=")
+			port, err := strconv.Atoi(value)
+			if !ok || err != nil {
+				output.Error("Invalid port slot '%s'\n\n💡 Use <name>=<base-port>, e.g. web=3000", arg)
+				os.Exit(1)
+			}
+			ports[name] = port
+		}
+		for _, name := range unset {
+			delete(ports, name)
+		}
+
+		if err := appState.SetPortSlots(activeRepo.Alias, ports); err != nil {
+			output.Error("Failed to set port slots: %v", err)
+			os.Exit(1)
+		}
+		activeRepo.Ports = ports
+		output.Success("Port slots of '%s' updated", activeRepo.Alias)
+		worktree.AllocateWorktreeIndexes(appState, activeRepo)
+		worktree.RefreshRepoEnvFiles(appState, activeRepo)
+		for _, conflict := range ports.Conflicts(max(activeRepo.MaxWorktreeIndex(), len(activeRepo.Worktrees))) {
+			output.Warning("Ports %s hand out the same port to different worktrees", conflict)
+		}
+	}
+
+	if len(activeRepo.Ports) == 0 {
+		output.Info("No port slots set for '%s'", activeRepo.Alias)
+		output.Hint("Use 'wt repo ports <name>=<base-port>' to add one")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(activeRepo.Ports))
+	for _, name := range slices.Sorted(maps.Keys(activeRepo.Ports)) {
+		rows = append(rows, []string{name, fmt.Sprint(activeRepo.Ports[name]), state.PortEnvName(name)})
+	}
+	output.Table([]string{"NAME", "BASE", "VARIABLE"}, rows)
+	return nil
+}
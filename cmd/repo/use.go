@@ -1,25 +1,45 @@
 package repo
 
 import (
+	"errors"
 	"os"
 
 	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
 	"worktree-manager/internal/output"
+	"worktree-manager/internal/picker"
 	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
 )
 
 var UseCmd = &cobra.Command{
-	Use:   "use <alias>",
+	Use:   "use [alias]",
 	Short: "Set the active repository for tree commands",
-	Long:  `Set the specified repository as the active repository for tree commands.`,
-	Args:  cobra.ExactArgs(1),
+	Long:  `Set the specified repository as the active repository for tree commands. Without an alias, pick the repository interactively.`,
+	Args:  cobra.MaximumNArgs(1),
 	RunE:  runRepoUse,
 }
 
 func runRepoUse(cmd *cobra.Command, args []string) error {
-	alias := args[0]
+	cfg := config.GetConfigFromContext(cmd.Context())
 	appState := state.GetStateFromContext(cmd.Context())
 
+	var alias string
+	if len(args) > 0 {
+		alias = args[0]
+	} else {
+		picked, err := worktree.PickRepo(cfg, appState)
+		if errors.Is(err, picker.ErrCancelled) {
+			output.Warning("No repository selected")
+			return nil
+		}
+		if err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		alias = picked
+	}
+
 	repo, err := appState.FindRepoByAlias(alias)
 	if err != nil {
 		output.Error("%v", err)
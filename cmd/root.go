@@ -4,9 +4,12 @@ import (
 	"context"
 	"github.com/spf13/cobra"
 	"os"
+	"path/filepath"
+	"worktree-manager/cmd/backup"
 	"worktree-manager/cmd/root"
 	"worktree-manager/internal/config"
 	"worktree-manager/internal/consts"
+	"worktree-manager/internal/git"
 	"worktree-manager/internal/output"
 	"worktree-manager/internal/state"
 )
@@ -18,7 +21,21 @@ var rootCmd = &cobra.Command{
 	Long:    `A command-line tool for managing Git worktrees efficiently.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 
-		if cmd.Name() == "init" || cmd.Name() == "doctor" || cmd.Name() == "version" {
+		// --home is passed on through the environment so hooks and nested wt calls use the same root
+		if home, _ := cmd.Flags().GetString("home"); home != "" {
+			if abs, err := filepath.Abs(home); err == nil {
+				home = abs
+			}
+			os.Setenv(consts.HomeEnvVar, home)
+		}
+		if _, err := consts.ResolveLayout(); err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+
+		// Commands that work without a config, matched by identity since 'wt hooks init' needs one
+		switch cmd {
+		case root.InitCmd, root.DoctorCmd, root.VersionCmd, root.ApplyCmd, backup.RestoreCmd:
 			return nil
 		}
 
@@ -29,6 +46,11 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		if err := git.SetDefaultBackend(cfg.GetGitBackend()); err != nil {
+			output.Error("Invalid git-backend in config: %v", err)
+			os.Exit(1)
+		}
+
 		appState, err := state.Load()
 		if err != nil {
 			output.Error("Failed to load state: %v", err)
@@ -52,11 +74,29 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.PersistentFlags().String("home", "", "Keep config, state, repos and worktrees in this directory (overrides WT_HOME)")
+
 	rootCmd.AddCommand(root.InitCmd)
 	rootCmd.AddCommand(root.DoctorCmd)
 	rootCmd.AddCommand(root.TreeCmd)
 	rootCmd.AddCommand(root.ConfigCmd)
 	rootCmd.AddCommand(root.RepoCmd)
+	rootCmd.AddCommand(root.StatusCmd)
+	rootCmd.AddCommand(root.SyncCmd)
+	rootCmd.AddCommand(root.DuCmd)
+	rootCmd.AddCommand(root.DedupeCmd)
+	rootCmd.AddCommand(root.ExecCmd)
+	rootCmd.AddCommand(root.EnvCmd)
+	rootCmd.AddCommand(root.PrCmd)
+	rootCmd.AddCommand(root.PickCmd)
+	rootCmd.AddCommand(root.UiCmd)
+	rootCmd.AddCommand(root.OpenCmd)
+	rootCmd.AddCommand(root.LogsCmd)
+	rootCmd.AddCommand(root.HooksCmd)
+	rootCmd.AddCommand(root.ApplyCmd)
+	rootCmd.AddCommand(root.ExportCmd)
+	rootCmd.AddCommand(root.BackupCmd)
+	rootCmd.AddCommand(root.MigrateHomeCmd)
 	rootCmd.AddCommand(root.AutocompleteCmd)
 	rootCmd.AddCommand(root.VersionCmd)
 }
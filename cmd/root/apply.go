@@ -0,0 +1,96 @@
+package root
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/manifest"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+var ApplyCmd = &cobra.Command{
+	Use:   "apply -f <workspace.yaml>",
+	Short: "Bootstrap repositories, hooks and config from a workspace file",
+	Long: `Apply a workspace file, in YAML or JSON, that declares repositories (url, alias, dir, base branch, clone args), their hooks, file rules, port slots and env, and config values.
+
+Missing repositories are cloned, existing clones are registered, scripts are written and config values are set. Applying the same file again changes nothing. Differences apply does not fix on its own, such as a repository with another origin or one missing from the file, are reported as drift.
+
+wt init is run first on a machine that has not been initialized. Use 'wt export' to write a workspace file from the current setup.`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	m, err := manifest.Load(file)
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	cfg, appState, err := loadOrInit(dryRun)
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	if _, err := manifest.Apply(cfg, appState, m, manifest.ApplyOptions{DryRun: dryRun}); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+// loadOrInit loads the config and state, initializing them first on a fresh machine; a dry run only pretends to
+func loadOrInit(dryRun bool) (*config.Config, *state.State, error) {
+	if !config.CheckConfigExists() || !state.StateExists() {
+		if dryRun {
+			output.Info("wt is not initialized yet, comparing against a fresh setup")
+			defaults := consts.GetConfigDefaults()
+			cfg := &config.Config{
+				ConfigEditor:            defaults.ConfigEditor,
+				AutomaticWorkOnAfterAdd: defaults.AutomaticWorkOnAfterAdd,
+				SyncStrategy:            defaults.SyncStrategy,
+				BranchTemplate:          defaults.BranchTemplate,
+				Picker:                  defaults.Picker,
+			}
+			return cfg, &state.State{}, nil
+		}
+
+		output.Progress("Initializing worktree-manager...")
+		if !config.CheckConfigExists() {
+			if err := config.CreateDefault(); err != nil {
+				return nil, nil, err
+			}
+		}
+		if !state.StateExists() {
+			if err := state.CreateDefault(); err != nil {
+				return nil, nil, err
+			}
+			if err := createScripts(); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	appState, err := state.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, appState, nil
+}
+
+func init() {
+	ApplyCmd.Flags().StringP("file", "f", "", "Workspace file to apply")
+	ApplyCmd.Flags().Bool("dry-run", false, "Report what would change without changing anything")
+	ApplyCmd.MarkFlagRequired("file")
+}
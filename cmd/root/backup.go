@@ -0,0 +1,17 @@
+package root
+
+import (
+	"github.com/spf13/cobra"
+	"worktree-manager/cmd/backup"
+)
+
+var BackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up and restore the whole setup",
+	Long:  `Commands for moving the worktree-manager setup, including every worktree, to another machine.`,
+}
+
+func init() {
+	BackupCmd.AddCommand(backup.CreateCmd)
+	BackupCmd.AddCommand(backup.RestoreCmd)
+}
@@ -0,0 +1,56 @@
+package root
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var DedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Link identical files across worktrees to save disk space",
+	Long: `Find files with the same content in the directories listed under 'dedupe-dirs' in the config, such as node_modules or .venv, across the worktrees and main checkout of a repository, and replace the copies with links to one of them. Nothing is deduplicated until 'dedupe-dirs' is set. Defaults to the active repository.
+
+By default copies are replaced with copy-on-write clones, which share their data on disk but remain separate files, so changing one leaves the others alone; this needs a filesystem such as btrfs, XFS or APFS. --mode hardlink works on any filesystem but makes the copies one file: a change made in one worktree shows up in all of them. Only files with the same permissions are hard linked.
+
+Every link is verified against the hash of the file it replaces before taking its place, and files that change in the meantime are left alone. Use --dry-run to see what would be freed first.`,
+	Args: cobra.NoArgs,
+	RunE: runDedupe,
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	repos, _ := cmd.Flags().GetStringSlice("repo")
+	all, _ := cmd.Flags().GetBool("all")
+	mode, _ := cmd.Flags().GetString("mode")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	opts := worktree.DedupeOptions{
+		Repos:  repos,
+		All:    all,
+		Mode:   mode,
+		DryRun: dryRun,
+	}
+
+	if err := worktree.DedupeWorktrees(cfg, appState, opts); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func init() {
+	DedupeCmd.Flags().StringSlice("repo", nil, "Repository aliases to deduplicate (defaults to the active repository)")
+	DedupeCmd.Flags().Bool("all", false, "Deduplicate every managed repository")
+	DedupeCmd.Flags().String("mode", worktree.DedupeReflink, fmt.Sprintf("How copies are linked, one of: %s", strings.Join(worktree.DedupeModes, ", ")))
+	DedupeCmd.Flags().Bool("dry-run", false, "Report what would be linked without changing anything")
+	DedupeCmd.MarkFlagsMutuallyExclusive("repo", "all")
+}
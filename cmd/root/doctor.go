@@ -1,246 +1,106 @@
 package root
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
-	"worktree-manager/internal/git"
 
 	"github.com/spf13/cobra"
-	"worktree-manager/internal/config"
-	"worktree-manager/internal/consts"
+	"worktree-manager/internal/doctor"
 	"worktree-manager/internal/output"
-	"worktree-manager/internal/state"
 )
 
 var DoctorCmd = &cobra.Command{
 	Use:   "doctor",
 	Short: "Check the health of the worktree-manager configuration",
-	Long:  `Validate the configuration and check for any issues with repositories or scripts.`,
-	RunE:  runDoctor,
+	Long: `Validate the config and state files, the directories and hook scripts, and every repository and its worktrees. Exits with an error only when a problem of error severity is left; warnings are reported but do not fail.
+
+Use --fix to repair what can be repaired: missing directories and scripts are created, hook scripts made executable, worktrees moved by hand relinked with 'git worktree repair', worktrees deleted by hand pruned with 'git worktree prune', and repositories whose directory is gone forgotten. --fix=<check> repairs the problems of one check only, and can be repeated.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
-	output.Progress("Running worktree-manager health check...")
-
-	checkConfig()
-	appState := checkState()
-
-	errors := validateConfigurationHealth(appState)
+	fixes, _ := cmd.Flags().GetStringSlice("fix")
+	jsonFormat, _ := cmd.Flags().GetBool("json")
 
-	checkFolders()
-	checkWorkOnScript()
-	checkRepos(appState)
-
-	if len(errors) == 0 {
-		output.Success("All checks passed! Your worktree-manager is ready to use.")
-	} else {
-		output.Warning("Some issues were found. Please address them before using worktree-manager.")
-		for _, err := range errors {
-			output.Error(err.Error())
-		}
+	if err := doctor.ValidateFixes(fixes); err != nil {
+		output.Error("%v", err)
 		os.Exit(1)
 	}
 
-	return nil
-}
+	var report doctor.Report
+	if jsonFormat {
+		// What the fixes print would get in the way of the JSON
+		restore := output.Redirect(os.Stderr, os.Stderr)
+		report = doctor.Run(&doctor.Env{}, fixes)
+		restore()
 
-// checkConfig verifies the config file exists and can be loaded
-func checkConfig() *config.Config {
-	if !config.CheckConfigExists() {
-		output.Error("Config file does not exist. Run 'wt init' to create it.")
-		os.Exit(1)
-	}
-	output.Success("Config file exists")
-
-	cfg, err := config.Load()
-	if err != nil {
-		output.Error("Failed to load config: %v", err)
-		os.Exit(1)
-	}
-	output.Success("Config file is valid JSON")
-
-	if cfg.ConfigEditor != "" {
-		output.Success("Config editor set to: %s", cfg.ConfigEditor)
+		jsonOutput, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			output.Error("Failed to marshal JSON: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(jsonOutput))
 	} else {
-		output.Warning("No config editor specified, will use 'vi' as default")
-	}
-
-	return cfg
-}
-
-// checkState verifies the state file exists and can be loaded
-func checkState() *state.State {
-	if !state.StateExists() {
-		output.Error("State file does not exist. Run 'wt init' to create it.")
-		os.Exit(1)
+		output.Progress("Running worktree-manager health check...")
+		report = doctor.Run(&doctor.Env{}, fixes)
+		printDoctorReport(report, len(fixes) > 0)
 	}
-	output.Success("State file exists")
 
-	appState, err := state.Load()
-	if err != nil {
-		output.Error("Failed to load state: %v", err)
+	if report.Errors > 0 {
 		os.Exit(1)
 	}
-	output.Success("State file is valid JSON")
-
-	return appState
-}
-
-// checkFolders verifies the existence of required directories
-func checkFolders() {
-	paths := consts.GetDirectoryPaths()
-	gitReposDir := paths.DefaultGitReposDir
-	if _, err := os.Stat(gitReposDir); os.IsNotExist(err) {
-		output.Warning("Git repos directory does not exist: %s", gitReposDir)
-		output.Hint("Run 'mkdir -p %s' to create it", gitReposDir)
-	} else {
-		output.Success("Git repos directory exists: %s", gitReposDir)
-	}
-
-	worktreesDir := paths.DefaultWorktreesDir
-	if _, err := os.Stat(worktreesDir); os.IsNotExist(err) {
-		output.Warning("Worktrees directory does not exist: %s", worktreesDir)
-		output.Hint("Run 'mkdir -p %s' to create it", worktreesDir)
-	} else {
-		output.Success("Worktrees directory exists: %s", worktreesDir)
-	}
-}
-
-// checkWorkOnScript verifies the work-on script status
-func checkWorkOnScript() {
-	workOnScript := consts.GetFilePaths().WorkOnScript
-	if _, err := os.Stat(workOnScript); os.IsNotExist(err) {
-		output.Warning("Work-on script does not exist: %s", workOnScript)
-		output.Hint("It will be created when you first use 'wt tree workon'")
-	} else {
-		output.Success("Work-on script exists: %s", workOnScript)
-	}
+	return nil
 }
 
-// checkRepos verifies all configured repositories and their scripts
-func checkRepos(appState *state.State) {
-	output.Info("Checking %d configured repositories:", len(appState.Repos))
-
-	worktreesDir := consts.GetDirectoryPaths().DefaultWorktreesDir
-
-	for _, repo := range appState.Repos {
-		output.Info("🔍 Checking repository '%s':", repo.Alias)
-
-		if _, err := os.Stat(repo.Dir); os.IsNotExist(err) {
-			output.Error("  Repository directory does not exist: %s", repo.Dir)
-			output.Hint("     Run 'wt repo clone <url>' to clone it again")
-		} else {
-			output.Success("  Repository directory exists: %s", repo.Dir)
+// printDoctorReport shows each check that passed, and each problem with what can be done about it
+func printDoctorReport(report doctor.Report, fixing bool) {
+	for _, result := range report.Checks {
+		if len(result.Findings) == 0 {
+			output.Success("%s", result.Description)
+			continue
+		}
 
-			gitDir := filepath.Join(repo.Dir, ".git")
-			if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-				output.Error("  Directory is not a git repository (no .git directory)")
-			} else {
-				output.Success("  Valid git repository")
+		for _, finding := range result.Findings {
+			switch {
+			case finding.Fixed:
+				output.Success("Fixed: %s", finding.Message)
+				continue
+			case finding.Severity == doctor.SeverityError:
+				output.Error("%s", finding.Message)
+			default:
+				output.Warning("%s", finding.Message)
 			}
 
-			repoWorktreesDir := filepath.Join(worktreesDir, repo.Alias)
-			if _, err := os.Stat(repoWorktreesDir); os.IsNotExist(err) {
-				output.Warning("  Worktrees directory does not exist: %s", repoWorktreesDir)
-				output.Hint("     It will be created when you add your first worktree")
-			} else {
-				output.Success("  Worktrees directory exists: %s", repoWorktreesDir)
+			if finding.FixError != "" {
+				output.Error("  Fix failed: %s", finding.FixError)
+			}
+			if finding.Hint != "" {
+				output.Hint("  %s", finding.Hint)
+			}
+			if finding.Fixable && finding.FixError == "" {
+				output.Hint("  Repair it with 'wt doctor --fix=%s'", finding.Check)
 			}
-		}
-
-		scriptPath := consts.GetFilePaths().PostWorktreeAddScript(repo.Alias)
-		if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-			output.Warning("  Post-worktree-add script does not exist: %s", scriptPath)
-			output.Hint("     It will be created when you first add a worktree")
-		} else {
-			output.Success("  Post-worktree-add script exists: %s", scriptPath)
-		}
-	}
-}
-
-func validateGitRepository(path string) error {
-	if !git.IsGitRepository(path) {
-		return fmt.Errorf("directory is not a git repository: %s", path)
-	}
-	return nil
-}
-
-func validateWorktreeStructure(repoPath string) error {
-	if err := validateGitRepository(repoPath); err != nil {
-		return err
-	}
-
-	worktreesDir := filepath.Join(repoPath, "worktrees")
-	if stat, err := os.Stat(worktreesDir); err == nil {
-		if !stat.IsDir() {
-			return fmt.Errorf("worktrees path exists but is not a directory: %s", worktreesDir)
 		}
 	}
 
-	return nil
-}
-
-func validateScriptPath(scriptPath, repoDir string) error {
-	if scriptPath == "" {
-		return nil
-	}
-
-	if !filepath.IsAbs(scriptPath) {
-		scriptPath = filepath.Join(repoDir, scriptPath)
-	}
-
-	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-		return fmt.Errorf("script does not exist: %s", scriptPath)
-	}
-
-	return nil
-}
-
-func validateRepositoryConfig(repo *state.Repo) error {
-	if repo.Alias == "" {
-		return fmt.Errorf("repository alias cannot be empty")
-	}
-
-	if repo.Dir == "" {
-		return fmt.Errorf("repository directory cannot be empty")
-	}
-
-	if _, err := os.Stat(repo.Dir); os.IsNotExist(err) {
-		return fmt.Errorf("repository directory does not exist: %s", repo.Dir)
-	}
-
-	if err := validateWorktreeStructure(repo.Dir); err != nil {
-		return fmt.Errorf("invalid repository structure: %w", err)
+	fmt.Println()
+	switch {
+	case report.Errors == 0 && report.Warnings == 0:
+		output.Success("All checks passed! Your worktree-manager is ready to use.")
+	case report.Errors == 0:
+		output.Warning("%d warnings found; worktree-manager can be used, but some features may not work as expected.", report.Warnings)
+	default:
+		output.Error("%d errors and %d warnings found. Please address the errors before using worktree-manager.", report.Errors, report.Warnings)
 	}
-
-	// Validate post-worktree-add script exists
-	scriptPath := consts.GetFilePaths().PostWorktreeAddScript(repo.Alias)
-	if err := validateScriptPath(scriptPath, repo.Dir); err != nil {
-		return fmt.Errorf("invalid post-worktree-add script: %w", err)
+	if report.Fixable > 0 && !fixing {
+		output.Hint("Run 'wt doctor --fix' to repair %d of them", report.Fixable)
 	}
-
-	return nil
 }
 
-func validateConfigurationHealth(appState *state.State) []error {
-	var errors []error
-
-	defaultReposDir := consts.GetDirectoryPaths().DefaultGitReposDir
-	if _, err := os.Stat(defaultReposDir); os.IsNotExist(err) {
-		errors = append(errors, fmt.Errorf("git repos directory does not exist: %s", defaultReposDir))
-	}
-
-	workOnScriptPath := consts.GetFilePaths().WorkOnScript
-	if _, err := os.Stat(workOnScriptPath); os.IsNotExist(err) {
-		errors = append(errors, fmt.Errorf("work-on script does not exist: %s", workOnScriptPath))
-	}
-
-	for _, repo := range appState.Repos {
-		if err := validateRepositoryConfig(&repo); err != nil {
-			errors = append(errors, fmt.Errorf("repository '%s': %w", repo.Alias, err))
-		}
-	}
-
-	return errors
+func init() {
+	DoctorCmd.Flags().StringSlice("fix", nil, "Repair the problems found, or only those of the given checks")
+	DoctorCmd.Flags().Lookup("fix").NoOptDefVal = doctor.FixAll
+	DoctorCmd.Flags().Bool("json", false, "Output in JSON format")
 }
@@ -0,0 +1,49 @@
+package root
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var DuCmd = &cobra.Command{
+	Use:   "du",
+	Short: "Show the disk usage of worktrees",
+	Long:  `Measure how much disk space each worktree takes up, largest first, with its largest top-level directories such as node_modules, target or .venv. Worktrees not worked on for --stale-days are marked stale, and the largest of them are pointed out as the first to remove. Files hard linked between worktrees, such as those linked by 'wt dedupe', count once in the repository total. Defaults to the active repository.`,
+	Args:  cobra.NoArgs,
+	RunE:  runDu,
+}
+
+func runDu(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	repos, _ := cmd.Flags().GetStringSlice("repo")
+	all, _ := cmd.Flags().GetBool("all")
+	jsonFormat, _ := cmd.Flags().GetBool("json")
+	staleDays, _ := cmd.Flags().GetInt("stale-days")
+
+	opts := worktree.DiskUsageOptions{
+		Repos:      repos,
+		All:        all,
+		JSON:       jsonFormat,
+		StaleAfter: time.Duration(staleDays) * 24 * time.Hour,
+	}
+
+	if err := worktree.ShowDiskUsage(appState, opts); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func init() {
+	DuCmd.Flags().StringSlice("repo", nil, "Repository aliases to measure (defaults to the active repository)")
+	DuCmd.Flags().Bool("all", false, "Measure every managed repository")
+	DuCmd.Flags().Bool("json", false, "Output in JSON format")
+	DuCmd.Flags().Int("stale-days", int(worktree.DefaultStaleAfter.Hours()/24), "Days without being worked on after which a worktree is stale")
+	DuCmd.MarkFlagsMutuallyExclusive("repo", "all")
+}
@@ -0,0 +1,51 @@
+package root
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var EnvCmd = &cobra.Command{
+	Use:   "env <branch>",
+	Short: "Print a worktree's environment as export statements",
+	Long: `Print the environment of a worktree of the active repository as shell export statements, for use with eval "$(wt env <branch>)". It holds the WT_* variables hooks receive, including the worktree's index, ports and compose project name, and the variables set with 'wt repo env'.
+
+With 'env-file' set in the config, the same variables are written to .wt.env in every new worktree, and with 'direnv' set an .envrc loading it is written as well. The files are kept out of git status and updated when the worktree's ports or variables change or it moves; use --write to write or update them now.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnv,
+}
+
+func init() {
+	EnvCmd.Flags().Bool("write", false, "Write the worktree's .wt.env, and .envrc with 'direnv' set, instead of printing")
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	branch := args[0]
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	write, _ := cmd.Flags().GetBool("write")
+
+	if write {
+		if err := worktree.WriteEnvFiles(cfg, appState, branch); err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		output.Success("Environment files of '%s' written", branch)
+		return nil
+	}
+
+	env, err := worktree.WorktreeEnvironment(appState, branch)
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	fmt.Fprint(output.Stdout(), worktree.FormatExports(env))
+	return nil
+}
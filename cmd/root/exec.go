@@ -0,0 +1,56 @@
+package root
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var ExecCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [args...]",
+	Short: "Run a command in every selected worktree",
+	Long:  `Run an arbitrary command in each selected worktree, sequentially or in parallel, with the same WT_* environment that hook scripts receive. Exits non-zero if the command fails in any worktree. Defaults to the worktrees of the active repository.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runExec,
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	repos, _ := cmd.Flags().GetStringSlice("repo")
+	allRepos, _ := cmd.Flags().GetBool("all-repos")
+	filter, _ := cmd.Flags().GetString("filter")
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	outputMode, _ := cmd.Flags().GetString("output")
+
+	opts := worktree.ExecOptions{
+		Repos:    repos,
+		AllRepos: allRepos,
+		Filter:   filter,
+		Parallel: parallel,
+		Output:   outputMode,
+	}
+
+	if err := worktree.ExecInWorktrees(appState, args, opts); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func init() {
+	ExecCmd.Flags().StringSlice("repo", nil, "Repository aliases to run in (defaults to the active repository)")
+	ExecCmd.Flags().Bool("all-repos", false, "Run in the worktrees of every managed repository")
+	ExecCmd.Flags().String("filter", "", fmt.Sprintf("Run only in worktrees matching one of: %s, or a branch glob", strings.Join(worktree.FilterOptions, ", ")))
+	ExecCmd.Flags().IntP("parallel", "j", 1, "Number of worktrees to run in concurrently (1 runs sequentially)")
+	ExecCmd.Flags().String("output", "prefixed", fmt.Sprintf("Output mode, one of: %s", strings.Join(worktree.ExecOutputModes, ", ")))
+	ExecCmd.MarkFlagsMutuallyExclusive("repo", "all-repos")
+
+	// Stop flag parsing at the command so its own flags are passed through untouched
+	ExecCmd.Flags().SetInterspersed(false)
+}
@@ -0,0 +1,58 @@
+package root
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/manifest"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+var ExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the current setup as a workspace file",
+	Long: `Describe the configured repositories, their hooks and file rules, and the config as a workspace file for 'wt apply'.
+
+The file is printed as YAML unless --output is given; an output path ending in .json is written as JSON. API tokens are left out unless --include-secrets is set, and scripts that still hold the generated template are skipped.`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	outputPath, _ := cmd.Flags().GetString("output")
+	includeSecrets, _ := cmd.Flags().GetBool("include-secrets")
+
+	m, err := manifest.Export(cfg, appState, manifest.ExportOptions{IncludeSecrets: includeSecrets})
+	if err != nil {
+		output.Error("Failed to export: %v", err)
+		os.Exit(1)
+	}
+
+	data, err := m.Marshal(outputPath)
+	if err != nil {
+		output.Error("Failed to encode workspace file: %v", err)
+		os.Exit(1)
+	}
+
+	if outputPath == "" {
+		os.Stdout.Write(data)
+		return nil
+	}
+
+	if err := os.WriteFile(outputPath, data, 0600); err != nil {
+		output.Error("Failed to write workspace file: %v", err)
+		os.Exit(1)
+	}
+	output.Success("Workspace file written to: %s", outputPath)
+	return nil
+}
+
+func init() {
+	ExportCmd.Flags().StringP("output", "o", "", "Write the workspace file to this path instead of stdout")
+	ExportCmd.Flags().Bool("include-secrets", false, "Include API tokens from the config")
+}
@@ -0,0 +1,20 @@
+package root
+
+import (
+	"github.com/spf13/cobra"
+	"worktree-manager/cmd/hooks"
+)
+
+var HooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage hook scripts",
+	Long:  `Commands for inspecting and editing the post-worktree-add and work-on hooks, and for generating post-worktree-add scripts from built-in templates.`,
+}
+
+func init() {
+	HooksCmd.AddCommand(hooks.ListCmd)
+	HooksCmd.AddCommand(hooks.ShowCmd)
+	HooksCmd.AddCommand(hooks.EditCmd)
+	HooksCmd.AddCommand(hooks.InitCmd)
+	HooksCmd.AddCommand(hooks.TemplatesCmd)
+}
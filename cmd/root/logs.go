@@ -0,0 +1,92 @@
+package root
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var LogsCmd = &cobra.Command{
+	Use:   "logs [branch]",
+	Short: "Show the output of hook scripts",
+	Long: `Show what the post-worktree-add and work-on hooks printed.
+
+Every hook run is logged under the logs directory as <alias>/<branch>/<hook>-<timestamp>.log, keeping the last 10 runs of each hook. Without a branch, the runs of all worktrees of the active repository are listed. With a branch, the latest log of that worktree is printed; use --list to list its runs instead.
+
+Hooks run without a timeout and keep the terminal, so they can prompt, and only their start and result are logged. Set 'hook-timeouts' in the config to limit how long a hook may run, for example {"post-worktree-add": "15m"}; a hook with a timeout runs detached from the terminal, without input, and its output is logged.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogs,
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	hook, _ := cmd.Flags().GetString("hook")
+	list, _ := cmd.Flags().GetBool("list")
+
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	var branch string
+	if len(args) > 0 {
+		branch = args[0]
+	}
+
+	logs, err := worktree.ListHookLogs(activeRepo, branch)
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	if hook != "" {
+		var matching []worktree.HookLog
+		for _, log := range logs {
+			if log.Hook == hook {
+				matching = append(matching, log)
+			}
+		}
+		logs = matching
+	}
+
+	if len(logs) == 0 {
+		output.Info("No hook logs found")
+		return nil
+	}
+
+	if branch == "" || list {
+		rows := make([][]string, 0, len(logs))
+		for _, log := range logs {
+			result := log.Result
+			if result == "" {
+				result = "running"
+			}
+			rows = append(rows, []string{log.Branch, log.Hook, log.Started.Format("2006-01-02 15:04:05"), result, log.Path})
+		}
+		output.Table([]string{"BRANCH", "HOOK", "STARTED", "RESULT", "LOG"}, rows)
+		return nil
+	}
+
+	file, err := os.Open(logs[0].Path)
+	if err != nil {
+		output.Error("Failed to open %s: %v", logs[0].Path, err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(output.Stdout(), file); err != nil {
+		output.Error("Failed to read %s: %v", logs[0].Path, err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func init() {
+	LogsCmd.Flags().String("hook", "", "Only show logs of this hook (post-worktree-add or work-on)")
+	LogsCmd.Flags().BoolP("list", "l", false, "List the runs of the branch's hooks instead of printing the latest log")
+}
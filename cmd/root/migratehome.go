@@ -0,0 +1,80 @@
+package root
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/migrate"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+var MigrateHomeCmd = &cobra.Command{
+	Use:   "migrate-home",
+	Short: "Move config, state, repos and worktrees to another location",
+	Long: `Move everything worktree-manager keeps to another location and repair the git worktree links.
+
+With --to, config, state, scripts, repos and worktrees are moved into a single directory to use as WT_HOME. With --xdg, they are spread over the XDG config, state and data directories. With neither, only repositories and worktrees are moved, to the repos-dir and worktrees-dir set in the config.
+
+Repositories and worktrees are only moved when they sit where wt placed them; ones registered from elsewhere stay put. Use --dry-run to see the moves first.`,
+	Args: cobra.NoArgs,
+	RunE: runMigrateHome,
+}
+
+func runMigrateHome(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	to, _ := cmd.Flags().GetString("to")
+	xdg, _ := cmd.Flags().GetBool("xdg")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	source, err := consts.ResolveLayout()
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	target := source
+	switch {
+	case to != "":
+		abs, err := filepath.Abs(to)
+		if err != nil {
+			output.Error("Invalid directory %s: %v", to, err)
+			os.Exit(1)
+		}
+		target = consts.SingleRootLayout(abs)
+	case xdg:
+		target, err = consts.XDGLayout()
+		if err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+	}
+
+	moves, err := migrate.MigrateHome(appState, migrate.Options{Source: source, Target: target, DryRun: dryRun})
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	if dryRun || len(moves) == 0 {
+		return nil
+	}
+
+	output.Success("Migration complete")
+	switch {
+	case to != "" && os.Getenv(consts.HomeEnvVar) != target.ConfigDir:
+		output.Hint("Add 'export %s=%s' to your shell profile so wt finds it", consts.HomeEnvVar, target.ConfigDir)
+	case xdg && os.Getenv(consts.HomeEnvVar) != "":
+		output.Hint("Remove %s from your shell profile so wt uses the XDG directories", consts.HomeEnvVar)
+	}
+	return nil
+}
+
+func init() {
+	MigrateHomeCmd.Flags().String("to", "", "Move everything into this directory")
+	MigrateHomeCmd.Flags().Bool("xdg", false, "Move everything into the XDG config, state and data directories")
+	MigrateHomeCmd.Flags().Bool("dry-run", false, "Show the moves without making them")
+	MigrateHomeCmd.MarkFlagsMutuallyExclusive("to", "xdg")
+}
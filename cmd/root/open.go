@@ -0,0 +1,57 @@
+package root
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/picker"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var OpenCmd = &cobra.Command{
+	Use:   "open [branch]",
+	Short: "Open a worktree in an editor or IDE",
+	Long: `Open a worktree of the active repository in an editor. Without a branch, pick the worktree interactively.
+
+The editor defaults to 'workon-editor' from the config, then 'config-editor'. VS Code opens a new window on a generated workspace titled <alias>:<branch>, JetBrains IDEs reuse the window of an already open project, and terminal editors such as nvim open in a tmux split when run inside tmux.
+Setting 'workon-editor' also opens the editor on 'wt tree workon'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runOpen,
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	editor, _ := cmd.Flags().GetString("editor")
+
+	var branch string
+	if len(args) > 0 {
+		branch = args[0]
+	} else {
+		picked, err := worktree.PickWorktrees(cfg, appState, "open> ", false)
+		if errors.Is(err, picker.ErrCancelled) {
+			output.Warning("No worktree selected")
+			return nil
+		}
+		if err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		branch = picked[0].ShortBranch()
+	}
+
+	if err := worktree.OpenWorktree(cfg, appState, branch, editor); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func init() {
+	OpenCmd.Flags().StringP("editor", "e", "", "Editor to open the worktree with (e.g. code, idea, nvim)")
+}
@@ -0,0 +1,56 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/picker"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var PickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Pick a worktree interactively and print its path",
+	Long: `Open the fuzzy finder on the worktrees of the active repository and print the path of the chosen worktree, e.g. 'cd "$(wt pick)"'.
+
+The built-in finder is used unless the 'picker' config setting names an external command such as fzf.`,
+	Args: cobra.NoArgs,
+	RunE: runPick,
+}
+
+func runPick(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	multi, _ := cmd.Flags().GetBool("multi")
+	printBranch, _ := cmd.Flags().GetBool("branch")
+
+	picked, err := worktree.PickWorktrees(cfg, appState, "pick> ", multi)
+	if errors.Is(err, picker.ErrCancelled) {
+		// Nothing is printed so that 'cd "$(wt pick)"' stays put
+		os.Exit(1)
+	}
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	for _, info := range picked {
+		if printBranch {
+			fmt.Println(info.ShortBranch())
+		} else {
+			fmt.Println(info.Path)
+		}
+	}
+	return nil
+}
+
+func init() {
+	PickCmd.Flags().BoolP("multi", "m", false, "Allow picking several worktrees, printing one per line")
+	PickCmd.Flags().Bool("branch", false, "Print the branch name instead of the worktree path")
+}
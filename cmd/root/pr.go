@@ -0,0 +1,16 @@
+package root
+
+import (
+	"github.com/spf13/cobra"
+	"worktree-manager/cmd/pr"
+)
+
+var PrCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Manage pull requests",
+	Long:  `Commands for working with pull requests (merge requests on GitLab) through the forge API.`,
+}
+
+func init() {
+	PrCmd.AddCommand(pr.OpenCmd)
+}
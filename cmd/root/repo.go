@@ -17,4 +17,6 @@ func init() {
 	RepoCmd.AddCommand(repo.RemoveCmd)
 	RepoCmd.AddCommand(repo.UseCmd)
 	RepoCmd.AddCommand(repo.CurrentCmd)
+	RepoCmd.AddCommand(repo.PortsCmd)
+	RepoCmd.AddCommand(repo.EnvCmd)
 }
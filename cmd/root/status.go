@@ -0,0 +1,41 @@
+package root
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var StatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of every managed repository",
+	Long:  `Show worktree counts, dirty worktrees, branches ahead of or behind their upstream and missing directories for every managed repository. The active repository is marked with '*'.`,
+	Args:  cobra.NoArgs,
+	RunE:  runStatus,
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	fetch, _ := cmd.Flags().GetBool("fetch")
+	jsonFormat, _ := cmd.Flags().GetBool("json")
+
+	opts := worktree.StatusOptions{
+		Fetch: fetch,
+		JSON:  jsonFormat,
+	}
+
+	if err := worktree.ShowStatus(appState, opts); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func init() {
+	StatusCmd.Flags().Bool("fetch", false, "Fetch from origin in each repository before collecting status")
+	StatusCmd.Flags().Bool("json", false, "Output in JSON format")
+}
@@ -0,0 +1,53 @@
+package root
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var SyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Update worktrees from their upstream branches",
+	Long:  `Fetch once, then fast-forward or rebase every clean worktree onto its upstream. Dirty worktrees and worktrees that cannot be updated cleanly are skipped and reported. Defaults to the active repository.`,
+	Args:  cobra.NoArgs,
+	RunE:  runSync,
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	repos, _ := cmd.Flags().GetStringSlice("repo")
+	all, _ := cmd.Flags().GetBool("all")
+	strategy, _ := cmd.Flags().GetString("strategy")
+	ontoBase, _ := cmd.Flags().GetBool("onto-base")
+
+	opts := worktree.SyncOptions{
+		Repos:    repos,
+		All:      all,
+		Strategy: strategy,
+		OntoBase: ontoBase,
+	}
+
+	if err := worktree.SyncWorktrees(cfg, appState, opts); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func init() {
+	SyncCmd.Flags().StringSlice("repo", nil, "Repository aliases to sync (defaults to the active repository)")
+	SyncCmd.Flags().Bool("all", false, "Sync every managed repository")
+	SyncCmd.Flags().String("strategy", "", fmt.Sprintf("Update strategy, one of: %s (defaults to the sync-strategy config value)", strings.Join(consts.SyncStrategies, ", ")))
+	SyncCmd.Flags().Bool("onto-base", false, "Rebase feature worktrees onto the latest base branch instead of their upstream")
+	SyncCmd.MarkFlagsMutuallyExclusive("repo", "all")
+}
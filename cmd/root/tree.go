@@ -16,4 +16,10 @@ func init() {
 	TreeCmd.AddCommand(tree.RemoveCmd)
 	TreeCmd.AddCommand(tree.ListCmd)
 	TreeCmd.AddCommand(tree.WorkonCmd)
+	TreeCmd.AddCommand(tree.RefreshFilesCmd)
+	TreeCmd.AddCommand(tree.RefreshCmd)
+	TreeCmd.AddCommand(tree.WaitCmd)
+	TreeCmd.AddCommand(tree.UpCmd)
+	TreeCmd.AddCommand(tree.DownCmd)
+	TreeCmd.AddCommand(tree.BootstrapCmd)
 }
@@ -0,0 +1,33 @@
+package root
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/tui"
+)
+
+var UiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Manage repositories and worktrees in a full-screen terminal UI",
+	Long: `Open a full-screen terminal UI listing repositories on the left and the worktrees of the selected repository on the right, with live status.
+
+Keys: a add, d remove, w or enter work on, o open in editor, s sync, p prune, r refresh, tab or h/l to switch panes, j/k or arrows to move, q to quit.
+Acting on a repository makes it the active repository, as 'wt repo use' would.`,
+	Args: cobra.NoArgs,
+	RunE: runUi,
+}
+
+func runUi(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	if err := tui.Run(cfg, appState); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
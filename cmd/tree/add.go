@@ -13,16 +13,52 @@ import (
 var AddCmd = &cobra.Command{
 	Use:   "add <branch>",
 	Short: "Add a new worktree for the specified branch",
-	Long:  `Create a new worktree for the specified branch in the current repository. Must be run from within a repository managed by worktree-manager.`,
-	Args:  cobra.ExactArgs(1),
+	Long:  `Create a new worktree for the specified branch in the current repository, for a pull/merge request with --pr, or for an issue with --issue. Must be run from within a repository managed by worktree-manager.`,
+	Args:  cobra.MaximumNArgs(1),
 	RunE:  runAdd,
 }
 
+func init() {
+	AddCmd.Flags().Int("pr", 0, "Create the worktree from a GitHub pull request or GitLab merge request number")
+	AddCmd.Flags().String("issue", "", "Create the worktree for an issue key, naming the branch from the branch template")
+	AddCmd.MarkFlagsMutuallyExclusive("pr", "issue")
+}
+
 func runAdd(cmd *cobra.Command, args []string) error {
-	branch := args[0]
 	cfg := config.GetConfigFromContext(cmd.Context())
 	appState := state.GetStateFromContext(cmd.Context())
 
+	prNumber, _ := cmd.Flags().GetInt("pr")
+	issueKey, _ := cmd.Flags().GetString("issue")
+
+	if (prNumber != 0 || issueKey != "") && len(args) > 0 {
+		output.Error("Specify either a branch, --pr or --issue, not several")
+		os.Exit(1)
+	}
+
+	if issueKey != "" {
+		if err := worktree.AddIssueWorktree(cfg, appState, issueKey); err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	if prNumber != 0 {
+		if err := worktree.AddPullRequestWorktree(cfg, appState, prNumber); err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	if len(args) == 0 {
+		output.Error("A branch name is required")
+		output.Hint("Use 'wt tree add <branch>', 'wt tree add --pr <number>' or 'wt tree add --issue <key>'")
+		os.Exit(1)
+	}
+
+	branch := args[0]
 	if err := worktree.AddWorktree(cfg, appState, branch); err != nil {
 		output.Error("%v", err)
 		os.Exit(1)
@@ -0,0 +1,51 @@
+package tree
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+// BootstrapCmd is what 'wt tree add' starts in the background to run the post-worktree-add script when
+// 'async-post-worktree-add' is set. It is not meant to be run by hand
+var BootstrapCmd = &cobra.Command{
+	Use:    "bootstrap <branch>",
+	Short:  "Run a worktree's post-worktree-add script and record its result",
+	Args:   cobra.ExactArgs(1),
+	Hidden: true,
+	RunE:   runBootstrap,
+}
+
+func init() {
+	BootstrapCmd.Flags().String("repo", "", "Alias of the worktree's repository")
+	BootstrapCmd.Flags().String("operation", "add", "What created the worktree, passed to the script as WT_OPERATION")
+	BootstrapCmd.MarkFlagRequired("repo")
+}
+
+func runBootstrap(cmd *cobra.Command, args []string) error {
+	branch := args[0]
+	cfg := config.GetConfigFromContext(cmd.Context())
+	appState := state.GetStateFromContext(cmd.Context())
+
+	alias, _ := cmd.Flags().GetString("repo")
+	operation, _ := cmd.Flags().GetString("operation")
+
+	repo, err := appState.FindRepoByAlias(alias)
+	if err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+
+	// Nobody watches a background run; its output goes to the hook log only
+	output.Redirect(io.Discard, io.Discard)
+
+	if err := worktree.RunBootstrap(cfg, appState, repo, branch, operation); err != nil {
+		os.Exit(1)
+	}
+	return nil
+}
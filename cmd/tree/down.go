@@ -0,0 +1,34 @@
+package tree
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var DownCmd = &cobra.Command{
+	Use:   "down <branch>",
+	Short: "Tear down a worktree's docker compose stack",
+	Long:  `Run 'docker compose down' for the compose project of a worktree, removing its containers and networks. Volumes are kept unless --volumes is given. Must be run with an active repository.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDown,
+}
+
+func init() {
+	DownCmd.Flags().BoolP("volumes", "v", false, "Remove the project's volumes as well")
+}
+
+func runDown(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	volumes, _ := cmd.Flags().GetBool("volumes")
+
+	if err := worktree.ComposeDown(appState, args[0], volumes); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
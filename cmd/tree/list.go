@@ -1,9 +1,12 @@
 package tree
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
 	"worktree-manager/internal/output"
 	"worktree-manager/internal/state"
 	"worktree-manager/internal/worktree"
@@ -12,12 +15,16 @@ import (
 var ListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List worktrees for the current repository",
-	Long:  `List all worktrees for the current repository. Must be run from within a repository managed by worktree-manager.`,
+	Long:  `List all worktrees for the current repository with their branch, HEAD, working tree state, upstream divergence, last commit and lock flags. The current worktree is marked with '*'. Must be run from within a repository managed by worktree-manager.`,
 	RunE:  runList,
 }
 
 func init() {
 	ListCmd.Flags().Bool("json", false, "Output in JSON format for autocompletion")
+	ListCmd.Flags().Bool("names", false, "Output only the branch names, one per line")
+	ListCmd.Flags().String("sort", "branch", fmt.Sprintf("Sort worktrees by one of: %s", strings.Join(worktree.SortOptions, ", ")))
+	ListCmd.Flags().Bool("forge", false, "Show pull request, CI and review status from the forge API")
+	ListCmd.Flags().String("filter", "", fmt.Sprintf("Show only worktrees matching one of: %s, or a branch glob", strings.Join(worktree.FilterOptions, ", ")))
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -25,14 +32,31 @@ func runList(cmd *cobra.Command, args []string) error {
 
 	// Check if JSON format is requested
 	jsonFormat, _ := cmd.Flags().GetBool("json")
+	namesOnly, _ := cmd.Flags().GetBool("names")
 
 	if jsonFormat {
 		if err := worktree.ListWorktreesJSON(appState); err != nil {
 			output.Error("%v", err)
 			os.Exit(1)
 		}
+	} else if namesOnly {
+		if err := worktree.ListWorktreeNames(appState); err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
 	} else {
-		if err := worktree.ListWorktrees(appState); err != nil {
+		sortBy, _ := cmd.Flags().GetString("sort")
+		filter, _ := cmd.Flags().GetString("filter")
+		showForge, _ := cmd.Flags().GetBool("forge")
+
+		opts := worktree.ListOptions{
+			Sort:   sortBy,
+			Filter: filter,
+			Forge:  showForge,
+		}
+
+		cfg := config.GetConfigFromContext(cmd.Context())
+		if err := worktree.ListWorktrees(cfg, appState, opts); err != nil {
 			output.Error("%v", err)
 			os.Exit(1)
 		}
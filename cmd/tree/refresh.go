@@ -0,0 +1,35 @@
+package tree
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var RefreshCmd = &cobra.Command{
+	Use:   "refresh <branch>",
+	Short: "Re-fetch the pull request head for a worktree",
+	Long:  `Fetch the latest head of the pull or merge request a worktree was created from with 'wt tree add --pr' and fast-forward the worktree to it. Use --force to reset the worktree when the pull request was rewritten.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRefresh,
+}
+
+func init() {
+	RefreshCmd.Flags().BoolP("force", "f", false, "Reset the worktree to the new head when it cannot be fast-forwarded")
+}
+
+func runRefresh(cmd *cobra.Command, args []string) error {
+	branch := args[0]
+	appState := state.GetStateFromContext(cmd.Context())
+
+	force, _ := cmd.Flags().GetBool("force")
+
+	if err := worktree.RefreshPullRequestWorktree(appState, branch, force); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
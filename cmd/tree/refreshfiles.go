@@ -0,0 +1,29 @@
+package tree
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var RefreshFilesCmd = &cobra.Command{
+	Use:   "refresh-files <branch>",
+	Short: "Re-apply file copy and symlink rules to a worktree",
+	Long:  `Copy, clone or symlink the untracked files matched by the repository's file rules from the main checkout into an existing worktree, replacing any existing copies. Must be run with an active repository.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRefreshFiles,
+}
+
+func runRefreshFiles(cmd *cobra.Command, args []string) error {
+	branch := args[0]
+	appState := state.GetStateFromContext(cmd.Context())
+
+	if err := worktree.RefreshWorktreeFiles(appState, branch); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
@@ -1,28 +1,64 @@
 package tree
 
 import (
+	"errors"
 	"os"
 
 	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
 	"worktree-manager/internal/output"
+	"worktree-manager/internal/picker"
 	"worktree-manager/internal/state"
 	"worktree-manager/internal/worktree"
 )
 
 var RemoveCmd = &cobra.Command{
-	Use:   "remove <branch>",
+	Use:   "remove [branch]",
 	Short: "Remove a worktree for the specified branch",
-	Long:  `Remove the worktree for the specified branch in the current repository. Must be run from within a repository managed by worktree-manager.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runRemove,
+	Long: `Remove the worktree for the specified branch in the current repository. Without a branch, pick one or more worktrees interactively (tab toggles a selection). Must be run from within a repository managed by worktree-manager.
+
+A docker compose stack wt brought up for the worktree is torn down; its volumes are kept unless --volumes is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRemove,
+}
+
+func init() {
+	RemoveCmd.Flags().BoolP("volumes", "v", false, "Remove the volumes of the worktree's compose stack as well")
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
-	branch := args[0]
+	cfg := config.GetConfigFromContext(cmd.Context())
 	appState := state.GetStateFromContext(cmd.Context())
 
-	if err := worktree.RemoveWorktree(appState, branch); err != nil {
-		output.Error("%v", err)
+	volumes, _ := cmd.Flags().GetBool("volumes")
+
+	var branches []string
+	if len(args) > 0 {
+		branches = []string{args[0]}
+	} else {
+		picked, err := worktree.PickWorktrees(cfg, appState, "remove> ", true)
+		if errors.Is(err, picker.ErrCancelled) {
+			output.Warning("No worktree selected")
+			return nil
+		}
+		if err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		for _, info := range picked {
+			branches = append(branches, info.ShortBranch())
+		}
+	}
+
+	failed := false
+	for _, branch := range branches {
+		if err := worktree.RemoveWorktree(appState, branch, worktree.RemoveOptions{Volumes: volumes}); err != nil {
+			output.Error("%v", err)
+			failed = true
+		}
+	}
+
+	if failed {
 		os.Exit(1)
 	}
 	return nil
@@ -0,0 +1,30 @@
+package tree
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var UpCmd = &cobra.Command{
+	Use:   "up <branch>",
+	Short: "Bring up a worktree's docker compose stack",
+	Long: `Run 'docker compose up --detach' in a worktree under a project name of its own, <alias>-<branch-slug>, so that the stacks of several worktrees run side by side. The compose files see the worktree's WT_* variables, such as WT_PORT_<NAME>, and the variables set with 'wt repo env'. Must be run with an active repository.
+
+With 'compose-up' set in the config, the stack is brought up whenever the worktree is added or worked on. 'wt tree remove' tears down a stack left up, keeping its volumes unless --volumes is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUp,
+}
+
+func runUp(cmd *cobra.Command, args []string) error {
+	appState := state.GetStateFromContext(cmd.Context())
+
+	if err := worktree.ComposeUp(appState, args[0]); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package tree
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var WaitCmd = &cobra.Command{
+	Use:   "wait <branch>",
+	Short: "Wait for a worktree's post-worktree-add script to finish",
+	Long:  `Block until the post-worktree-add script of a worktree has finished, which is useful with 'async-post-worktree-add' set in the config. Exits with an error if the script failed or --timeout passed first. Must be run with an active repository.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWait,
+}
+
+func init() {
+	WaitCmd.Flags().Duration("timeout", 0, "Give up after this long, e.g. 5m (default: wait until the script finishes)")
+}
+
+func runWait(cmd *cobra.Command, args []string) error {
+	branch := args[0]
+	appState := state.GetStateFromContext(cmd.Context())
+
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	if err := worktree.WaitForBootstrap(appState, branch, timeout); err != nil {
+		output.Error("%v", err)
+		os.Exit(1)
+	}
+	return nil
+}
@@ -1,27 +1,56 @@
 package tree
 
 import (
+	"errors"
 	"os"
 
 	"github.com/spf13/cobra"
+	"worktree-manager/internal/config"
 	"worktree-manager/internal/output"
+	"worktree-manager/internal/picker"
 	"worktree-manager/internal/state"
 	"worktree-manager/internal/worktree"
 )
 
 var WorkonCmd = &cobra.Command{
-	Use:   "workon <branch>",
+	Use:   "workon [branch]",
 	Short: "Work on a specific worktree",
-	Long:  `Change to a worktree directory and run the work-on script if configured. Must be run from within a repository managed by worktree-manager.`,
-	Args:  cobra.ExactArgs(1),
+	Long:  `Change to a worktree directory and run the work-on script if configured. Without a branch, pick the worktree interactively. With --wait, first wait for a post-worktree-add script still running in the background. Must be run from within a repository managed by worktree-manager.`,
+	Args:  cobra.MaximumNArgs(1),
 	RunE:  runWorkon,
 }
 
+func init() {
+	WorkonCmd.Flags().Bool("wait", false, "Wait for the worktree's post-worktree-add script to finish first")
+}
+
 func runWorkon(cmd *cobra.Command, args []string) error {
-	branch := args[0]
+	cfg := config.GetConfigFromContext(cmd.Context())
 	appState := state.GetStateFromContext(cmd.Context())
 
-	if err := worktree.WorkOnWorktree(appState, branch); err != nil {
+	var branch string
+	if len(args) > 0 {
+		branch = args[0]
+	} else {
+		picked, err := worktree.PickWorktrees(cfg, appState, "workon> ", false)
+		if errors.Is(err, picker.ErrCancelled) {
+			output.Warning("No worktree selected")
+			return nil
+		}
+		if err != nil {
+			output.Error("%v", err)
+			os.Exit(1)
+		}
+		branch = picked[0].ShortBranch()
+	}
+
+	if wait, _ := cmd.Flags().GetBool("wait"); wait {
+		if err := worktree.WaitForBootstrap(appState, branch, 0); err != nil {
+			output.Warning("%v", err)
+		}
+	}
+
+	if err := worktree.WorkOnWorktree(cfg, appState, branch); err != nil {
 		output.Error("%v", err)
 		os.Exit(1)
 	}
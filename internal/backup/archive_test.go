@@ -0,0 +1,60 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "app"), 0755)
+	os.WriteFile(filepath.Join(src, "work-on.sh"), []byte("#!/bin/bash\n"), 0755)
+	os.WriteFile(filepath.Join(src, "app", "post-worktree-add.sh"), []byte("npm install\n"), 0644)
+
+	archivePath := filepath.Join(t.TempDir(), "test.tar.gz")
+	archive, err := createArchive(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.addTree("scripts", src); err != nil {
+		t.Fatalf("addTree failed: %v", err)
+	}
+	if err := archive.addBytes("manifest.json", []byte("{}"), 0644); err != nil {
+		t.Fatalf("addBytes failed: %v", err)
+	}
+	if err := archive.close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := extractArchive(archivePath, dst); err != nil {
+		t.Fatalf("extractArchive failed: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dst, "scripts", "work-on.sh"))
+	if err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("Expected an executable work-on.sh, got %v (err: %v)", info, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "scripts", "app", "post-worktree-add.sh")); string(data) != "npm install\n" {
+		t.Errorf("Unexpected content: %q", data)
+	}
+}
+
+func TestExtractArchive_RejectsEscapingEntries(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "evil.tar.gz")
+	archive, err := createArchive(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive.addBytes("../escaped.txt", []byte("x"), 0644)
+	archive.close()
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	if err := extractArchive(archivePath, dst); err == nil {
+		t.Error("Expected an error for an entry outside the archive")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dst), "escaped.txt")); err == nil {
+		t.Error("Expected nothing to be written outside the destination")
+	}
+}
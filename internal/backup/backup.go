@@ -0,0 +1,209 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+// Version is the archive layout written by 'wt backup create' and understood by 'wt backup restore'
+const Version = 1
+
+// Names of the archive entries; worktree bundles and patches live below worktreesEntry
+const (
+	manifestEntry  = "manifest.json"
+	configEntry    = "config.json"
+	stateEntry     = "state.json"
+	scriptsEntry   = "scripts"
+	worktreesEntry = "worktrees"
+)
+
+// Manifest records every repository and worktree in a backup
+type Manifest struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created-at"`
+	Repos     []RepoSnapshot `json:"repos"`
+}
+
+// RepoSnapshot records where a repository lives and is cloned from
+type RepoSnapshot struct {
+	Alias     string             `json:"alias"`
+	URL       string             `json:"url,omitempty"`
+	Dir       string             `json:"dir"`
+	Worktrees []WorktreeSnapshot `json:"worktrees,omitempty"`
+}
+
+// WorktreeSnapshot records a worktree's commit and upstream, and the archive entries holding what is not on a remote
+type WorktreeSnapshot struct {
+	Branch   string `json:"branch,omitempty"`
+	Path     string `json:"path"`
+	Head     string `json:"head"`
+	Upstream string `json:"upstream,omitempty"`
+	MergeRef string `json:"merge-ref,omitempty"`
+	Dirty    bool   `json:"dirty"`
+	Bundle   string `json:"bundle,omitempty"`
+	Patch    string `json:"patch,omitempty"`
+}
+
+// CreateOptions controls what a backup contains
+type CreateOptions struct {
+	IncludePatches bool
+}
+
+// DefaultArchiveName names a backup after the time it was taken
+func DefaultArchiveName(now time.Time) string {
+	return fmt.Sprintf("wt-backup-%s.tar.gz", now.Format("20060102-150405"))
+}
+
+// Create writes config.json, state.json, the scripts and a manifest of every worktree to a gzip-compressed tar archive.
+// Commits that are on no remote are stored as git bundles so restore can recreate the worktrees at the recorded commits
+func Create(appState *state.State, archivePath string, opts CreateOptions) (*Manifest, error) {
+	archive, err := createArchive(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := writeBackup(archive, appState, opts)
+	if closeErr := archive.close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(archivePath)
+		return nil, err
+	}
+	return m, nil
+}
+
+func writeBackup(archive *archiveWriter, appState *state.State, opts CreateOptions) (*Manifest, error) {
+	paths := consts.GetFilePaths()
+
+	if err := archive.addFile(configEntry, paths.Config); err != nil {
+		return nil, fmt.Errorf("failed to back up config: %w", err)
+	}
+
+	// Repository directories are stored relative to $HOME so the backup restores under another user name
+	portable := *appState
+	portable.Repos = make([]state.Repo, len(appState.Repos))
+	for i, repo := range appState.Repos {
+		repo.Dir = fileops.PortablePath(repo.Dir)
+		portable.Repos[i] = repo
+	}
+	stateData, err := json.MarshalIndent(portable, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	if err := archive.addBytes(stateEntry, stateData, 0644); err != nil {
+		return nil, err
+	}
+
+	scriptsDir := consts.GetDirectoryPaths().ScriptsDir
+	if fileops.FileExists(scriptsDir) {
+		if err := archive.addTree(scriptsEntry, scriptsDir); err != nil {
+			return nil, fmt.Errorf("failed to back up scripts: %w", err)
+		}
+	}
+
+	m := &Manifest{Version: Version, CreatedAt: time.Now().UTC()}
+	for i := range appState.Repos {
+		m.Repos = append(m.Repos, snapshotRepo(archive, &appState.Repos[i], opts))
+	}
+
+	manifestData, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	if err := archive.addBytes(manifestEntry, manifestData, 0644); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func snapshotRepo(archive *archiveWriter, repo *state.Repo, opts CreateOptions) RepoSnapshot {
+	output.Progress("Backing up %s...", repo.Alias)
+
+	snapshot := RepoSnapshot{Alias: repo.Alias, Dir: fileops.PortablePath(repo.Dir)}
+	if url, err := git.GetRemoteURL(repo.Dir); err == nil {
+		snapshot.URL = url
+	} else {
+		output.Warning("%s has no origin remote, restore will not be able to clone it", repo.Alias)
+	}
+
+	infos, err := worktree.RepoWorktrees(repo)
+	if err != nil {
+		output.Warning("Failed to list worktrees of %s: %v", repo.Alias, err)
+		return snapshot
+	}
+
+	for _, info := range infos {
+		if info.Prunable {
+			continue
+		}
+		wt, err := snapshotWorktree(archive, repo, info, opts)
+		if err != nil {
+			output.Warning("Failed to back up worktree %s: %v", info.Path, err)
+			continue
+		}
+		snapshot.Worktrees = append(snapshot.Worktrees, wt)
+	}
+	return snapshot
+}
+
+func snapshotWorktree(archive *archiveWriter, repo *state.Repo, info worktree.WorktreeInfo, opts CreateOptions) (WorktreeSnapshot, error) {
+	wt := WorktreeSnapshot{
+		Branch:   info.ShortBranch(),
+		Path:     fileops.PortablePath(info.Path),
+		Head:     info.Head,
+		Upstream: info.Status.Upstream,
+		Dirty:    info.Status.Dirty,
+	}
+	if wt.Branch != "" {
+		wt.MergeRef, _ = git.GetBranchMergeRef(repo.Dir, wt.Branch)
+	}
+
+	name := wt.Branch
+	if name == "" {
+		name = filepath.Base(info.Path)
+	}
+	entry := worktreesEntry + "/" + repo.Alias + "/" + name
+
+	if git.HasUnpushedCommits(info.Path) {
+		bundle, err := os.CreateTemp("", "wt-bundle-")
+		if err != nil {
+			return wt, err
+		}
+		bundle.Close()
+		defer os.Remove(bundle.Name())
+
+		if err := git.CreateBundle(info.Path, bundle.Name()); err != nil {
+			return wt, fmt.Errorf("failed to bundle unpushed commits: %w", err)
+		}
+		wt.Bundle = entry + ".bundle"
+		if err := archive.addFile(wt.Bundle, bundle.Name()); err != nil {
+			return wt, err
+		}
+	}
+
+	if opts.IncludePatches && wt.Dirty {
+		patch, err := git.DiffWorkingTree(info.Path)
+		if err != nil {
+			return wt, err
+		}
+		if len(patch) > 0 {
+			wt.Patch = entry + ".patch"
+			if err := archive.addBytes(wt.Patch, patch, 0644); err != nil {
+				return wt, err
+			}
+		}
+	}
+
+	return wt, nil
+}
@@ -0,0 +1,169 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/testutil"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupMachine creates a home with config, state, a custom script and a repo cloned from a bare origin
+func setupMachine(t *testing.T) (*state.State, string) {
+	t.Helper()
+	testutil.SetupHome(t)
+	origin, _ := testutil.NewOriginRepo(t)
+
+	repoDir := filepath.Join(consts.GetDirectoryPaths().DefaultGitReposDir, "app")
+	testutil.RunGit(t, "", "clone", "--quiet", origin, repoDir)
+
+	appState := &state.State{ActiveRepo: "app", Repos: []state.Repo{{Alias: "app", Dir: repoDir, BaseBranch: "main"}}}
+	if err := appState.Save(); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, consts.GetFilePaths().Config, `{"config-editor": "vi", "picker": "fzf"}`)
+	writeFile(t, consts.GetFilePaths().PostWorktreeAddScript("app"), "#!/bin/bash\nnpm install\n")
+
+	return appState, origin
+}
+
+func addWorktree(t *testing.T, repoDir string, args ...string) string {
+	t.Helper()
+	path := args[len(args)-2]
+	testutil.RunGit(t, repoDir, append([]string{"worktree", "add", "--quiet"}, args...)...)
+	return path
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	appState, origin := setupMachine(t)
+	repoDir := appState.Repos[0].Dir
+	worktreesDir := filepath.Join(consts.GetDirectoryPaths().DefaultWorktreesDir, "app")
+
+	// A pushed, clean branch
+	pushed := addWorktree(t, repoDir, "-b", "pushed", filepath.Join(worktreesDir, "pushed"), "origin/main")
+	writeFile(t, filepath.Join(pushed, "pushed.txt"), "pushed\n")
+	testutil.RunGit(t, pushed, "add", ".")
+	testutil.RunGit(t, pushed, "commit", "--quiet", "-m", "Pushed work")
+	testutil.RunGit(t, pushed, "push", "--quiet", "--set-upstream", "origin", "pushed")
+
+	// An unpushed commit with staged, unstaged and untracked changes on top
+	local := addWorktree(t, repoDir, "-b", "feature/local", filepath.Join(worktreesDir, "feature", "local"), "origin/main")
+	writeFile(t, filepath.Join(local, "local.txt"), "committed\n")
+	testutil.RunGit(t, local, "add", ".")
+	testutil.RunGit(t, local, "commit", "--quiet", "-m", "Local work")
+	localHead := testutil.RunGit(t, local, "rev-parse", "HEAD")
+	writeFile(t, filepath.Join(local, "local.txt"), "committed\nunstaged\n")
+	writeFile(t, filepath.Join(local, "staged.txt"), "staged\n")
+	testutil.RunGit(t, local, "add", "staged.txt")
+	writeFile(t, filepath.Join(local, "untracked.txt"), "untracked\n")
+
+	// A detached worktree
+	addWorktree(t, repoDir, "--detach", filepath.Join(worktreesDir, "detached"), "origin/main")
+
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+	m, err := Create(appState, archivePath, CreateOptions{IncludePatches: true})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if len(m.Repos) != 1 || len(m.Repos[0].Worktrees) != 3 {
+		t.Fatalf("Expected one repo with three worktrees, got %+v", m.Repos)
+	}
+
+	// The index of the backed up worktree is untouched by collecting the patch
+	if status := testutil.RunGit(t, local, "status", "--porcelain"); !strings.Contains(status, "?? untracked.txt") || !strings.Contains(status, "A  staged.txt") {
+		t.Errorf("Expected the worktree's index to be unchanged, got:\n%s", status)
+	}
+
+	// A new laptop: another home directory, with the origin still reachable
+	testutil.SetupHome(t)
+
+	results, err := Restore(archivePath, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("Restore failed: %v (%+v)", err, results)
+	}
+
+	newRepoDir := filepath.Join(consts.GetDirectoryPaths().DefaultGitReposDir, "app")
+	var restoredState state.State
+	if err := fileops.ReadJSONFile(consts.GetFilePaths().State, &restoredState); err != nil {
+		t.Fatal(err)
+	}
+	if dir := fileops.ExpandEnvVars(restoredState.Repos[0].Dir); dir != newRepoDir || restoredState.Repos[0].BaseBranch != "main" {
+		t.Errorf("Expected the state to point at %s, got %+v", newRepoDir, restoredState.Repos[0])
+	}
+	if data, _ := os.ReadFile(consts.GetFilePaths().PostWorktreeAddScript("app")); string(data) != "#!/bin/bash\nnpm install\n" {
+		t.Errorf("Expected the scripts to be restored, got %q", data)
+	}
+	if url := testutil.RunGit(t, newRepoDir, "remote", "get-url", "origin"); url != origin {
+		t.Errorf("Expected the repository to be cloned from %s, got %s", origin, url)
+	}
+
+	newWorktreesDir := filepath.Join(consts.GetDirectoryPaths().DefaultWorktreesDir, "app")
+	newLocal := filepath.Join(newWorktreesDir, "feature", "local")
+	if head := testutil.RunGit(t, newLocal, "rev-parse", "HEAD"); head != localHead {
+		t.Errorf("Expected feature/local at %s, got %s", localHead, head)
+	}
+	for file, content := range map[string]string{
+		"local.txt":     "committed\nunstaged\n",
+		"staged.txt":    "staged\n",
+		"untracked.txt": "untracked\n",
+	} {
+		if data, _ := os.ReadFile(filepath.Join(newLocal, file)); string(data) != content {
+			t.Errorf("Expected %s to hold %q, got %q", file, content, data)
+		}
+	}
+
+	newPushed := filepath.Join(newWorktreesDir, "pushed")
+	if upstream := testutil.RunGit(t, newPushed, "rev-parse", "--abbrev-ref", "@{upstream}"); upstream != "origin/pushed" {
+		t.Errorf("Expected pushed to track origin/pushed, got %s", upstream)
+	}
+	if wts, _ := git.ListWorktrees(newRepoDir); len(wts) != 4 {
+		t.Errorf("Expected the main checkout and three worktrees, got %+v", wts)
+	}
+
+	// Restoring again needs --force and then leaves existing worktrees alone
+	if _, err := Restore(archivePath, RestoreOptions{}); err == nil {
+		t.Error("Expected restoring over an existing setup to need --force")
+	}
+	results, err = Restore(archivePath, RestoreOptions{Force: true})
+	if err != nil {
+		t.Fatalf("Forced restore failed: %v", err)
+	}
+	for _, result := range results {
+		if result.Result != restoreSkipped {
+			t.Errorf("Expected existing worktrees to be skipped, got %+v", result)
+		}
+	}
+}
+
+func TestRestore_NotABackup(t *testing.T) {
+	testutil.SetupHome(t)
+	archivePath := filepath.Join(t.TempDir(), "other.tar.gz")
+	archive, err := createArchive(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive.addBytes("notes.txt", []byte("hello"), 0644)
+	archive.close()
+
+	if _, err := Restore(archivePath, RestoreOptions{}); err == nil || !strings.Contains(err.Error(), "not a worktree-manager backup") {
+		t.Errorf("Expected an error for an archive without a manifest, got %v", err)
+	}
+	if fileops.FileExists(consts.GetFilePaths().Config) {
+		t.Error("Expected nothing to be restored")
+	}
+}
@@ -0,0 +1,228 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+)
+
+// RestoreOptions controls how a backup is restored
+type RestoreOptions struct {
+	Force bool
+}
+
+// RestoreResult records the outcome of restoring a repository or one of its worktrees
+type RestoreResult struct {
+	Repo     string
+	Worktree string
+	Result   string
+	Detail   string
+}
+
+const (
+	restoreCloned   = "cloned"
+	restoreRestored = "restored"
+	restoreSkipped  = "skipped"
+	restoreFailed   = "failed"
+)
+
+// Restore puts back the config, state and scripts of a backup, re-clones missing repositories and
+// recreates their worktrees at the recorded commits, applying any saved uncommitted changes
+func Restore(archivePath string, opts RestoreOptions) ([]RestoreResult, error) {
+	paths := consts.GetFilePaths()
+	if !opts.Force && (fileops.FileExists(paths.Config) || fileops.FileExists(paths.State)) {
+		return nil, fmt.Errorf("worktree-manager is already set up at %s\n\n💡 Use --force to replace its config, state and scripts with the backup", consts.GetDirectoryPaths().WorktreeManagerDir)
+	}
+
+	extracted, err := os.MkdirTemp("", "wt-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(extracted)
+
+	if err := extractArchive(archivePath, extracted); err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := fileops.ReadJSONFile(filepath.Join(extracted, manifestEntry), &m); err != nil {
+		return nil, fmt.Errorf("%s is not a worktree-manager backup: %w", archivePath, err)
+	}
+	if m.Version > Version {
+		return nil, fmt.Errorf("the backup was made by a newer wt (format %d, this wt reads up to %d)", m.Version, Version)
+	}
+
+	if err := restoreFiles(extracted); err != nil {
+		return nil, err
+	}
+	output.Success("Restored config, state and scripts from the backup taken %s", m.CreatedAt.Local().Format("2006-01-02 15:04"))
+
+	var results []RestoreResult
+	for _, repo := range m.Repos {
+		results = append(results, restoreRepo(extracted, repo)...)
+	}
+
+	PrintRestoreSummary(results)
+
+	failed := 0
+	for _, result := range results {
+		if result.Result == restoreFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d item(s) failed to restore", failed)
+	}
+	return results, nil
+}
+
+// restoreFiles copies config.json, state.json and the scripts out of the extracted backup
+func restoreFiles(extracted string) error {
+	dirs := consts.GetDirectoryPaths()
+	paths := consts.GetFilePaths()
+
+	for _, dir := range []string{dirs.WorktreeManagerDir, dirs.StateDir, dirs.DefaultGitReposDir, dirs.DefaultWorktreesDir} {
+		if err := fileops.EnsureDir(dir); err != nil {
+			return err
+		}
+	}
+
+	if err := fileops.CopyFile(filepath.Join(extracted, configEntry), paths.Config); err != nil {
+		return fmt.Errorf("failed to restore config: %w", err)
+	}
+	if err := fileops.CopyFile(filepath.Join(extracted, stateEntry), paths.State); err != nil {
+		return fmt.Errorf("failed to restore state: %w", err)
+	}
+
+	scripts := filepath.Join(extracted, scriptsEntry)
+	if fileops.FileExists(scripts) {
+		if err := fileops.CopyTree(scripts, dirs.ScriptsDir, fileops.CopyFile); err != nil {
+			return fmt.Errorf("failed to restore scripts: %w", err)
+		}
+	}
+	return nil
+}
+
+func restoreRepo(extracted string, repo RepoSnapshot) []RestoreResult {
+	dir := fileops.ExpandEnvVars(repo.Dir)
+	var results []RestoreResult
+
+	if !fileops.FileExists(dir) {
+		if repo.URL == "" {
+			return []RestoreResult{{Repo: repo.Alias, Result: restoreFailed, Detail: fmt.Sprintf("%s is missing and no url was recorded", dir)}}
+		}
+		if err := fileops.EnsureDir(filepath.Dir(dir)); err != nil {
+			return []RestoreResult{{Repo: repo.Alias, Result: restoreFailed, Detail: err.Error()}}
+		}
+		output.Progress("Cloning %s into %s...", repo.URL, dir)
+		if err := git.Clone(repo.URL, dir, nil); err != nil {
+			return []RestoreResult{{Repo: repo.Alias, Result: restoreFailed, Detail: fmt.Sprintf("failed to clone %s: %v", repo.URL, err)}}
+		}
+		results = append(results, RestoreResult{Repo: repo.Alias, Result: restoreCloned, Detail: repo.URL})
+	} else if len(repo.Worktrees) > 0 {
+		if err := git.FetchFromOrigin(dir); err != nil {
+			output.Warning("Failed to fetch %s, restoring from what is already there: %v", repo.Alias, err)
+		}
+	}
+
+	for _, wt := range repo.Worktrees {
+		result := restoreWorktree(extracted, dir, wt)
+		result.Repo = repo.Alias
+		results = append(results, result)
+	}
+	return results
+}
+
+func restoreWorktree(extracted, repoDir string, wt WorktreeSnapshot) RestoreResult {
+	worktreePath := fileops.ExpandEnvVars(wt.Path)
+	label := wt.Branch
+	if label == "" {
+		label = fmt.Sprintf("(detached) %s", filepath.Base(worktreePath))
+	}
+	result := RestoreResult{Worktree: label}
+	fail := func(format string, args ...interface{}) RestoreResult {
+		result.Result = restoreFailed
+		result.Detail = fmt.Sprintf(format, args...)
+		return result
+	}
+
+	if fileops.FileExists(worktreePath) {
+		result.Result = restoreSkipped
+		result.Detail = fmt.Sprintf("%s already exists", worktreePath)
+		return result
+	}
+
+	if wt.Bundle != "" {
+		if err := git.FetchBundle(repoDir, filepath.Join(extracted, filepath.FromSlash(wt.Bundle))); err != nil {
+			return fail("failed to fetch unpushed commits: %v", err)
+		}
+	}
+	if !git.CommitExists(repoDir, wt.Head) {
+		return fail("commit %s is not available in the repository", shortCommit(wt.Head))
+	}
+
+	if err := fileops.EnsureDir(filepath.Dir(worktreePath)); err != nil {
+		return fail("%v", err)
+	}
+
+	opts := git.WorktreeCreateOptions{Branch: wt.Branch, WorktreePath: worktreePath, SourceBranch: wt.Head, CreateBranch: true}
+	var notes []string
+	switch {
+	case wt.Branch == "":
+		opts = git.WorktreeCreateOptions{WorktreePath: worktreePath, SourceBranch: wt.Head, Detach: true}
+	case git.LocalBranchExists(repoDir, wt.Branch):
+		// An existing branch is checked out as it is rather than moved
+		opts = git.WorktreeCreateOptions{Branch: wt.Branch, WorktreePath: worktreePath, SourceBranch: wt.Branch}
+		notes = append(notes, "branch already existed and was kept as is")
+	}
+	if err := git.CreateWorktree(repoDir, opts); err != nil {
+		return fail("failed to create worktree: %v", err)
+	}
+
+	if wt.MergeRef != "" {
+		if err := git.SetBranchUpstream(repoDir, wt.Branch, wt.MergeRef); err != nil {
+			notes = append(notes, fmt.Sprintf("upstream not set: %v", err))
+		}
+	}
+
+	if wt.Patch != "" {
+		if err := git.ApplyPatch(worktreePath, filepath.Join(extracted, filepath.FromSlash(wt.Patch))); err != nil {
+			return fail("worktree created, but its uncommitted changes did not apply: %v", err)
+		}
+		notes = append(notes, "uncommitted changes applied")
+	} else if wt.Dirty {
+		notes = append(notes, "uncommitted changes were not backed up")
+	}
+
+	result.Result = restoreRestored
+	result.Detail = "at " + shortCommit(wt.Head)
+	for _, note := range notes {
+		result.Detail += ", " + note
+	}
+	return result
+}
+
+func shortCommit(commit string) string {
+	return git.Worktree{Head: commit}.ShortHead()
+}
+
+// PrintRestoreSummary displays the outcome of a restore
+func PrintRestoreSummary(results []RestoreResult) {
+	if len(results) == 0 {
+		output.Hint("The backup holds no repositories")
+		return
+	}
+
+	output.Info("Restore summary:")
+
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, []string{result.Repo, result.Worktree, result.Result, result.Detail})
+	}
+	output.Table([]string{"REPO", "WORKTREE", "RESULT", "DETAIL"}, rows)
+}
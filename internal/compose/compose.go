@@ -0,0 +1,91 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"worktree-manager/internal/executors"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/output"
+)
+
+// Files are the names docker compose looks for in a project directory, in its order of preference
+var Files = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+type ComposeOperations struct {
+	cmdExecutor executors.CommandExecutor
+	ctx         context.Context
+}
+
+func NewComposeOperations() *ComposeOperations {
+	return NewComposeOperationsWithExecutor(executors.NewSystemCommandExecutor())
+}
+
+// NewComposeOperationsWithExecutor runs docker through the given executor, such as a ReplayExecutor in tests
+func NewComposeOperationsWithExecutor(executor executors.CommandExecutor) *ComposeOperations {
+	return &ComposeOperations{cmdExecutor: executor, ctx: context.Background()}
+}
+
+var defaultComposeOps = NewComposeOperations()
+
+// SetDefaultExecutor makes the package-level functions run docker through executor and returns a function that restores the previous one
+func SetDefaultExecutor(executor executors.CommandExecutor) (restore func()) {
+	previous := defaultComposeOps
+	defaultComposeOps = NewComposeOperationsWithExecutor(executor)
+	return func() { defaultComposeOps = previous }
+}
+
+// FindFile returns the compose file docker compose would use in dir, or "" if there is none
+func FindFile(dir string) string {
+	for _, name := range Files {
+		if path := filepath.Join(dir, name); fileops.FileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+func Up(dir, project string, env []string) error {
+	return defaultComposeOps.Up(dir, project, env)
+}
+
+// Up creates and starts the services of the compose project in dir in the background. env is added to wt's own
+// environment, so compose files can refer to variables such as ${WT_PORT_WEB}
+func (c *ComposeOperations) Up(dir, project string, env []string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:     "docker",
+		Args:        []string{"compose", "--project-name", project, "up", "--detach"},
+		WorkingDir:  dir,
+		Env:         append(os.Environ(), env...),
+		ProgressMsg: "Starting compose project " + project,
+		ShowOutput:  true,
+		// Pulling and building images can take a while, so their progress is shown as it happens
+		Stdout: output.Stdout(),
+		Stderr: output.Stderr(),
+	}
+	_, err := c.cmdExecutor.Execute(c.ctx, ctx)
+	return err
+}
+
+func Down(dir, project string, env []string, removeVolumes bool) error {
+	return defaultComposeOps.Down(dir, project, env, removeVolumes)
+}
+
+// Down stops and removes the containers and networks of a compose project, and its volumes if removeVolumes is set
+func (c *ComposeOperations) Down(dir, project string, env []string, removeVolumes bool) error {
+	args := []string{"compose", "--project-name", project, "down", "--remove-orphans"}
+	if removeVolumes {
+		args = append(args, "--volumes")
+	}
+	ctx := &executors.CommandExecutionContext{
+		Command:     "docker",
+		Args:        args,
+		WorkingDir:  dir,
+		Env:         append(os.Environ(), env...),
+		ProgressMsg: "Stopping compose project " + project,
+		ShowOutput:  true,
+	}
+	_, err := c.cmdExecutor.Execute(c.ctx, ctx)
+	return err
+}
@@ -4,14 +4,42 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
+	"time"
+
 	"worktree-manager/internal/consts"
 	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/state"
 )
 
 // Config represents the user configuration settings
 type Config struct {
-	ConfigEditor            string `json:"config-editor"`
-	AutomaticWorkOnAfterAdd bool   `json:"automatic-work-on-after-add"`
+	ConfigEditor            string              `json:"config-editor"`
+	AutomaticWorkOnAfterAdd bool                `json:"automatic-work-on-after-add"`
+	SyncStrategy            string              `json:"sync-strategy,omitempty"`
+	Forges                  []ForgeConfig       `json:"forges,omitempty"`
+	BranchTemplate          string              `json:"branch-template,omitempty"`
+	IssueTracker            *IssueTrackerConfig `json:"issue-tracker,omitempty"`
+	Picker                  string              `json:"picker,omitempty"`
+	WorkonEditor            string              `json:"workon-editor,omitempty"`
+	ReposDir                string              `json:"repos-dir,omitempty"`
+	WorktreesDir            string              `json:"worktrees-dir,omitempty"`
+	GitBackend              string              `json:"git-backend,omitempty"`
+	HookTimeouts            map[string]string   `json:"hook-timeouts,omitempty"`
+	AsyncPostWorktreeAdd    bool                `json:"async-post-worktree-add,omitempty"`
+	HookCommands            state.HookCommands  `json:"hook-commands,omitempty"`
+	EnvFile                 bool                `json:"env-file,omitempty"`
+	Direnv                  bool                `json:"direnv,omitempty"`
+	ComposeUp               bool                `json:"compose-up,omitempty"`
+	DedupeDirs              []string            `json:"dedupe-dirs,omitempty"`
+}
+
+// ForgeConfig holds the API settings for a code forge host
+type ForgeConfig struct {
+	Host    string `json:"host"`
+	Type    string `json:"type"`
+	BaseURL string `json:"base-url,omitempty"`
+	Token   string `json:"token,omitempty"`
 }
 
 var (
@@ -30,6 +58,7 @@ func Load() (*Config, error) {
 	}
 
 	cfg = &config
+	cfg.applyDirectories()
 	return cfg, nil
 }
 
@@ -51,6 +80,9 @@ func CreateDefault(force ...bool) error {
 	defaultConfig := Config{
 		ConfigEditor:            defaults.ConfigEditor,
 		AutomaticWorkOnAfterAdd: defaults.AutomaticWorkOnAfterAdd,
+		SyncStrategy:            defaults.SyncStrategy,
+		BranchTemplate:          defaults.BranchTemplate,
+		Picker:                  defaults.Picker,
 	}
 
 	configPath := consts.GetFilePaths().Config
@@ -68,9 +100,110 @@ func CreateDefault(force ...bool) error {
 
 // Save saves the current configuration
 func (c *Config) Save() error {
+	c.applyDirectories()
 	return fileops.WriteJSONFile(consts.GetFilePaths().Config, c)
 }
 
+// applyDirectories points new clones and worktrees at the configured repos and worktrees directories
+func (c *Config) applyDirectories() {
+	consts.SetDirectoryOverrides(consts.DirectoryOverrides{
+		ReposDir:     expandDir(c.ReposDir),
+		WorktreesDir: expandDir(c.WorktreesDir),
+	})
+}
+
+// expandDir expands environment variables and a leading ~ in a configured directory
+func expandDir(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		dir = "$HOME" + dir[1:]
+	}
+	return filepath.Clean(fileops.ExpandEnvVars(dir))
+}
+
+// IssueTrackerConfig describes where issues are looked up for 'wt tree add --issue'
+type IssueTrackerConfig struct {
+	Type  string `json:"type"`
+	Path  string `json:"path,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+// GetSyncStrategy returns the configured sync strategy, falling back to the default
+func (c *Config) GetSyncStrategy() string {
+	if c.SyncStrategy == "" {
+		return consts.GetConfigDefaults().SyncStrategy
+	}
+	return c.SyncStrategy
+}
+
+// GetBranchTemplate returns the configured branch name template, falling back to the default
+func (c *Config) GetBranchTemplate() string {
+	if c.BranchTemplate == "" {
+		return consts.GetConfigDefaults().BranchTemplate
+	}
+	return c.BranchTemplate
+}
+
+// GetPicker returns the configured picker command, or "builtin" for the built-in fuzzy finder
+func (c *Config) GetPicker() string {
+	if c.Picker == "" {
+		return consts.GetConfigDefaults().Picker
+	}
+	return c.Picker
+}
+
+// GetGitBackend returns the backend read-only git operations use, falling back to the git CLI
+func (c *Config) GetGitBackend() string {
+	if c.GitBackend == "" {
+		return consts.GetConfigDefaults().GitBackend
+	}
+	return c.GitBackend
+}
+
+// GetHookTimeout returns how long a hook may run, from hook-timeouts or the hook's default; zero means no limit.
+// Values are durations such as "90s" or "15m", with "0" turning the timeout off
+func (c *Config) GetHookTimeout(hook string) (time.Duration, error) {
+	defaultTimeout := consts.DefaultHookTimeouts[hook]
+
+	value, ok := c.HookTimeouts[hook]
+	if !ok {
+		return defaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return defaultTimeout, fmt.Errorf("invalid timeout '%s' for the %s hook in hook-timeouts (use a duration such as 90s or 15m, or 0 for none)", value, hook)
+	}
+	return timeout, nil
+}
+
+// GetDedupeDirs returns the directories 'wt dedupe' links identical files in, relative to the root of each worktree.
+// They must stay within the worktree
+func (c *Config) GetDedupeDirs() ([]string, error) {
+	dirs := make([]string, 0, len(c.DedupeDirs))
+	for _, dir := range c.DedupeDirs {
+		clean := filepath.Clean(dir)
+		if dir == "" || filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("invalid directory '%s' in dedupe-dirs (use a path relative to the worktree, such as node_modules)", dir)
+		}
+		dirs = append(dirs, clean)
+	}
+	return dirs, nil
+}
+
+// GetWorkonEditor returns the editor worktrees are opened with, falling back to the config editor
+func (c *Config) GetWorkonEditor() string {
+	if c.WorkonEditor != "" {
+		return c.WorkonEditor
+	}
+	if c.ConfigEditor != "" {
+		return c.ConfigEditor
+	}
+	return consts.GetConfigDefaults().ConfigEditor
+}
+
 // GetConfigFromContext extracts config from context
 func GetConfigFromContext(ctx context.Context) *Config {
 	return ctx.Value(consts.GetContextKeys().Config).(*Config)
@@ -7,8 +7,18 @@ import (
 type ConfigDefaults struct {
 	ConfigEditor            string
 	AutomaticWorkOnAfterAdd bool
+	SyncStrategy            string
+	BranchTemplate          string
+	Picker                  string
+	GitBackend              string
 }
 
+// SyncStrategies lists the accepted values for the sync strategy
+var SyncStrategies = []string{"ff-only", "rebase"}
+
+// GitBackends lists the accepted values for the git backend
+var GitBackends = []string{"cli", "go-git"}
+
 func GetConfigDefaults() ConfigDefaults {
 
 	configEditor := os.Getenv("EDITOR")
@@ -20,5 +30,9 @@ func GetConfigDefaults() ConfigDefaults {
 	return ConfigDefaults{
 		ConfigEditor:            configEditor,
 		AutomaticWorkOnAfterAdd: true,
+		SyncStrategy:            "ff-only",
+		BranchTemplate:          "{{.Key}}-{{.Title | slug}}",
+		Picker:                  "builtin",
+		GitBackend:              "cli",
 	}
 }
@@ -1,34 +1,167 @@
 package consts
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 )
 
+// HomeEnvVar names the environment variable that moves everything worktree-manager keeps into one directory
+const HomeEnvVar = "WT_HOME"
+
+// legacyDirName is the directory under the user's home that held everything before the XDG layout
+const legacyDirName = ".worktree-manager"
+
+// appDirName is the directory created inside each XDG base directory
+const appDirName = "worktree-manager"
+
 type DirectoryPaths struct {
 	WorktreeManagerDir  string
+	StateDir            string
 	DefaultGitReposDir  string
 	DefaultWorktreesDir string
 	ScriptsDir          string
 	RepoScriptsDir      func(string) string
+	WorkspacesDir       string
+	LogsDir             string
 }
 
-func GetDirectoryPaths() DirectoryPaths {
+// Layout says where config (config.json and scripts), state (state.json) and data (repos, worktrees and generated files) live
+type Layout struct {
+	ConfigDir string
+	StateDir  string
+	DataDir   string
+}
+
+// DirectoryOverrides holds the repos and worktrees directories set in config.json
+type DirectoryOverrides struct {
+	ReposDir     string
+	WorktreesDir string
+}
+
+var directoryOverrides DirectoryOverrides
+
+// SetDirectoryOverrides changes where repositories are cloned and worktrees created; empty fields keep the layout's directories
+func SetDirectoryOverrides(overrides DirectoryOverrides) {
+	directoryOverrides = overrides
+}
+
+// GetDirectoryOverrides returns the directories set with SetDirectoryOverrides
+func GetDirectoryOverrides() DirectoryOverrides {
+	return directoryOverrides
+}
+
+// SingleRootLayout keeps config, state and data together in root, as WT_HOME and ~/.worktree-manager do
+func SingleRootLayout(root string) Layout {
+	return Layout{ConfigDir: root, StateDir: root, DataDir: root}
+}
+
+// LegacyRoot returns ~/.worktree-manager
+func LegacyRoot() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, legacyDirName), nil
+}
+
+// XDGLayout places config, state and data in the XDG base directories, with their defaults under the home directory
+func XDGLayout() (Layout, error) {
+	configHome, err := xdgBaseDir("XDG_CONFIG_HOME", ".config")
+	if err != nil {
+		return Layout{}, err
+	}
+	stateHome, err := xdgBaseDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+	if err != nil {
+		return Layout{}, err
+	}
+	dataHome, err := xdgBaseDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	if err != nil {
+		return Layout{}, err
+	}
+
+	return Layout{
+		ConfigDir: filepath.Join(configHome, appDirName),
+		StateDir:  filepath.Join(stateHome, appDirName),
+		DataDir:   filepath.Join(dataHome, appDirName),
+	}, nil
+}
+
+// xdgBaseDir returns an XDG base directory; relative values are invalid per the specification and ignored
+func xdgBaseDir(envVar, defaultRel string) (string, error) {
+	if dir := os.Getenv(envVar); filepath.IsAbs(dir) {
+		return dir, nil
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		homeDir = "."
+		return "", err
 	}
+	return filepath.Join(homeDir, defaultRel), nil
+}
 
-	worktreeManagerDir := filepath.Join(homeDir, ".worktree-manager")
-	scriptsDir := filepath.Join(worktreeManagerDir, "scripts")
+// ResolveLayout picks the layout in use: WT_HOME if set, then an existing ~/.worktree-manager, then the XDG directories
+func ResolveLayout() (Layout, error) {
+	if root := os.Getenv(HomeEnvVar); root != "" {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return Layout{}, err
+		}
+		return SingleRootLayout(abs), nil
+	}
+
+	legacy, err := LegacyRoot()
+	if err != nil {
+		return Layout{}, errors.New("cannot determine the home directory\n\n💡 Set WT_HOME or pass --home to choose where worktree-manager keeps its files")
+	}
+	if _, err := os.Stat(legacy); err == nil {
+		return SingleRootLayout(legacy), nil
+	}
+
+	return XDGLayout()
+}
+
+// Directories returns the paths of a layout, with the repos and worktrees directories replaced by any overrides
+func (l Layout) Directories(overrides DirectoryOverrides) DirectoryPaths {
+	scriptsDir := filepath.Join(l.ConfigDir, "scripts")
+
+	reposDir := filepath.Join(l.DataDir, "repos")
+	if overrides.ReposDir != "" {
+		reposDir = overrides.ReposDir
+	}
+	worktreesDir := filepath.Join(l.DataDir, "worktrees")
+	if overrides.WorktreesDir != "" {
+		worktreesDir = overrides.WorktreesDir
+	}
 
 	return DirectoryPaths{
-		WorktreeManagerDir:  worktreeManagerDir,
-		DefaultGitReposDir:  filepath.Join(worktreeManagerDir, "repos"),
-		DefaultWorktreesDir: filepath.Join(worktreeManagerDir, "worktrees"),
+		WorktreeManagerDir:  l.ConfigDir,
+		StateDir:            l.StateDir,
+		DefaultGitReposDir:  reposDir,
+		DefaultWorktreesDir: worktreesDir,
 		ScriptsDir:          scriptsDir,
 		RepoScriptsDir: func(repoAlias string) string {
 			return filepath.Join(scriptsDir, repoAlias)
 		},
+		WorkspacesDir: filepath.Join(l.DataDir, "workspaces"),
+		LogsDir:       filepath.Join(l.StateDir, "logs"),
+	}
+}
+
+func GetDirectoryPaths() DirectoryPaths {
+	layout, err := ResolveLayout()
+	if err != nil {
+		// Commands check ResolveLayout before running, so this only keeps callers from having to handle the error.
+		// The fallback is absolute, so that nothing ends up relative to the current directory
+		layout = SingleRootLayout(fallbackRoot())
+	}
+	return layout.Directories(directoryOverrides)
+}
+
+// fallbackRoot is ~/.worktree-manager when the home directory is known, and a directory under the system's
+// temporary directory otherwise
+func fallbackRoot() string {
+	if homeDir, err := os.UserHomeDir(); err == nil && filepath.IsAbs(homeDir) {
+		return filepath.Join(homeDir, legacyDirName)
 	}
+	return filepath.Join(os.TempDir(), appDirName)
 }
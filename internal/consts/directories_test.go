@@ -0,0 +1,99 @@
+package consts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, env := range []string{HomeEnvVar, "XDG_CONFIG_HOME", "XDG_STATE_HOME", "XDG_DATA_HOME"} {
+		t.Setenv(env, "")
+	}
+	return home
+}
+
+func TestResolveLayout(t *testing.T) {
+	t.Run("defaults to the XDG directories", func(t *testing.T) {
+		home := setHome(t)
+		layout, err := ResolveLayout()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := Layout{
+			ConfigDir: filepath.Join(home, ".config", "worktree-manager"),
+			StateDir:  filepath.Join(home, ".local", "state", "worktree-manager"),
+			DataDir:   filepath.Join(home, ".local", "share", "worktree-manager"),
+		}
+		if layout != expected {
+			t.Errorf("Expected %+v, got %+v", expected, layout)
+		}
+	})
+
+	t.Run("honours XDG variables and ignores relative ones", func(t *testing.T) {
+		home := setHome(t)
+		t.Setenv("XDG_CONFIG_HOME", "/etc/xdg-config")
+		t.Setenv("XDG_STATE_HOME", "relative/state")
+		layout, _ := ResolveLayout()
+		if layout.ConfigDir != "/etc/xdg-config/worktree-manager" {
+			t.Errorf("Expected XDG_CONFIG_HOME to be used, got %s", layout.ConfigDir)
+		}
+		if layout.StateDir != filepath.Join(home, ".local", "state", "worktree-manager") {
+			t.Errorf("Expected the relative XDG_STATE_HOME to be ignored, got %s", layout.StateDir)
+		}
+	})
+
+	t.Run("keeps using an existing ~/.worktree-manager", func(t *testing.T) {
+		home := setHome(t)
+		t.Setenv("XDG_CONFIG_HOME", "/etc/xdg-config")
+		legacy := filepath.Join(home, ".worktree-manager")
+		os.Mkdir(legacy, 0755)
+		if layout, _ := ResolveLayout(); layout != SingleRootLayout(legacy) {
+			t.Errorf("Expected the legacy directory, got %+v", layout)
+		}
+	})
+
+	t.Run("WT_HOME wins", func(t *testing.T) {
+		home := setHome(t)
+		os.Mkdir(filepath.Join(home, ".worktree-manager"), 0755)
+		t.Setenv(HomeEnvVar, "/srv/wt")
+		if layout, _ := ResolveLayout(); layout != SingleRootLayout("/srv/wt") {
+			t.Errorf("Expected WT_HOME, got %+v", layout)
+		}
+	})
+}
+
+func TestDirectories_Overrides(t *testing.T) {
+	layout := SingleRootLayout("/srv/wt")
+
+	dirs := layout.Directories(DirectoryOverrides{})
+	if dirs.DefaultGitReposDir != "/srv/wt/repos" || dirs.DefaultWorktreesDir != "/srv/wt/worktrees" || dirs.ScriptsDir != "/srv/wt/scripts" {
+		t.Errorf("Unexpected directories: %+v", dirs)
+	}
+
+	dirs = layout.Directories(DirectoryOverrides{WorktreesDir: "/mnt/nvme/worktrees"})
+	if dirs.DefaultWorktreesDir != "/mnt/nvme/worktrees" || dirs.DefaultGitReposDir != "/srv/wt/repos" {
+		t.Errorf("Expected only the worktrees directory to move, got %+v", dirs)
+	}
+
+	xdg := Layout{ConfigDir: "/c", StateDir: "/s", DataDir: "/d"}
+	paths := FilePathsFor(xdg.Directories(DirectoryOverrides{}))
+	if paths.Config != "/c/config.json" || paths.State != "/s/state.json" || paths.PostWorktreeAddScript("app") != "/c/scripts/app/post-worktree-add.sh" {
+		t.Errorf("Unexpected file paths: %+v", paths)
+	}
+}
+
+func TestGetDirectoryPaths_AbsoluteWithoutHome(t *testing.T) {
+	setHome(t)
+	t.Setenv("HOME", "")
+	if _, err := ResolveLayout(); err == nil {
+		t.Skip("the home directory can be determined without HOME")
+	}
+
+	if dir := GetDirectoryPaths().WorktreeManagerDir; !filepath.IsAbs(dir) {
+		t.Errorf("Expected an absolute fallback directory, got %s", dir)
+	}
+}
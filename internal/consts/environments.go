@@ -8,9 +8,18 @@ type EnvironmentVariable struct {
 
 // EnvironmentVariables represents all environment variables used by the application
 type EnvironmentVariables struct {
-	RepoAlias    EnvironmentVariable
-	RepoDir      EnvironmentVariable
-	WorktreePath EnvironmentVariable
+	RepoAlias      EnvironmentVariable
+	RepoDir        EnvironmentVariable
+	WorktreePath   EnvironmentVariable
+	IssueKey       EnvironmentVariable
+	Branch         EnvironmentVariable
+	BaseRef        EnvironmentVariable
+	HeadSHA        EnvironmentVariable
+	Operation      EnvironmentVariable
+	NewWorktree    EnvironmentVariable
+	WorktreeIndex  EnvironmentVariable
+	ComposeProject EnvironmentVariable
+	Port           EnvironmentVariable
 }
 
 // GetEnvironmentVariables returns all environment variables with names and descriptions
@@ -28,5 +37,41 @@ func GetEnvironmentVariables() EnvironmentVariables {
 			Name:        "WT_WORKTREE_PATH",
 			Description: "The path to the worktree",
 		},
+		IssueKey: EnvironmentVariable{
+			Name:        "WT_ISSUE_KEY",
+			Description: "The issue key the worktree was created for, if any",
+		},
+		Branch: EnvironmentVariable{
+			Name:        "WT_BRANCH",
+			Description: "The branch checked out in the worktree",
+		},
+		BaseRef: EnvironmentVariable{
+			Name:        "WT_BASE_REF",
+			Description: "The ref the worktree was created from, if known",
+		},
+		HeadSHA: EnvironmentVariable{
+			Name:        "WT_HEAD_SHA",
+			Description: "The commit checked out in the worktree",
+		},
+		Operation: EnvironmentVariable{
+			Name:        "WT_OPERATION",
+			Description: "What ran the hook: add, pull-request or work-on",
+		},
+		NewWorktree: EnvironmentVariable{
+			Name:        "WT_NEW_WORKTREE",
+			Description: "true if the worktree was just created, false otherwise",
+		},
+		WorktreeIndex: EnvironmentVariable{
+			Name:        "WT_WORKTREE_INDEX",
+			Description: "A number from 1 no other worktree of the repository holds while this one exists",
+		},
+		ComposeProject: EnvironmentVariable{
+			Name:        "WT_COMPOSE_PROJECT",
+			Description: "The docker compose project name of the worktree, <alias>-<branch-slug>",
+		},
+		Port: EnvironmentVariable{
+			Name:        "WT_PORT_<NAME>",
+			Description: "The worktree's port for each port slot of the repository: its base port plus the worktree index",
+		},
 	}
 }
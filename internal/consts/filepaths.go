@@ -7,18 +7,30 @@ type FilePathConstants struct {
 	State                 string
 	WorkOnScript          string
 	PostWorktreeAddScript func(string) string
+	CodeWorkspace         func(string, string) string
+	HookLogsDir           func(string, string) string
 }
 
 func GetFilePaths() FilePathConstants {
+	return FilePathsFor(GetDirectoryPaths())
+}
+
+// FilePathsFor returns the file paths within the given directories
+func FilePathsFor(directoryPaths DirectoryPaths) FilePathConstants {
 	fileNames := GetFileNames()
-	directoryPaths := GetDirectoryPaths()
 
 	return FilePathConstants{
 		Config:       filepath.Join(directoryPaths.WorktreeManagerDir, fileNames.Config),
-		State:        filepath.Join(directoryPaths.WorktreeManagerDir, fileNames.State),
+		State:        filepath.Join(directoryPaths.StateDir, fileNames.State),
 		WorkOnScript: filepath.Join(directoryPaths.ScriptsDir, fileNames.WorkOnScript),
 		PostWorktreeAddScript: func(repo string) string {
-			return filepath.Join(directoryPaths.ScriptsDir, repo, "post-worktree-add.sh")
+			return filepath.Join(directoryPaths.ScriptsDir, repo, fileNames.PostWorktreeAdd)
+		},
+		CodeWorkspace: func(repo, branch string) string {
+			return filepath.Join(directoryPaths.WorkspacesDir, repo, branch+".code-workspace")
+		},
+		HookLogsDir: func(repo, branch string) string {
+			return filepath.Join(directoryPaths.LogsDir, repo, branch)
 		},
 	}
 }
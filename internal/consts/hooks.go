@@ -0,0 +1,16 @@
+package consts
+
+import "time"
+
+// Names of the hooks, as used for their timeouts and log files
+const (
+	HookPostWorktreeAdd = "post-worktree-add"
+	HookWorkOn          = "work-on"
+)
+
+// DefaultHookTimeouts holds the timeouts hooks run with unless configured otherwise; zero means no timeout.
+// A hook with a timeout runs detached from the terminal, so scripts that prompt would break: timeouts are opt-in
+var DefaultHookTimeouts = map[string]time.Duration{
+	HookPostWorktreeAdd: 0,
+	HookWorkOn:          0,
+}
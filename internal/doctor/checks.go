@@ -0,0 +1,457 @@
+package doctor
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/hooks"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+var checks = []Check{
+	{
+		ID:          "config-file",
+		Description: "Config file exists and loads",
+		Severity:    SeverityError,
+		Detect:      detectConfigFile,
+		Fix:         fixConfigFile,
+	},
+	{
+		ID:          "config-values",
+		Description: "Config values are valid",
+		Severity:    SeverityWarning,
+		Detect:      detectConfigValues,
+	},
+	{
+		ID:          "state-file",
+		Description: "State file exists and loads",
+		Severity:    SeverityError,
+		Detect:      detectStateFile,
+		Fix:         fixStateFile,
+	},
+	{
+		ID:          "directories",
+		Description: "Repos, worktrees and scripts directories exist",
+		Severity:    SeverityWarning,
+		Detect:      detectDirectories,
+		Fix: func(env *Env, problem Problem) error {
+			return fileops.EnsureDir(problem.Subject)
+		},
+	},
+	{
+		ID:          "work-on-script",
+		Description: "Work-on script exists",
+		Severity:    SeverityWarning,
+		Detect:      detectWorkOnScript,
+		Fix: func(env *Env, problem Problem) error {
+			return hooks.WriteScript(problem.Subject, hooks.DefaultScript(consts.HookWorkOn, ""))
+		},
+	},
+	{
+		ID:          "missing-repos",
+		Description: "Repository directories exist",
+		Severity:    SeverityError,
+		Detect:      detectMissingRepos,
+		Fix: func(env *Env, problem Problem) error {
+			return env.State.ForgetRepo(problem.Repo)
+		},
+	},
+	{
+		ID:          "git-repos",
+		Description: "Repository directories are git repositories",
+		Severity:    SeverityError,
+		Detect:      detectGitRepos,
+	},
+	{
+		ID:          "post-add-scripts",
+		Description: "Post-worktree-add scripts exist",
+		Severity:    SeverityWarning,
+		Detect:      detectPostAddScripts,
+		Fix: func(env *Env, problem Problem) error {
+			return hooks.WriteScript(problem.Subject, hooks.DefaultScript(consts.HookPostWorktreeAdd, problem.Repo))
+		},
+	},
+	{
+		ID:          "hook-permissions",
+		Description: "Hook scripts are executable",
+		Severity:    SeverityWarning,
+		Detect:      detectHookPermissions,
+		Fix: func(env *Env, problem Problem) error {
+			info, err := os.Stat(problem.Subject)
+			if err != nil {
+				return err
+			}
+			return os.Chmod(problem.Subject, info.Mode().Perm()|0111)
+		},
+	},
+	{
+		ID:          "worktree-links",
+		Description: "Worktrees and their repositories are linked both ways",
+		Severity:    SeverityWarning,
+		Detect:      detectWorktreeLinks,
+		Fix: func(env *Env, problem Problem) error {
+			repo, err := env.State.FindRepoByAlias(problem.Repo)
+			if err != nil {
+				return err
+			}
+			return git.RepairWorktrees(repo.Dir, []string{problem.Subject})
+		},
+	},
+	{
+		ID:          "prunable-worktrees",
+		Description: "Worktree directories exist",
+		Severity:    SeverityWarning,
+		Detect:      detectPrunableWorktrees,
+		Fix:         fixPrunableWorktree,
+	},
+	{
+		ID:          "repo-settings",
+		Description: "Repository hooks, ports and variables are valid",
+		Severity:    SeverityWarning,
+		Detect:      detectRepoSettings,
+	},
+}
+
+func detectConfigFile(env *Env) []Problem {
+	path := consts.GetFilePaths().Config
+	if !config.CheckConfigExists() {
+		return []Problem{{Message: fmt.Sprintf("Config file does not exist: %s", path), Subject: path}}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return []Problem{{
+			Message: fmt.Sprintf("Failed to load config: %v", err),
+			Hint:    "Correct it with 'wt config edit', or recreate it with 'wt init --force'",
+			Manual:  true,
+		}}
+	}
+	env.Config = cfg
+	return nil
+}
+
+func fixConfigFile(env *Env, problem Problem) error {
+	if err := config.CreateDefault(); err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	env.Config = cfg
+	return nil
+}
+
+func detectConfigValues(env *Env) []Problem {
+	cfg := env.Config
+	if cfg == nil {
+		return nil
+	}
+
+	var problems []Problem
+	if err := git.SetDefaultBackend(cfg.GetGitBackend()); err != nil {
+		problems = append(problems, Problem{Message: fmt.Sprintf("%v, the git CLI will be used", err)})
+	}
+	for _, hook := range slices.Sorted(maps.Keys(cfg.HookTimeouts)) {
+		if _, err := cfg.GetHookTimeout(hook); err != nil {
+			problems = append(problems, Problem{Message: err.Error()})
+		}
+	}
+	if err := cfg.HookCommands.Validate(); err != nil {
+		problems = append(problems, Problem{Message: err.Error()})
+	}
+	if _, err := cfg.GetDedupeDirs(); err != nil {
+		problems = append(problems, Problem{Message: err.Error()})
+	}
+	return problems
+}
+
+func detectStateFile(env *Env) []Problem {
+	path := consts.GetFilePaths().State
+	if !state.StateExists() {
+		return []Problem{{Message: fmt.Sprintf("State file does not exist: %s", path), Subject: path}}
+	}
+
+	appState, err := state.Load()
+	if err != nil {
+		return []Problem{{
+			Message: fmt.Sprintf("Failed to load state: %v", err),
+			Hint:    fmt.Sprintf("Correct %s by hand, or restore it with 'wt backup restore'", path),
+			Manual:  true,
+		}}
+	}
+	env.State = appState
+	return nil
+}
+
+func fixStateFile(env *Env, problem Problem) error {
+	if err := state.CreateDefault(); err != nil {
+		return err
+	}
+	appState, err := state.Load()
+	if err != nil {
+		return err
+	}
+	env.State = appState
+	return nil
+}
+
+func detectDirectories(env *Env) []Problem {
+	paths := consts.GetDirectoryPaths()
+	dirs := []struct{ name, path string }{
+		{"Repos", paths.DefaultGitReposDir},
+		{"Worktrees", paths.DefaultWorktreesDir},
+		{"Scripts", paths.ScriptsDir},
+	}
+
+	var problems []Problem
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir.path); os.IsNotExist(err) {
+			problems = append(problems, Problem{Message: fmt.Sprintf("%s directory does not exist: %s", dir.name, dir.path), Subject: dir.path})
+		} else if err == nil && !info.IsDir() {
+			problems = append(problems, Problem{Message: fmt.Sprintf("%s directory is not a directory: %s", dir.name, dir.path), Manual: true})
+		}
+	}
+	return problems
+}
+
+func detectWorkOnScript(env *Env) []Problem {
+	path := consts.GetFilePaths().WorkOnScript
+	if fileops.FileExists(path) {
+		return nil
+	}
+	return []Problem{{
+		Message: fmt.Sprintf("Work-on script does not exist: %s", path),
+		Hint:    "Worktrees are worked on without it; 'wt hooks edit work-on' creates it as well",
+		Subject: path,
+	}}
+}
+
+func detectMissingRepos(env *Env) []Problem {
+	if env.State == nil {
+		return nil
+	}
+
+	var problems []Problem
+	for _, repo := range env.State.Repos {
+		if _, err := os.Stat(repo.Dir); os.IsNotExist(err) {
+			problems = append(problems, Problem{
+				Message: fmt.Sprintf("Directory of repository '%s' does not exist: %s", repo.Alias, repo.Dir),
+				Hint:    "Clone it again with 'wt repo clone <url>', or let the fix forget it; its scripts are kept",
+				Repo:    repo.Alias,
+			})
+		}
+	}
+	return problems
+}
+
+func detectGitRepos(env *Env) []Problem {
+	var problems []Problem
+	forEachRepo(env, func(repo *state.Repo) {
+		if !git.IsGitRepository(repo.Dir) {
+			problems = append(problems, Problem{
+				Message: fmt.Sprintf("Directory of repository '%s' is not a git repository: %s", repo.Alias, repo.Dir),
+				Hint:    "Move the repository back into place, or remove it with 'wt repo remove' and clone it again",
+				Repo:    repo.Alias,
+			})
+		}
+	})
+	return problems
+}
+
+func detectPostAddScripts(env *Env) []Problem {
+	var problems []Problem
+	forEachRepo(env, func(repo *state.Repo) {
+		path := hooks.ScriptPath(consts.HookPostWorktreeAdd, repo.Alias)
+		if !fileops.FileExists(path) {
+			problems = append(problems, Problem{
+				Message: fmt.Sprintf("Post-worktree-add script of '%s' does not exist: %s", repo.Alias, path),
+				Hint:    "The fix creates an empty one; 'wt hooks init' generates one from templates instead",
+				Repo:    repo.Alias,
+				Subject: path,
+			})
+		}
+	})
+	return problems
+}
+
+func detectHookPermissions(env *Env) []Problem {
+	scripts := []string{consts.GetFilePaths().WorkOnScript}
+	forEachRepo(env, func(repo *state.Repo) {
+		scripts = append(scripts, hooks.ScriptPath(consts.HookPostWorktreeAdd, repo.Alias))
+	})
+
+	var problems []Problem
+	for _, path := range scripts {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 == 0 {
+			problems = append(problems, Problem{
+				Message: fmt.Sprintf("Hook script is not executable: %s", path),
+				Subject: path,
+			})
+		}
+	}
+	return problems
+}
+
+func detectWorktreeLinks(env *Env) []Problem {
+	var problems []Problem
+	forEachGitRepo(env, func(repo *state.Repo, worktrees []git.Worktree) {
+		listed := make(map[string]bool)
+		for _, wt := range worktrees {
+			listed[resolvePath(wt.Path)] = true
+			if wt.Bare || wt.Prunable || resolvePath(wt.Path) == resolvePath(repo.Dir) {
+				continue
+			}
+			if gitDir, ok := linkedGitDir(wt.Path); ok && !fileops.FileExists(gitDir) {
+				problems = append(problems, Problem{
+					Message: fmt.Sprintf("Worktree %s points at a git directory that no longer exists: %s", wt.Path, gitDir),
+					Hint:    "This happens when a repository is moved by hand",
+					Repo:    repo.Alias,
+					Subject: wt.Path,
+				})
+			}
+		}
+
+		// Worktrees moved by hand are no longer known to git under their new path
+		root := filepath.Join(consts.GetDirectoryPaths().DefaultWorktreesDir, repo.Alias)
+		for _, path := range findWorktreeDirs(root) {
+			if !listed[resolvePath(path)] {
+				problems = append(problems, Problem{
+					Message: fmt.Sprintf("Worktree %s is not known to repository '%s'", path, repo.Alias),
+					Hint:    "This happens when a worktree is moved by hand",
+					Repo:    repo.Alias,
+					Subject: path,
+				})
+			}
+		}
+	})
+	return problems
+}
+
+func detectPrunableWorktrees(env *Env) []Problem {
+	var problems []Problem
+	forEachGitRepo(env, func(repo *state.Repo, worktrees []git.Worktree) {
+		for _, wt := range worktrees {
+			if wt.Prunable {
+				problems = append(problems, Problem{
+					Message: fmt.Sprintf("Worktree %s of '%s' no longer exists", wt.Path, repo.Alias),
+					Hint:    "If it was moved rather than deleted, repair it with --fix=worktree-links first",
+					Repo:    repo.Alias,
+					Subject: wt.ShortBranch(),
+				})
+			}
+		}
+	})
+	return problems
+}
+
+func fixPrunableWorktree(env *Env, problem Problem) error {
+	repo, err := env.State.FindRepoByAlias(problem.Repo)
+	if err != nil {
+		return err
+	}
+	if err := git.PruneWorktrees(repo.Dir); err != nil {
+		return err
+	}
+	if problem.Subject == "" {
+		return nil
+	}
+	return env.State.RemoveWorktreeMetadata(repo.Alias, problem.Subject)
+}
+
+func detectRepoSettings(env *Env) []Problem {
+	var problems []Problem
+	forEachRepo(env, func(repo *state.Repo) {
+		report := func(err error) {
+			if err != nil {
+				problems = append(problems, Problem{Message: fmt.Sprintf("Repository '%s': %v", repo.Alias, err), Repo: repo.Alias})
+			}
+		}
+		report(repo.Hooks.Validate())
+		report(repo.Ports.Validate())
+		report(worktree.ValidateRepoEnv(repo))
+
+		// Slots need room for one port per worktree above their base port
+		for _, conflict := range repo.Ports.Conflicts(max(repo.MaxWorktreeIndex(), len(repo.Worktrees))) {
+			problems = append(problems, Problem{
+				Message: fmt.Sprintf("Repository '%s': ports %s hand out the same port to different worktrees", repo.Alias, conflict),
+				Hint:    "Move their base ports further apart with 'wt repo ports'",
+				Repo:    repo.Alias,
+			})
+		}
+	})
+	return problems
+}
+
+// forEachRepo calls fn for every repository in the state whose directory exists
+func forEachRepo(env *Env, fn func(repo *state.Repo)) {
+	if env.State == nil {
+		return
+	}
+	for i := range env.State.Repos {
+		if fileops.FileExists(env.State.Repos[i].Dir) {
+			fn(&env.State.Repos[i])
+		}
+	}
+}
+
+// forEachGitRepo calls fn for every git repository in the state with the worktrees git lists for it
+func forEachGitRepo(env *Env, fn func(repo *state.Repo, worktrees []git.Worktree)) {
+	forEachRepo(env, func(repo *state.Repo) {
+		if !git.IsGitRepository(repo.Dir) {
+			return
+		}
+		if worktrees, err := git.ListWorktrees(repo.Dir); err == nil {
+			fn(repo, worktrees)
+		}
+	})
+}
+
+// linkedGitDir returns the git directory a worktree's .git file points at
+func linkedGitDir(worktreePath string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(worktreePath, ".git"))
+	if err != nil {
+		return "", false
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return "", false
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(worktreePath, gitDir)
+	}
+	return gitDir, true
+}
+
+// findWorktreeDirs returns the directories under root with a .git file, not looking inside them
+func findWorktreeDirs(root string) []string {
+	var dirs []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if info, err := os.Lstat(filepath.Join(path, ".git")); err == nil && info.Mode().IsRegular() {
+			dirs = append(dirs, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return dirs
+}
+
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,150 @@
+package doctor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/state"
+)
+
+// Severity tells how much a problem matters; only errors make 'wt doctor' fail
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// FixAll selects the fixes of every check
+const FixAll = "all"
+
+// Env is what checks inspect and repair. The config and state are loaded by their own checks, and are nil when they
+// could not be; checks that need them find nothing to report without them
+type Env struct {
+	Config *config.Config
+	State  *state.State
+}
+
+// Problem is something a check detected
+type Problem struct {
+	Message string
+	Hint    string
+	// Repo is the alias of the repository the problem belongs to, if any
+	Repo string
+	// Subject is what the problem is about, such as a path or a branch, for the check's fix
+	Subject string
+	// Manual problems are left to a person even by checks with a fix
+	Manual bool
+}
+
+// Check is a health check with an optional repair
+type Check struct {
+	ID          string
+	Description string
+	Severity    Severity
+	Detect      func(env *Env) []Problem
+	// Fix repairs a problem Detect found; nil for checks whose problems need a person
+	Fix func(env *Env, problem Problem) error
+}
+
+// Finding is a problem as reported, with the outcome of its fix when one was attempted
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Hint     string   `json:"hint,omitempty"`
+	Repo     string   `json:"repo,omitempty"`
+	Fixable  bool     `json:"fixable"`
+	Fixed    bool     `json:"fixed"`
+	FixError string   `json:"fix-error,omitempty"`
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Findings    []Finding `json:"findings"`
+}
+
+// Report is the outcome of all checks
+type Report struct {
+	Checks []CheckResult `json:"checks"`
+	// Errors and Warnings count the problems left unfixed
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	// Fixable counts the unfixed problems a check could repair
+	Fixable int `json:"fixable"`
+}
+
+// Checks returns the checks 'wt doctor' runs, in order; checks further down rely on the repairs of those above
+func Checks() []Check {
+	return slices.Clone(checks)
+}
+
+// ValidateFixes checks that every selected fix names a check, or is FixAll
+func ValidateFixes(fixes []string) error {
+	for _, id := range fixes {
+		if id == FixAll {
+			continue
+		}
+		i := slices.IndexFunc(checks, func(check Check) bool { return check.ID == id })
+		if i < 0 {
+			return fmt.Errorf("unknown check '%s' (available: %s)", id, strings.Join(checkIDs(), ", "))
+		}
+		if checks[i].Fix == nil {
+			return fmt.Errorf("check '%s' has no automatic fix", id)
+		}
+	}
+	return nil
+}
+
+func checkIDs() []string {
+	ids := make([]string, 0, len(checks))
+	for _, check := range checks {
+		ids = append(ids, check.ID)
+	}
+	return ids
+}
+
+// Run runs every check in order, repairing the problems of the checks selected by fixes as they are found
+func Run(env *Env, fixes []string) Report {
+	var report Report
+	for _, check := range checks {
+		fix := check.Fix != nil && (slices.Contains(fixes, FixAll) || slices.Contains(fixes, check.ID))
+
+		result := CheckResult{ID: check.ID, Description: check.Description, Findings: []Finding{}}
+		for _, problem := range check.Detect(env) {
+			finding := Finding{
+				Check:    check.ID,
+				Severity: check.Severity,
+				Message:  problem.Message,
+				Hint:     problem.Hint,
+				Repo:     problem.Repo,
+				Fixable:  check.Fix != nil && !problem.Manual,
+			}
+			if fix && finding.Fixable {
+				if err := check.Fix(env, problem); err != nil {
+					finding.FixError = err.Error()
+				} else {
+					finding.Fixed = true
+				}
+			}
+			result.Findings = append(result.Findings, finding)
+
+			switch {
+			case finding.Fixed:
+			case check.Severity == SeverityError:
+				report.Errors++
+			default:
+				report.Warnings++
+			}
+			if finding.Fixable && !finding.Fixed {
+				report.Fixable++
+			}
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
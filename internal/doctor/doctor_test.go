@@ -0,0 +1,76 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/state"
+)
+
+func findings(report Report, id string) []Finding {
+	for _, result := range report.Checks {
+		if result.ID == id {
+			return result.Findings
+		}
+	}
+	return nil
+}
+
+// Config and state are loaded once per process, so the whole repair sequence runs in a single test
+func TestRunAndFix(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("WT_HOME", filepath.Join(home, ".worktree-manager"))
+
+	report := Run(&Env{}, nil)
+	if report.Errors != 2 || len(findings(report, "config-file")) != 1 || len(findings(report, "state-file")) != 1 {
+		t.Fatalf("Expected missing config and state files to be errors, got %+v", report)
+	}
+	if _, err := os.Stat(consts.GetFilePaths().Config); !os.IsNotExist(err) {
+		t.Fatal("Expected detection alone to leave the config file alone")
+	}
+
+	env := &Env{}
+	report = Run(env, []string{FixAll})
+	if report.Errors != 0 || report.Warnings != 0 {
+		t.Fatalf("Expected every problem of a new home to be fixed, got %+v", report)
+	}
+	for _, path := range []string{consts.GetFilePaths().Config, consts.GetFilePaths().State, consts.GetFilePaths().WorkOnScript, consts.GetDirectoryPaths().DefaultWorktreesDir} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected the fixes to create %s: %v", path, err)
+		}
+	}
+
+	// Only the selected check is fixed
+	env.State.Repos = append(env.State.Repos, state.Repo{Alias: "gone", Dir: filepath.Join(home, "gone")})
+	env.State.ActiveRepo = "gone"
+	os.Chmod(consts.GetFilePaths().WorkOnScript, 0644)
+
+	report = Run(env, []string{"missing-repos"})
+	if missing := findings(report, "missing-repos"); len(missing) != 1 || !missing[0].Fixed {
+		t.Errorf("Expected the missing repository to be forgotten, got %+v", missing)
+	}
+	if len(env.State.Repos) != 0 || env.State.ActiveRepo != "" {
+		t.Errorf("Expected no repositories left, got %+v", env.State)
+	}
+	if perms := findings(report, "hook-permissions"); len(perms) != 1 || perms[0].Fixed || !perms[0].Fixable {
+		t.Errorf("Expected the non-executable work-on script to be reported but not fixed, got %+v", perms)
+	}
+	if report.Errors != 0 || report.Warnings != 1 || report.Fixable != 1 {
+		t.Errorf("Expected one fixable warning left, got %+v", report)
+	}
+}
+
+func TestValidateFixes(t *testing.T) {
+	if err := ValidateFixes([]string{FixAll, "directories"}); err != nil {
+		t.Errorf("Expected all and a check with a fix to be accepted, got %v", err)
+	}
+	if err := ValidateFixes([]string{"git-repos"}); err == nil {
+		t.Error("Expected an error for a check without a fix, got nil")
+	}
+	if err := ValidateFixes([]string{"bogus"}); err == nil {
+		t.Error("Expected an error for an unknown check, got nil")
+	}
+}
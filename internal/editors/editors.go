@@ -0,0 +1,153 @@
+package editors
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Kind groups editors that are launched the same way
+type Kind string
+
+const (
+	KindVSCode    Kind = "vscode"
+	KindJetBrains Kind = "jetbrains"
+	KindTerminal  Kind = "terminal"
+	KindGeneric   Kind = "generic"
+)
+
+// editorKinds maps editor executables to the way they are launched
+var editorKinds = map[string]Kind{
+	"code":          KindVSCode,
+	"code-insiders": KindVSCode,
+	"codium":        KindVSCode,
+	"cursor":        KindVSCode,
+	"idea":          KindJetBrains,
+	"idea64":        KindJetBrains,
+	"goland":        KindJetBrains,
+	"pycharm":       KindJetBrains,
+	"webstorm":      KindJetBrains,
+	"phpstorm":      KindJetBrains,
+	"clion":         KindJetBrains,
+	"rider":         KindJetBrains,
+	"rubymine":      KindJetBrains,
+	"rustrover":     KindJetBrains,
+	"studio":        KindJetBrains,
+	"nvim":          KindTerminal,
+	"vim":           KindTerminal,
+	"vi":            KindTerminal,
+	"hx":            KindTerminal,
+	"nano":          KindTerminal,
+}
+
+// Target is the worktree an editor is opened on
+type Target struct {
+	Path          string
+	Title         string
+	WorkspaceFile string
+}
+
+// LaunchCommand is the process that opens an editor; foreground commands take over the terminal until they exit
+type LaunchCommand struct {
+	Name       string
+	Args       []string
+	Dir        string
+	Foreground bool
+}
+
+func (c *LaunchCommand) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// DetectKind returns how an editor command is launched, from the name of its executable
+func DetectKind(editor string) Kind {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return KindGeneric
+	}
+
+	name := strings.TrimSuffix(filepath.Base(fields[0]), ".exe")
+	if kind, ok := editorKinds[name]; ok {
+		return kind
+	}
+	return KindGeneric
+}
+
+// BuildCommand returns the command that opens the target with the editor, which may include extra arguments
+func BuildCommand(editor string, target Target, inTmux bool) (*LaunchCommand, error) {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no editor specified")
+	}
+	name, extraArgs := fields[0], fields[1:]
+
+	switch DetectKind(editor) {
+	case KindVSCode:
+		// A new window per worktree, titled from the workspace file so windows can be told apart
+		open := target.Path
+		if target.WorkspaceFile != "" {
+			open = target.WorkspaceFile
+		}
+		return &LaunchCommand{
+			Name: name,
+			Args: append(extraArgs, "--new-window", open),
+			Dir:  target.Path,
+		}, nil
+
+	case KindJetBrains:
+		// The launcher focuses the window of an already open project instead of opening another one
+		return &LaunchCommand{
+			Name: name,
+			Args: append(extraArgs, target.Path),
+			Dir:  target.Path,
+		}, nil
+
+	case KindTerminal:
+		if inTmux {
+			args := []string{"split-window", "-h", "-c", target.Path}
+			return &LaunchCommand{
+				Name: "tmux",
+				Args: append(args, append(fields, ".")...),
+				Dir:  target.Path,
+			}, nil
+		}
+		return &LaunchCommand{
+			Name:       name,
+			Args:       append(extraArgs, "."),
+			Dir:        target.Path,
+			Foreground: true,
+		}, nil
+	}
+
+	return &LaunchCommand{
+		Name:       name,
+		Args:       append(extraArgs, target.Path),
+		Dir:        target.Path,
+		Foreground: true,
+	}, nil
+}
+
+// Launch runs a launch command, waiting for foreground editors and leaving the others running in the background
+func Launch(launch *LaunchCommand) error {
+	cmd := exec.Command(launch.Name, launch.Args...)
+	cmd.Dir = launch.Dir
+
+	if launch.Foreground {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// InTmux reports whether wt runs inside a tmux session
+func InTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
@@ -0,0 +1,79 @@
+package editors
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetectKind(t *testing.T) {
+	tests := []struct {
+		editor   string
+		expected Kind
+	}{
+		{"code", KindVSCode},
+		{"/usr/local/bin/cursor --reuse", KindVSCode},
+		{"idea", KindJetBrains},
+		{"goland", KindJetBrains},
+		{"nvim", KindTerminal},
+		{"vim -u NONE", KindTerminal},
+		{"subl", KindGeneric},
+		{"", KindGeneric},
+	}
+
+	for _, tt := range tests {
+		if got := DetectKind(tt.editor); got != tt.expected {
+			t.Errorf("DetectKind(%q) = %s, expected %s", tt.editor, got, tt.expected)
+		}
+	}
+}
+
+func TestBuildCommand(t *testing.T) {
+	target := Target{Path: "/wt/app/feature", Title: "app:feature", WorkspaceFile: "/ws/app/feature.code-workspace"}
+
+	tests := []struct {
+		name       string
+		editor     string
+		inTmux     bool
+		command    string
+		args       []string
+		foreground bool
+	}{
+		{"vscode opens the workspace in a new window", "code", false, "code", []string{"--new-window", "/ws/app/feature.code-workspace"}, false},
+		{"jetbrains opens the project path", "idea", false, "idea", []string{"/wt/app/feature"}, false},
+		{"nvim runs in the foreground", "nvim", false, "nvim", []string{"."}, true},
+		{"nvim splits tmux", "nvim -p", true, "tmux", []string{"split-window", "-h", "-c", "/wt/app/feature", "nvim", "-p", "."}, false},
+		{"generic editors get the path", "subl -n", false, "subl", []string{"-n", "/wt/app/feature"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launch, err := BuildCommand(tt.editor, target, tt.inTmux)
+			if err != nil {
+				t.Fatalf("BuildCommand failed: %v", err)
+			}
+			if launch.Name != tt.command || !slices.Equal(launch.Args, tt.args) || launch.Foreground != tt.foreground {
+				t.Errorf("Got %s %v (foreground %v), expected %s %v (foreground %v)",
+					launch.Name, launch.Args, launch.Foreground, tt.command, tt.args, tt.foreground)
+			}
+			if launch.Dir != target.Path {
+				t.Errorf("Expected the command to run in %s, got %s", target.Path, launch.Dir)
+			}
+		})
+	}
+}
+
+func TestBuildCommand_VSCodeWithoutWorkspace(t *testing.T) {
+	launch, err := BuildCommand("code", Target{Path: "/wt/app/feature"}, false)
+	if err != nil {
+		t.Fatalf("BuildCommand failed: %v", err)
+	}
+	if !slices.Equal(launch.Args, []string{"--new-window", "/wt/app/feature"}) {
+		t.Errorf("Expected the worktree folder to be opened, got %v", launch.Args)
+	}
+}
+
+func TestBuildCommand_NoEditor(t *testing.T) {
+	if _, err := BuildCommand("  ", Target{Path: "/wt"}, false); err == nil {
+		t.Error("Expected an error without an editor")
+	}
+}
@@ -0,0 +1,48 @@
+package editors
+
+import (
+	"os"
+	"path/filepath"
+
+	"worktree-manager/internal/fileops"
+)
+
+// codeWorkspace is the subset of the VS Code workspace file format written for worktrees
+type codeWorkspace struct {
+	Folders  []codeWorkspaceFolder `json:"folders"`
+	Settings map[string]string     `json:"settings"`
+}
+
+type codeWorkspaceFolder struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+}
+
+// WriteCodeWorkspace writes a VS Code workspace for a worktree whose window title starts with title
+func WriteCodeWorkspace(workspaceFile, worktreePath, title string) error {
+	if err := fileops.EnsureDir(filepath.Dir(workspaceFile)); err != nil {
+		return err
+	}
+
+	workspace := codeWorkspace{
+		Folders: []codeWorkspaceFolder{{Path: worktreePath, Name: title}},
+		Settings: map[string]string{
+			"window.title": title + "${separator}${activeEditorShort}",
+		},
+	}
+	return fileops.WriteJSONFile(workspaceFile, workspace)
+}
+
+// WriteJetBrainsProjectName names the project of a worktree already opened in a JetBrains IDE, without replacing a name the repository sets
+func WriteJetBrainsProjectName(worktreePath, title string) error {
+	ideaDir := filepath.Join(worktreePath, ".idea")
+	if _, err := os.Stat(ideaDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	nameFile := filepath.Join(ideaDir, ".name")
+	if fileops.FileExists(nameFile) {
+		return nil
+	}
+	return os.WriteFile(nameFile, []byte(title), 0644)
+}
@@ -0,0 +1,57 @@
+package editors
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"worktree-manager/internal/fileops"
+)
+
+func TestWriteCodeWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	workspaceFile := filepath.Join(dir, "workspaces", "app", "feature", "x.code-workspace")
+
+	if err := WriteCodeWorkspace(workspaceFile, "/wt/app/feature/x", "app:feature/x"); err != nil {
+		t.Fatalf("WriteCodeWorkspace failed: %v", err)
+	}
+
+	var workspace codeWorkspace
+	if err := fileops.ReadJSONFile(workspaceFile, &workspace); err != nil {
+		t.Fatalf("Failed to read workspace: %v", err)
+	}
+	if len(workspace.Folders) != 1 || workspace.Folders[0].Path != "/wt/app/feature/x" || workspace.Folders[0].Name != "app:feature/x" {
+		t.Errorf("Unexpected folders: %+v", workspace.Folders)
+	}
+	if workspace.Settings["window.title"] != "app:feature/x${separator}${activeEditorShort}" {
+		t.Errorf("Unexpected window title: %q", workspace.Settings["window.title"])
+	}
+}
+
+func TestWriteJetBrainsProjectName(t *testing.T) {
+	worktree := t.TempDir()
+
+	// Without an .idea directory the worktree has not been opened as a project yet
+	if err := WriteJetBrainsProjectName(worktree, "app:feature"); err != nil {
+		t.Fatalf("WriteJetBrainsProjectName failed: %v", err)
+	}
+	if fileops.FileExists(filepath.Join(worktree, ".idea")) {
+		t.Error("Expected no .idea directory to be created")
+	}
+
+	os.Mkdir(filepath.Join(worktree, ".idea"), 0755)
+	if err := WriteJetBrainsProjectName(worktree, "app:feature"); err != nil {
+		t.Fatalf("WriteJetBrainsProjectName failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktree, ".idea", ".name")); string(data) != "app:feature" {
+		t.Errorf("Expected the project name to be written, got %q", data)
+	}
+
+	// An existing name is left alone
+	if err := WriteJetBrainsProjectName(worktree, "other"); err != nil {
+		t.Fatalf("WriteJetBrainsProjectName failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktree, ".idea", ".name")); string(data) != "app:feature" {
+		t.Errorf("Expected the existing name to be kept, got %q", data)
+	}
+}
@@ -1,15 +1,21 @@
 package executors
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os/exec"
+	"strings"
+	"time"
 
 	"worktree-manager/internal/output"
 )
 
 // CommandExecutor defines the interface for executing system commands
 type CommandExecutor interface {
-	Execute(ctx *CommandExecutionContext) error
+	Execute(ctx context.Context, execCtx *CommandExecutionContext) (*CommandResult, error)
 }
 
 // CommandExecutionContext contains all parameters needed for command execution
@@ -20,8 +26,41 @@ type CommandExecutionContext struct {
 	Env         []string
 	ProgressMsg string
 	ShowOutput  bool
+	// Stdout and Stderr, when set, receive the command's output as it is printed, on top of the CommandResult
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
+// CommandLine returns the command and its arguments as one string, for messages and matching
+func (c *CommandExecutionContext) CommandLine() string {
+	return strings.Join(append([]string{c.Command}, c.Args...), " ")
+}
+
+// CommandResult holds what a command printed, how it exited and how long it took
+type CommandResult struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+	Duration time.Duration
+}
+
+// TrimmedStdout returns stdout without surrounding whitespace
+func (r *CommandResult) TrimmedStdout() string {
+	return strings.TrimSpace(string(r.Stdout))
+}
+
+// ExitError reports a command that ran but exited with a non-zero code
+type ExitError struct {
+	ExitCode int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.ExitCode)
+}
+
+// waitDelay bounds how long a cancelled command may keep its output open
+const waitDelay = time.Second
+
 // SystemCommandExecutor implements CommandExecutor for system commands
 type SystemCommandExecutor struct{}
 
@@ -29,32 +68,78 @@ func NewSystemCommandExecutor() *SystemCommandExecutor {
 	return &SystemCommandExecutor{}
 }
 
-func (e *SystemCommandExecutor) Execute(ctx *CommandExecutionContext) error {
-	if ctx.Command == "" {
-		return fmt.Errorf("command cannot be empty")
+func (e *SystemCommandExecutor) Execute(ctx context.Context, execCtx *CommandExecutionContext) (*CommandResult, error) {
+	if execCtx.Command == "" {
+		return nil, fmt.Errorf("command cannot be empty")
 	}
 
-	cmd := exec.Command(ctx.Command, ctx.Args...)
+	cmd := exec.CommandContext(ctx, execCtx.Command, execCtx.Args...)
+	// Children that outlive a cancelled command would otherwise keep the output pipes, and Run, open
+	cmd.WaitDelay = waitDelay
 
-	if ctx.WorkingDir != "" {
-		cmd.Dir = ctx.WorkingDir
+	if execCtx.WorkingDir != "" {
+		cmd.Dir = execCtx.WorkingDir
 	}
 
-	if len(ctx.Env) > 0 {
-		cmd.Env = ctx.Env
+	if len(execCtx.Env) > 0 {
+		cmd.Env = execCtx.Env
 	}
 
-	if ctx.ProgressMsg != "" {
-		output.Progress(ctx.ProgressMsg)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = teeOutput(&stdout, execCtx.Stdout)
+	cmd.Stderr = teeOutput(&stderr, execCtx.Stderr)
+
+	if execCtx.ProgressMsg != "" {
+		output.Progress(execCtx.ProgressMsg)
 	}
 
-	if ctx.ShowOutput {
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("command failed: %v\nOutput: %s", err, string(output))
-		}
-		return nil
+	start := time.Now()
+	err := cmd.Run()
+	result := &CommandResult{
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+		Duration: time.Since(start),
 	}
 
-	return cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		result.ExitCode = -1
+		return result, fmt.Errorf("%s: %w", execCtx.CommandLine(), ctx.Err())
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		err = &ExitError{ExitCode: result.ExitCode}
+	default:
+		result.ExitCode = -1
+		return result, err
+	}
+
+	return result, CommandError(execCtx, result, err)
+}
+
+func teeOutput(buf *bytes.Buffer, live io.Writer) io.Writer {
+	if live == nil {
+		return buf
+	}
+	return io.MultiWriter(buf, live)
+}
+
+// CommandError turns a non-zero exit into the error callers see, adding the command's output when ShowOutput is set.
+// Output already shown live is not repeated
+func CommandError(execCtx *CommandExecutionContext, result *CommandResult, err error) error {
+	if err == nil || !execCtx.ShowOutput {
+		return err
+	}
+	var shown string
+	if execCtx.Stderr == nil {
+		shown += string(result.Stderr)
+	}
+	if execCtx.Stdout == nil {
+		shown += string(result.Stdout)
+	}
+	if shown == "" {
+		return fmt.Errorf("command failed: %w", err)
+	}
+	return fmt.Errorf("command failed: %w\nOutput: %s", err, shown)
 }
@@ -1,7 +1,13 @@
 package executors
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSystemCommandExecutor_Execute_EmptyCommand(t *testing.T) {
@@ -10,7 +16,7 @@ func TestSystemCommandExecutor_Execute_EmptyCommand(t *testing.T) {
 		Command: "",
 	}
 
-	err := executor.Execute(ctx)
+	_, err := executor.Execute(context.Background(), ctx)
 	if err == nil {
 		t.Error("Expected error for empty command, got nil")
 	}
@@ -21,3 +27,91 @@ func TestSystemCommandExecutor_Execute_EmptyCommand(t *testing.T) {
 		t.Errorf("Expected error message '%s', got '%s'", expectedMsg, err.Error())
 	}
 }
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+}
+
+func TestSystemCommandExecutor_Execute_CapturesResult(t *testing.T) {
+	requireShell(t)
+	executor := NewSystemCommandExecutor()
+
+	result, err := executor.Execute(context.Background(), &CommandExecutionContext{
+		Command: "sh",
+		Args:    []string{"-c", "echo out; echo err >&2"},
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.TrimmedStdout() != "out" || strings.TrimSpace(string(result.Stderr)) != "err" {
+		t.Errorf("Expected stdout 'out' and stderr 'err', got %q and %q", result.Stdout, result.Stderr)
+	}
+	if result.ExitCode != 0 || result.Duration <= 0 {
+		t.Errorf("Expected exit code 0 and a duration, got %d and %v", result.ExitCode, result.Duration)
+	}
+}
+
+func TestSystemCommandExecutor_Execute_LiveOutput(t *testing.T) {
+	requireShell(t)
+	executor := NewSystemCommandExecutor()
+
+	var stdout, stderr bytes.Buffer
+	result, err := executor.Execute(context.Background(), &CommandExecutionContext{
+		Command:    "sh",
+		Args:       []string{"-c", "echo out; echo err >&2; exit 3"},
+		ShowOutput: true,
+		Stdout:     &stdout,
+		Stderr:     &stderr,
+	})
+	if stdout.String() != "out\n" || stderr.String() != "err\n" {
+		t.Errorf("Expected the live writers to get 'out' and 'err', got %q and %q", stdout.String(), stderr.String())
+	}
+	if result.TrimmedStdout() != "out" || strings.TrimSpace(string(result.Stderr)) != "err" {
+		t.Errorf("Expected the result to hold the output too, got %q and %q", result.Stdout, result.Stderr)
+	}
+	// Output already shown is not repeated in the error
+	if err == nil || strings.Contains(err.Error(), "Output:") {
+		t.Errorf("Expected an error without the output, got %v", err)
+	}
+}
+
+func TestSystemCommandExecutor_Execute_ExitCode(t *testing.T) {
+	requireShell(t)
+	executor := NewSystemCommandExecutor()
+
+	result, err := executor.Execute(context.Background(), &CommandExecutionContext{
+		Command:    "sh",
+		Args:       []string{"-c", "echo broken >&2; exit 3"},
+		ShowOutput: true,
+	})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 3 {
+		t.Fatalf("Expected an ExitError with code 3, got %v", err)
+	}
+	if result.ExitCode != 3 {
+		t.Errorf("Expected result exit code 3, got %d", result.ExitCode)
+	}
+	if !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected the output in the error, got %q", err.Error())
+	}
+}
+
+func TestSystemCommandExecutor_Execute_Timeout(t *testing.T) {
+	requireShell(t)
+	executor := NewSystemCommandExecutor()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := executor.Execute(ctx, &CommandExecutionContext{Command: "sh", Args: []string{"-c", "sleep 5"}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a deadline error, got %v", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("Expected the command to be killed at the deadline")
+	}
+}
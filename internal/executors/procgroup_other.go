@@ -0,0 +1,39 @@
+//go:build !unix
+
+package executors
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// signalProcessGroup can only kill the script itself where there are no process groups
+func signalProcessGroup(process *os.Process, sig syscall.Signal) error {
+	return process.Kill()
+}
+
+func reraise(sig os.Signal) {
+	os.Exit(1)
+}
+
+func Detach(cmd *exec.Cmd) {}
+
+func ProcessAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
+
+func TerminateProcess(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Kill()
+}
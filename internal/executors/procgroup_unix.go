@@ -0,0 +1,44 @@
+//go:build unix
+
+package executors
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// setProcessGroup starts the command in a process group of its own, so everything it starts can be signalled together
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func signalProcessGroup(process *os.Process, sig syscall.Signal) error {
+	return syscall.Kill(-process.Pid, sig)
+}
+
+// reraise delivers a signal wt caught to itself again, so it ends the way it would have without being caught
+func reraise(sig os.Signal) {
+	signal.Reset(sig)
+	if s, ok := sig.(syscall.Signal); ok {
+		_ = syscall.Kill(os.Getpid(), s)
+	}
+}
+
+// Detach starts the command in a session of its own, so it keeps running after wt exits and the terminal closes
+func Detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// ProcessAlive reports whether a process with the given PID is running
+func ProcessAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// TerminateProcess asks a process to stop with SIGTERM
+func TerminateProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
@@ -0,0 +1,166 @@
+package executors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Interaction is one command together with the result it produced
+type Interaction struct {
+	Command    string   `json:"command"`
+	Args       []string `json:"args"`
+	WorkingDir string   `json:"working-dir,omitempty"`
+	Stdout     string   `json:"stdout,omitempty"`
+	Stderr     string   `json:"stderr,omitempty"`
+	ExitCode   int      `json:"exit-code,omitempty"`
+}
+
+// RecordingExecutor runs commands with another executor and records each one with its result
+type RecordingExecutor struct {
+	next CommandExecutor
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+func NewRecordingExecutor(next CommandExecutor) *RecordingExecutor {
+	return &RecordingExecutor{next: next}
+}
+
+func (r *RecordingExecutor) Execute(ctx context.Context, execCtx *CommandExecutionContext) (*CommandResult, error) {
+	result, err := r.next.Execute(ctx, execCtx)
+
+	interaction := Interaction{Command: execCtx.Command, Args: execCtx.Args, WorkingDir: execCtx.WorkingDir}
+	if result != nil {
+		interaction.Stdout = string(result.Stdout)
+		interaction.Stderr = string(result.Stderr)
+		interaction.ExitCode = result.ExitCode
+	} else {
+		interaction.ExitCode = -1
+		interaction.Stderr = err.Error()
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	return result, err
+}
+
+// Interactions returns the commands run so far, in order
+func (r *RecordingExecutor) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.interactions)
+}
+
+// Save writes the recorded interactions to a JSON file that LoadReplayExecutor reads back
+func (r *RecordingExecutor) Save(path string) error {
+	data, err := json.MarshalIndent(r.Interactions(), "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReplayExecutor answers commands from recorded interactions without running anything.
+// A command matches an interaction with the same command and arguments, and the same working directory when one
+// was recorded; matches are used in order, and the last one is repeated once they run out
+type ReplayExecutor struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	calls        []Interaction
+}
+
+func NewReplayExecutor(interactions ...Interaction) *ReplayExecutor {
+	return &ReplayExecutor{interactions: interactions, used: make([]bool, len(interactions))}
+}
+
+// LoadReplayExecutor replays the interactions saved by a RecordingExecutor
+func LoadReplayExecutor(path string) (*ReplayExecutor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return NewReplayExecutor(interactions...), nil
+}
+
+func (r *ReplayExecutor) Execute(ctx context.Context, execCtx *CommandExecutionContext) (*CommandResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, Interaction{Command: execCtx.Command, Args: slices.Clone(execCtx.Args), WorkingDir: execCtx.WorkingDir})
+
+	match := -1
+	for i, interaction := range r.interactions {
+		if !interaction.matches(execCtx) {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("no recorded interaction for '%s' in %s", execCtx.CommandLine(), execCtx.WorkingDir)
+	}
+	r.used[match] = true
+
+	interaction := r.interactions[match]
+	if execCtx.Stdout != nil {
+		io.WriteString(execCtx.Stdout, interaction.Stdout)
+	}
+	if execCtx.Stderr != nil {
+		io.WriteString(execCtx.Stderr, interaction.Stderr)
+	}
+	result := &CommandResult{
+		Stdout:   []byte(interaction.Stdout),
+		Stderr:   []byte(interaction.Stderr),
+		ExitCode: interaction.ExitCode,
+	}
+	if interaction.ExitCode != 0 {
+		return result, CommandError(execCtx, result, &ExitError{ExitCode: interaction.ExitCode})
+	}
+	return result, nil
+}
+
+func (i Interaction) matches(execCtx *CommandExecutionContext) bool {
+	return i.Command == execCtx.Command &&
+		slices.Equal(i.Args, execCtx.Args) &&
+		(i.WorkingDir == "" || i.WorkingDir == execCtx.WorkingDir)
+}
+
+// Calls returns the commands the executor was asked to run, in order
+func (r *ReplayExecutor) Calls() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// Unused returns the interactions no command has matched yet
+func (r *ReplayExecutor) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
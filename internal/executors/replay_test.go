@@ -0,0 +1,66 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplayExecutor(t *testing.T) {
+	replay := NewReplayExecutor(
+		Interaction{Command: "git", Args: []string{"rev-parse", "HEAD"}, Stdout: "first\n"},
+		Interaction{Command: "git", Args: []string{"rev-parse", "HEAD"}, Stdout: "second\n"},
+		Interaction{Command: "git", Args: []string{"fetch"}, WorkingDir: "/repo", ExitCode: 128, Stderr: "offline"},
+	)
+	ctx := context.Background()
+	revParse := &CommandExecutionContext{Command: "git", Args: []string{"rev-parse", "HEAD"}, WorkingDir: "/anywhere"}
+
+	for _, want := range []string{"first", "second", "second"} {
+		result, err := replay.Execute(ctx, revParse)
+		if err != nil || result.TrimmedStdout() != want {
+			t.Errorf("Expected %q, got %v, %v", want, result, err)
+		}
+	}
+
+	_, err := replay.Execute(ctx, &CommandExecutionContext{Command: "git", Args: []string{"fetch"}, WorkingDir: "/repo", ShowOutput: true})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 128 {
+		t.Errorf("Expected an ExitError with code 128, got %v", err)
+	}
+
+	if _, err := replay.Execute(ctx, &CommandExecutionContext{Command: "git", Args: []string{"fetch"}, WorkingDir: "/other"}); err == nil {
+		t.Error("Expected an error for a command run in another directory")
+	}
+
+	if calls := replay.Calls(); len(calls) != 5 {
+		t.Errorf("Expected 5 recorded calls, got %d", len(calls))
+	}
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Errorf("Expected every interaction to be used, got %+v", unused)
+	}
+}
+
+func TestRecordingExecutor_SaveAndReplay(t *testing.T) {
+	requireShell(t)
+	recorder := NewRecordingExecutor(NewSystemCommandExecutor())
+	execCtx := &CommandExecutionContext{Command: "sh", Args: []string{"-c", "echo recorded; exit 2"}}
+
+	if _, err := recorder.Execute(context.Background(), execCtx); err == nil {
+		t.Fatal("Expected the recorded command to fail")
+	}
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	replay, err := LoadReplayExecutor(path)
+	if err != nil {
+		t.Fatalf("LoadReplayExecutor failed: %v", err)
+	}
+	result, err := replay.Execute(context.Background(), execCtx)
+	if err == nil || result.TrimmedStdout() != "recorded" || result.ExitCode != 2 {
+		t.Errorf("Expected the recorded output and exit code, got %+v, %v", result, err)
+	}
+}
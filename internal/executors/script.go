@@ -1,10 +1,18 @@
 package executors
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"worktree-manager/internal/consts"
 	"worktree-manager/internal/output"
@@ -18,13 +26,35 @@ type ScriptExecutor interface {
 
 // ScriptExecutionContext contains all parameters needed for script execution
 type ScriptExecutionContext struct {
-	ScriptPath   string
+	ScriptPath string
+	// Command is a command line run with bash instead of a script, for hook commands declared in the config
+	Command      string
 	Repo         *state.Repo
 	WorktreePath string
 	WorkingDir   string
 	ProgressMsg  string
+	ExtraEnv     []string
+	Hook         string
+	// Timeout stops the script and everything it started once exceeded; zero means no limit
+	Timeout time.Duration
+	// LogPath receives a copy of the script's output when set
+	LogPath string
 }
 
+// Results recorded at the end of a script log
+const (
+	ScriptResultOK          = "ok"
+	ScriptResultFailed      = "failed"
+	ScriptResultTimeout     = "timeout"
+	ScriptResultInterrupted = "interrupted"
+)
+
+// scriptLogPrefix starts the lines wt adds to a script log, around the script's own output
+const scriptLogPrefix = "# wt: "
+
+// killGrace is how long a timed out script's processes get to exit after SIGTERM before they are killed
+const killGrace = 5 * time.Second
+
 // BashScriptExecutor implements ScriptExecutor for bash scripts
 type BashScriptExecutor struct{}
 
@@ -33,23 +63,189 @@ func NewBashScriptExecutor() *BashScriptExecutor {
 }
 
 func (e *BashScriptExecutor) Execute(ctx *ScriptExecutionContext) error {
-	if ctx.ScriptPath == "" {
+	var args []string
+	var target string
+	switch {
+	case ctx.Command != "":
+		args = []string{"bash", "-c", ctx.Command}
+		target = ctx.Command
+	case ctx.ScriptPath != "":
+		resolvedPath, err := resolveScriptPath(ctx.ScriptPath, ctx.Repo.Dir)
+		if err != nil {
+			return err
+		}
+		if args, err = scriptArgs(resolvedPath); err != nil {
+			return err
+		}
+		target = resolvedPath
+	default:
 		return nil
 	}
 
-	resolvedPath, err := resolveScriptPath(ctx.ScriptPath, ctx.Repo.Dir)
+	env := append(BuildScriptEnvironment(ctx.Repo, ctx.WorktreePath), ctx.ExtraEnv...)
+
+	// Without a timeout a script run from a terminal keeps it, so it can prompt and draw progress bars.
+	// Otherwise it runs in its own process group, with its output copied to the log
+	attached := ctx.Timeout == 0 && output.Interactive()
+
+	log, err := createScriptLog(ctx.LogPath)
 	if err != nil {
+		output.Warning("Failed to create the script log: %v", err)
+	}
+	if log != nil {
+		defer log.Close()
+		kind := "script"
+		if ctx.Command != "" {
+			kind = "command"
+		}
+		fmt.Fprintf(log, "%shook=%s %s=%s started=%s\n", scriptLogPrefix, ctx.Hook, kind, target, time.Now().Format(time.RFC3339))
+		if attached {
+			fmt.Fprintf(log, "%soutput went to the terminal\n", scriptLogPrefix)
+		}
+	}
+
+	if ctx.ProgressMsg != "" {
+		output.Progress(ctx.ProgressMsg, target)
+	}
+
+	start := time.Now()
+	var result string
+	if attached {
+		cmd := createScriptCommand(context.Background(), args, env, ctx.WorkingDir)
+		cmd.Stdout = output.Stdout()
+		cmd.Stderr = output.Stderr()
+		cmd.Stdin = os.Stdin
+		err = cmd.Run()
+		result = scriptResult(err)
+	} else {
+		result, err = runDetached(ctx, args, env, log)
+	}
+
+	if log != nil {
+		fmt.Fprintf(log, "\n%sresult=%s duration=%s", scriptLogPrefix, result, time.Since(start).Round(time.Millisecond))
+		if err != nil {
+			fmt.Fprintf(log, " error=%q", err.Error())
+		}
+		fmt.Fprintln(log)
+	}
+
+	var interrupted interruptedError
+	if errors.As(err, &interrupted) {
+		// wt stops as it would have had the script been in its process group
+		log.Close()
+		reraise(interrupted.signal)
+	}
+
+	if err != nil && log != nil {
+		return fmt.Errorf("%w (output saved to %s)", err, ctx.LogPath)
+	}
+	return err
+}
+
+// interruptedError reports a script stopped because wt itself was interrupted
+type interruptedError struct {
+	signal os.Signal
+}
+
+func (e interruptedError) Error() string {
+	return "interrupted"
+}
+
+// runDetached runs a script in its own process group, which is stopped as a whole on timeout or interrupt
+func runDetached(ctx *ScriptExecutionContext, args []string, env []string, log *os.File) (string, error) {
+	var runCtx context.Context
+	var cancel context.CancelFunc
+	if ctx.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(context.Background(), ctx.Timeout)
+	} else {
+		runCtx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+
+	cmd := createScriptCommand(runCtx, args, env, ctx.WorkingDir)
+	cmd.Stdout = output.Stdout()
+	cmd.Stderr = output.Stderr()
+	if log != nil {
+		cmd.Stdout = io.MultiWriter(cmd.Stdout, log)
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, log)
+	}
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		err := signalProcessGroup(cmd.Process, syscall.SIGTERM)
+		time.AfterFunc(killGrace, func() {
+			_ = signalProcessGroup(cmd.Process, syscall.SIGKILL)
+		})
 		return err
 	}
+	// Background processes the script leaves running must not hold wt until they exit
+	cmd.WaitDelay = waitDelay
 
-	env := buildScriptEnvironment(ctx.Repo, ctx.WorktreePath)
-	cmd := createScriptCommand(resolvedPath, env, ctx.WorkingDir)
+	// The script's process group does not receive the terminal's Ctrl-C, so it is passed on
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupts)
+	done := make(chan struct{})
+	stopped := make(chan os.Signal, 1)
+	go func() {
+		select {
+		case sig := <-interrupts:
+			cancel()
+			stopped <- sig
+		case <-done:
+			stopped <- nil
+		}
+	}()
 
-	if ctx.ProgressMsg != "" {
-		output.Progress(ctx.ProgressMsg, resolvedPath)
+	err := cmd.Run()
+	close(done)
+	interrupted := <-stopped
+
+	switch {
+	case interrupted != nil:
+		return ScriptResultInterrupted, interruptedError{signal: interrupted}
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		return ScriptResultTimeout, fmt.Errorf("timed out after %s", ctx.Timeout)
+	case errors.Is(err, exec.ErrWaitDelay):
+		// The script itself succeeded; only its background processes kept the output open
+		err = nil
 	}
+	return scriptResult(err), err
+}
 
-	return cmd.Run()
+func scriptResult(err error) string {
+	if err != nil {
+		return ScriptResultFailed
+	}
+	return ScriptResultOK
+}
+
+// ReadScriptResult returns the result recorded at the end of a script log, or "" while the script is still running
+func ReadScriptResult(logPath string) string {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	result := ""
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if fields, ok := strings.CutPrefix(scanner.Text(), scriptLogPrefix+"result="); ok {
+			result, _, _ = strings.Cut(fields, " ")
+		}
+	}
+	return result
+}
+
+func createScriptLog(logPath string) (*os.File, error) {
+	if logPath == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 }
 
 func resolveScriptPath(scriptPath, repoDir string) (string, error) {
@@ -64,21 +260,67 @@ func resolveScriptPath(scriptPath, repoDir string) (string, error) {
 	return scriptPath, nil
 }
 
-func buildScriptEnvironment(repo *state.Repo, worktreePath string) []string {
-	env := os.Environ()
+// BuildScriptEnvironment returns the current environment extended with the WT_* worktree variables
+func BuildScriptEnvironment(repo *state.Repo, worktreePath string) []string {
+	return append(os.Environ(), ScriptVariables(repo, worktreePath)...)
+}
+
+// ScriptVariables returns the WT_* variables locating a worktree and its repository
+func ScriptVariables(repo *state.Repo, worktreePath string) []string {
 	envVars := consts.GetEnvironmentVariables()
-	env = append(env, fmt.Sprintf("%s=%s", envVars.RepoAlias.Name, repo.Alias))
-	env = append(env, fmt.Sprintf("%s=%s", envVars.RepoDir.Name, repo.Dir))
-	env = append(env, fmt.Sprintf("%s=%s", envVars.WorktreePath.Name, worktreePath))
-	return env
+	return []string{
+		fmt.Sprintf("%s=%s", envVars.RepoAlias.Name, repo.Alias),
+		fmt.Sprintf("%s=%s", envVars.RepoDir.Name, repo.Dir),
+		fmt.Sprintf("%s=%s", envVars.WorktreePath.Name, worktreePath),
+	}
+}
+
+// scriptArgs returns the command line that runs a script: the script itself when it is executable and starts with a
+// shebang, the interpreter its shebang names otherwise, and bash for scripts without one
+func scriptArgs(scriptPath string) ([]string, error) {
+	file, err := os.Open(scriptPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	firstLine, _ := bufio.NewReader(file).ReadString('\n')
+	shebang, ok := strings.CutPrefix(strings.TrimSpace(firstLine), "#!")
+	shebang = strings.TrimSpace(shebang)
+	if !ok || shebang == "" {
+		return []string{"bash", scriptPath}, nil
+	}
+
+	if info, err := file.Stat(); err == nil && info.Mode()&0111 != 0 {
+		return []string{scriptPath}, nil
+	}
+
+	interpreter, argument, _ := strings.Cut(shebang, " ")
+	argument = strings.TrimSpace(argument)
+	// Where the interpreter's path does not exist, such as /bin/bash on Windows, it is looked up by name instead
+	if _, err := os.Stat(interpreter); err != nil {
+		interpreter = filepath.Base(interpreter)
+		if fields := strings.Fields(argument); interpreter == "env" && len(fields) > 0 {
+			if fields[0] == "-S" {
+				fields = fields[1:]
+			}
+			if len(fields) > 0 {
+				interpreter, argument = fields[0], strings.Join(fields[1:], " ")
+			}
+		}
+	}
+
+	// Like the kernel, pass everything after the interpreter as one argument
+	args := []string{interpreter}
+	if argument != "" {
+		args = append(args, argument)
+	}
+	return append(args, scriptPath), nil
 }
 
-func createScriptCommand(scriptPath string, env []string, workingDir string) *exec.Cmd {
-	cmd := exec.Command("bash", scriptPath)
+func createScriptCommand(ctx context.Context, args []string, env []string, workingDir string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
 	cmd.Env = env
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
 	if workingDir != "" {
 		cmd.Dir = workingDir
 	}
@@ -1,8 +1,19 @@
 package executors
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
+
 	"worktree-manager/internal/consts"
+	"worktree-manager/internal/output"
 	"worktree-manager/internal/state"
 )
 
@@ -12,7 +23,7 @@ func TestBuildScriptEnvironment(t *testing.T) {
 		Dir:   "/repo/dir",
 	}
 
-	env := buildScriptEnvironment(repo, "/worktree/path")
+	env := BuildScriptEnvironment(repo, "/worktree/path")
 
 	// Check that our custom environment variables are present
 	envVars := consts.GetEnvironmentVariables()
@@ -34,3 +45,192 @@ func TestBuildScriptEnvironment(t *testing.T) {
 		}
 	}
 }
+
+func writeScript(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBashScriptExecutor_LogsOutput(t *testing.T) {
+	var stdout bytes.Buffer
+	defer output.Redirect(&stdout, &stdout)()
+
+	logPath := filepath.Join(t.TempDir(), "logs", "post-worktree-add.log")
+	err := NewBashScriptExecutor().Execute(&ScriptExecutionContext{
+		ScriptPath: writeScript(t, "echo \"installing $WT_BRANCH\"\necho oops >&2\nexit 3\n"),
+		Repo:       &state.Repo{Alias: "app", Dir: t.TempDir()},
+		ExtraEnv:   []string{"WT_BRANCH=feature"},
+		Hook:       "post-worktree-add",
+		Timeout:    time.Minute,
+		LogPath:    logPath,
+	})
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") || !strings.Contains(err.Error(), logPath) {
+		t.Fatalf("Expected the exit status and log path in the error, got %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "installing feature") {
+		t.Errorf("Expected the output on the terminal too, got %q", stdout.String())
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"hook=post-worktree-add", "installing feature", "oops"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %q in the log, got:\n%s", expected, data)
+		}
+	}
+	if result := ReadScriptResult(logPath); result != ScriptResultFailed {
+		t.Errorf("Expected result %q, got %q", ScriptResultFailed, result)
+	}
+}
+
+func TestBashScriptExecutor_TimeoutStopsProcessGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are not used on Windows")
+	}
+	defer output.Redirect(io.Discard, io.Discard)()
+
+	// The background job would outlive its parent if only bash were killed
+	marker := filepath.Join(t.TempDir(), "survived")
+	logPath := filepath.Join(t.TempDir(), "hook.log")
+	script := writeScript(t, fmt.Sprintf("(sleep 1; touch %q) &\nsleep 30\n", marker))
+
+	start := time.Now()
+	err := NewBashScriptExecutor().Execute(&ScriptExecutionContext{
+		ScriptPath: script,
+		Repo:       &state.Repo{Alias: "app", Dir: t.TempDir()},
+		Timeout:    200 * time.Millisecond,
+		LogPath:    logPath,
+	})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 200ms") {
+		t.Fatalf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the hook to be stopped promptly, took %s", elapsed)
+	}
+	if result := ReadScriptResult(logPath); result != ScriptResultTimeout {
+		t.Errorf("Expected result %q, got %q", ScriptResultTimeout, result)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("Expected the hook's background job to be killed with it")
+	}
+}
+
+func TestBashScriptExecutor_BackgroundJobDoesNotBlock(t *testing.T) {
+	defer output.Redirect(io.Discard, io.Discard)()
+
+	start := time.Now()
+	err := NewBashScriptExecutor().Execute(&ScriptExecutionContext{
+		ScriptPath: writeScript(t, "sleep 5 &\necho started\n"),
+		Repo:       &state.Repo{Alias: "app", Dir: t.TempDir()},
+		Timeout:    time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Errorf("Expected wt not to wait for the background job, took %s", elapsed)
+	}
+}
+
+func TestScriptArgs(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string, mode os.FileMode) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	executable := write("executable.py", "#!/usr/bin/env python3\nprint('hi')\n", 0755)
+	plain := write("plain.py", "#!/usr/bin/env python3\nprint('hi')\n", 0644)
+	noShebang := write("legacy.sh", "echo hi\n", 0755)
+	missing := write("missing.js", "#!/opt/nowhere/env -S node --no-warnings\n", 0644)
+
+	plainExpected := []string{"/usr/bin/env", "python3", plain}
+	if _, err := os.Stat("/usr/bin/env"); err != nil {
+		plainExpected = []string{"python3", plain}
+	}
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{plain, plainExpected},
+		{noShebang, []string{"bash", noShebang}},
+		{missing, []string{"node", "--no-warnings", missing}},
+	}
+	if runtime.GOOS != "windows" {
+		tests = append(tests, struct {
+			path     string
+			expected []string
+		}{executable, []string{executable}})
+	}
+
+	for _, tt := range tests {
+		args, err := scriptArgs(tt.path)
+		if err != nil {
+			t.Fatalf("scriptArgs(%s) failed: %v", filepath.Base(tt.path), err)
+		}
+		if strings.Join(args, "|") != strings.Join(tt.expected, "|") {
+			t.Errorf("scriptArgs(%s) = %q, want %q", filepath.Base(tt.path), args, tt.expected)
+		}
+	}
+}
+
+func TestBashScriptExecutor_RespectsShebang(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not installed")
+	}
+	var stdout bytes.Buffer
+	defer output.Redirect(&stdout, io.Discard)()
+
+	script := filepath.Join(t.TempDir(), "hook.py")
+	os.WriteFile(script, []byte("#!/usr/bin/env python3\nimport os\nprint('python sees ' + os.environ['WT_REPO_ALIAS'])\n"), 0644)
+
+	err := NewBashScriptExecutor().Execute(&ScriptExecutionContext{
+		ScriptPath: script,
+		Repo:       &state.Repo{Alias: "app", Dir: t.TempDir()},
+		Timeout:    time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Expected the python hook to run, got %v", err)
+	}
+	if !strings.Contains(stdout.String(), "python sees app") {
+		t.Errorf("Unexpected output %q", stdout.String())
+	}
+}
+
+func TestBashScriptExecutor_Command(t *testing.T) {
+	var stdout bytes.Buffer
+	defer output.Redirect(&stdout, io.Discard)()
+
+	dir := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "hook.log")
+	err := NewBashScriptExecutor().Execute(&ScriptExecutionContext{
+		Command:    "echo \"$GREETING from $(basename \"$PWD\")\"",
+		Repo:       &state.Repo{Alias: "app", Dir: t.TempDir()},
+		WorkingDir: dir,
+		ExtraEnv:   []string{"GREETING=hello"},
+		Hook:       "post-worktree-add",
+		Timeout:    time.Minute,
+		LogPath:    logPath,
+	})
+	if err != nil {
+		t.Fatalf("Expected the command to succeed, got %v", err)
+	}
+	if expected := "hello from " + filepath.Base(dir); !strings.Contains(stdout.String(), expected) {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+	if data, _ := os.ReadFile(logPath); !strings.Contains(string(data), "command=echo") {
+		t.Errorf("Expected the command in the log header, got:\n%s", data)
+	}
+}
@@ -0,0 +1,116 @@
+package fileops
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// CopyFile copies a regular file byte for byte, preserving its permissions
+func CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s to %s: %w", src, dst, err)
+	}
+
+	return out.Close()
+}
+
+// CloneFile makes a copy-on-write clone of a file where the filesystem supports it, falling back to a regular copy
+func CloneFile(src, dst string) error {
+	if err := reflink(src, dst); err == nil {
+		return nil
+	}
+	return CopyFile(src, dst)
+}
+
+// Reflink makes a copy-on-write clone of a file, failing where the filesystem does not support them
+func Reflink(src, dst string) error {
+	return reflink(src, dst)
+}
+
+// FileID identifies the data of a file on disk, which all of its hard links share
+type FileID struct {
+	Dev uint64
+	Ino uint64
+}
+
+// CopyTree copies a file or directory tree using copyFn for each regular file; symlinks are recreated as-is
+func CopyTree(src, dst string, copyFn func(src, dst string) error) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("failed to read symlink %s: %w", path, err)
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		case d.IsDir():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type().IsRegular():
+			if err := EnsureDir(filepath.Dir(target)); err != nil {
+				return err
+			}
+			return copyFn(path, target)
+		}
+
+		// Sockets, devices and other special files are not copied
+		return nil
+	})
+}
+
+// MoveTree moves a file or directory to dst, creating dst's parent. Moves across filesystems fall back to copying and removing
+func MoveTree(src, dst string) error {
+	if FileExists(dst) {
+		return fmt.Errorf("cannot move %s: %s already exists", src, dst)
+	}
+	if err := EnsureDir(filepath.Dir(dst)); err != nil {
+		return err
+	}
+
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	if err := CopyTree(src, dst, CopyFile); err != nil {
+		os.RemoveAll(dst)
+		return fmt.Errorf("failed to copy %s to %s: %w", src, dst, err)
+	}
+	return os.RemoveAll(src)
+}
@@ -0,0 +1,44 @@
+package fileops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyTree(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dst := filepath.Join(t.TempDir(), "dst")
+
+	os.MkdirAll(filepath.Join(src, "nested"), 0755)
+	os.WriteFile(filepath.Join(src, "nested", "run.sh"), []byte("#!/bin/sh\n"), 0755)
+	os.WriteFile(filepath.Join(src, "data.txt"), []byte("data"), 0644)
+	os.Symlink("data.txt", filepath.Join(src, "link.txt"))
+
+	for name, copyFn := range map[string]func(src, dst string) error{"copy": CopyFile, "clone": CloneFile} {
+		t.Run(name, func(t *testing.T) {
+			target := filepath.Join(dst, name)
+			if err := CopyTree(src, target, copyFn); err != nil {
+				t.Fatalf("CopyTree failed: %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(target, "data.txt"))
+			if err != nil || string(data) != "data" {
+				t.Errorf("Expected copied file content 'data', got %q (err: %v)", data, err)
+			}
+
+			info, err := os.Stat(filepath.Join(target, "nested", "run.sh"))
+			if err != nil {
+				t.Fatalf("Failed to stat nested file: %v", err)
+			}
+			if info.Mode()&0111 == 0 {
+				t.Error("Expected executable permission to be preserved")
+			}
+
+			link, err := os.Readlink(filepath.Join(target, "link.txt"))
+			if err != nil || link != "data.txt" {
+				t.Errorf("Expected symlink to 'data.txt', got %q (err: %v)", link, err)
+			}
+		})
+	}
+}
@@ -64,6 +64,18 @@ func ExpandEnvVars(s string) string {
 	return os.ExpandEnv(s)
 }
 
+// PortablePath rewrites a path under the home directory relative to $HOME, which ExpandEnvVars undoes on another machine
+func PortablePath(path string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(homeDir, path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("$HOME", rel)
+	}
+	return path
+}
+
 // GetRepoScriptDir returns the script directory for a specific repo
 func GetRepoScriptDir(repoAlias string) string {
 	return filepath.Join(consts.GetDirectoryPaths().ScriptsDir, repoAlias)
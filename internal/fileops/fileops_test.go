@@ -68,7 +68,37 @@ func TestExpandEnvVars(t *testing.T) {
 	}
 }
 
+func TestPortablePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{filepath.Join(home, ".worktree-manager", "repos", "app"), filepath.Join("$HOME", ".worktree-manager", "repos", "app")},
+		{"/srv/app", "/srv/app"},
+		{home, home},
+	}
+
+	for _, tt := range tests {
+		result := PortablePath(tt.path)
+		if result != tt.expected {
+			t.Errorf("PortablePath(%s) = %s, expected %s", tt.path, result, tt.expected)
+		}
+		if ExpandEnvVars(result) != tt.path {
+			t.Errorf("Expected %s to expand back to %s", result, tt.path)
+		}
+	}
+}
+
 func TestGetWorktreeManagerDir(t *testing.T) {
+	// An existing ~/.worktree-manager keeps being used
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("WT_HOME", "")
+	os.Mkdir(filepath.Join(home, ".worktree-manager"), 0755)
+
 	dir := consts.GetDirectoryPaths().WorktreeManagerDir
 	if !strings.HasSuffix(dir, ".worktree-manager") {
 		t.Errorf("Expected directory to end with .worktree-manager, got %s", dir)
@@ -0,0 +1,14 @@
+//go:build darwin
+
+package fileops
+
+import (
+	"os"
+	"os/exec"
+)
+
+func reflink(src, dst string) error {
+	// cp -c uses clonefile(2), which only succeeds on APFS
+	os.Remove(dst)
+	return exec.Command("cp", "-c", src, dst).Run()
+}
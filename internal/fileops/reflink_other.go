@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package fileops
+
+import "errors"
+
+func reflink(src, dst string) error {
+	return errors.New("copy-on-write clones are not supported on this platform")
+}
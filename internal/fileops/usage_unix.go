@@ -0,0 +1,17 @@
+//go:build unix
+
+package fileops
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// DiskUsage returns the bytes a file occupies on disk, the identity of its data and how many hard links share it
+func DiskUsage(info fs.FileInfo) (int64, FileID, uint64) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.Size(), FileID{}, 1
+	}
+	return st.Blocks * 512, FileID{Dev: uint64(st.Dev), Ino: uint64(st.Ino)}, uint64(st.Nlink)
+}
@@ -0,0 +1,84 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+)
+
+// PullRequest states normalised across providers
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+	StateMerged = "merged"
+)
+
+// CI states normalised across providers
+const (
+	CISuccess = "success"
+	CIFailure = "failure"
+	CIPending = "pending"
+)
+
+// Review states normalised across providers
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes-requested"
+	ReviewPending          = "pending"
+)
+
+// PullRequest is a pull request (GitHub, Gitea) or merge request (GitLab)
+type PullRequest struct {
+	Number      int
+	Title       string
+	URL         string
+	State       string
+	Draft       bool
+	HeadBranch  string
+	BaseBranch  string
+	HeadSHA     string
+	CIStatus    string
+	ReviewState string
+}
+
+// IsMerged reports whether the pull request has been merged
+func (pr *PullRequest) IsMerged() bool {
+	return pr.State == StateMerged
+}
+
+// CreatePullRequestOptions describes a pull request to open
+type CreatePullRequestOptions struct {
+	Title string
+	Body  string
+	Head  string
+	Base  string
+	Draft bool
+}
+
+// Provider talks to the REST API of a code forge
+type Provider interface {
+	// Name returns the provider type, e.g. "github"
+	Name() string
+	// FindPullRequest returns the most recent pull request for a head branch, or nil if there is none
+	FindPullRequest(ctx context.Context, branch string) (*PullRequest, error)
+	// CreatePullRequest opens a new pull request
+	CreatePullRequest(ctx context.Context, opts CreatePullRequestOptions) (*PullRequest, error)
+}
+
+// NewProvider creates the provider for a forge type, repository path (owner/name) and API settings
+func NewProvider(forgeType, baseURL, token, repoPath string) (Provider, error) {
+	client := newClient(baseURL, token)
+
+	switch forgeType {
+	case TypeGitHub:
+		client.authHeader = func(token string) (string, string) { return "Authorization", "Bearer " + token }
+		return &GitHub{client: client, repoPath: repoPath}, nil
+	case TypeGitLab:
+		client.authHeader = func(token string) (string, string) { return "PRIVATE-TOKEN", token }
+		return &GitLab{client: client, repoPath: repoPath}, nil
+	case TypeGitea:
+		client.authHeader = func(token string) (string, string) { return "Authorization", "token " + token }
+		return &Gitea{client: client, repoPath: repoPath}, nil
+	default:
+		return nil, fmt.Errorf("unsupported forge type '%s'", forgeType)
+	}
+}
@@ -0,0 +1,114 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Gitea implements Provider for the Gitea (and Forgejo) REST API
+type Gitea struct {
+	client   *client
+	repoPath string
+}
+
+type giteaPullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+	Draft   bool   `json:"draft"`
+	Head    struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+type giteaReview struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State string `json:"state"`
+}
+
+func (g *Gitea) Name() string {
+	return TypeGitea
+}
+
+func (g *Gitea) FindPullRequest(ctx context.Context, branch string) (*PullRequest, error) {
+	// Gitea cannot filter by head branch, so scan the most recently updated pull requests
+	var pulls []giteaPullRequest
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls?state=all&sort=recentupdate&limit=50", g.repoPath), nil, &pulls); err != nil {
+		return nil, err
+	}
+
+	for _, pull := range pulls {
+		if pull.Head.Ref != branch {
+			continue
+		}
+
+		pr := g.convert(pull)
+
+		var status struct {
+			State string `json:"state"`
+		}
+		if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/commits/%s/status", g.repoPath, pr.HeadSHA), nil, &status); err == nil {
+			pr.CIStatus = normaliseCIStatus(status.State)
+		}
+
+		var reviews []giteaReview
+		if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d/reviews", g.repoPath, pr.Number), nil, &reviews); err == nil {
+			latest := make(map[string]string)
+			for _, review := range reviews {
+				recordReview(latest, review.User.Login, review.State)
+			}
+			pr.ReviewState = summariseReviews(latest, "APPROVED", "REQUEST_CHANGES")
+		}
+
+		return pr, nil
+	}
+
+	return nil, nil
+}
+
+func (g *Gitea) CreatePullRequest(ctx context.Context, opts CreatePullRequestOptions) (*PullRequest, error) {
+	title := opts.Title
+	if opts.Draft {
+		title = "WIP: " + title
+	}
+
+	body := map[string]interface{}{
+		"title": title,
+		"body":  opts.Body,
+		"head":  opts.Head,
+		"base":  opts.Base,
+	}
+
+	var created giteaPullRequest
+	if err := g.client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls", g.repoPath), body, &created); err != nil {
+		return nil, err
+	}
+	return g.convert(created), nil
+}
+
+func (g *Gitea) convert(pr giteaPullRequest) *PullRequest {
+	state := pr.State
+	if pr.Merged {
+		state = StateMerged
+	}
+
+	return &PullRequest{
+		Number:     pr.Number,
+		Title:      pr.Title,
+		URL:        pr.HTMLURL,
+		State:      state,
+		Draft:      pr.Draft,
+		HeadBranch: pr.Head.Ref,
+		BaseBranch: pr.Base.Ref,
+		HeadSHA:    pr.Head.SHA,
+	}
+}
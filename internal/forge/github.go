@@ -0,0 +1,151 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitHub implements Provider for the GitHub REST API
+type GitHub struct {
+	client   *client
+	repoPath string
+}
+
+type githubPullRequest struct {
+	Number   int     `json:"number"`
+	Title    string  `json:"title"`
+	HTMLURL  string  `json:"html_url"`
+	State    string  `json:"state"`
+	Draft    bool    `json:"draft"`
+	MergedAt *string `json:"merged_at"`
+	Head     struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+type githubReview struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State string `json:"state"`
+}
+
+func (g *GitHub) Name() string {
+	return TypeGitHub
+}
+
+func (g *GitHub) FindPullRequest(ctx context.Context, branch string) (*PullRequest, error) {
+	owner, _, _ := strings.Cut(g.repoPath, "/")
+	query := url.Values{
+		"head":  {owner + ":" + branch},
+		"state": {"all"},
+	}
+
+	var pulls []githubPullRequest
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls?%s", g.repoPath, query.Encode()), nil, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+
+	pr := g.convert(pulls[0])
+
+	pr.CIStatus = g.ciStatus(ctx, pr.HeadSHA)
+
+	var reviews []githubReview
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d/reviews", g.repoPath, pr.Number), nil, &reviews); err == nil {
+		latest := make(map[string]string)
+		for _, review := range reviews {
+			recordReview(latest, review.User.Login, review.State)
+		}
+		pr.ReviewState = summariseReviews(latest, "APPROVED", "CHANGES_REQUESTED")
+	}
+
+	return pr, nil
+}
+
+// ciStatus combines the commit statuses set through the status API with the check runs of GitHub Actions and other
+// apps, which the status API does not report. A commit with neither has no CI
+func (g *GitHub) ciStatus(ctx context.Context, sha string) string {
+	var states []string
+
+	var status struct {
+		State      string `json:"state"`
+		TotalCount int    `json:"total_count"`
+	}
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/commits/%s/status", g.repoPath, sha), nil, &status); err == nil && status.TotalCount > 0 {
+		states = append(states, normaliseCIStatus(status.State))
+	}
+
+	var checks struct {
+		TotalCount int `json:"total_count"`
+		CheckRuns  []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/commits/%s/check-runs?per_page=100", g.repoPath, sha), nil, &checks); err == nil {
+		for _, run := range checks.CheckRuns {
+			states = append(states, checkRunStatus(run.Status, run.Conclusion))
+		}
+	}
+
+	return combineCIStatus(states)
+}
+
+// checkRunStatus maps a GitHub check run onto success, failure or pending
+func checkRunStatus(status, conclusion string) string {
+	if status != "completed" {
+		return CIPending
+	}
+	switch conclusion {
+	case "success", "neutral", "skipped":
+		return CISuccess
+	case "failure", "timed_out", "cancelled", "startup_failure":
+		return CIFailure
+	default:
+		return CIPending
+	}
+}
+
+func (g *GitHub) CreatePullRequest(ctx context.Context, opts CreatePullRequestOptions) (*PullRequest, error) {
+	body := map[string]interface{}{
+		"title": opts.Title,
+		"body":  opts.Body,
+		"head":  opts.Head,
+		"base":  opts.Base,
+		"draft": opts.Draft,
+	}
+
+	var created githubPullRequest
+	if err := g.client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls", g.repoPath), body, &created); err != nil {
+		return nil, err
+	}
+	return g.convert(created), nil
+}
+
+func (g *GitHub) convert(pr githubPullRequest) *PullRequest {
+	state := pr.State
+	if pr.MergedAt != nil {
+		state = StateMerged
+	}
+
+	return &PullRequest{
+		Number:     pr.Number,
+		Title:      pr.Title,
+		URL:        pr.HTMLURL,
+		State:      state,
+		Draft:      pr.Draft,
+		HeadBranch: pr.Head.Ref,
+		BaseBranch: pr.Base.Ref,
+		HeadSHA:    pr.Head.SHA,
+	}
+}
@@ -0,0 +1,116 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GitLab implements Provider for the GitLab REST API
+type GitLab struct {
+	client   *client
+	repoPath string
+}
+
+type gitlabMergeRequest struct {
+	IID          int    `json:"iid"`
+	Title        string `json:"title"`
+	WebURL       string `json:"web_url"`
+	State        string `json:"state"`
+	Draft        bool   `json:"draft"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	SHA          string `json:"sha"`
+	HeadPipeline *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+func (g *GitLab) Name() string {
+	return TypeGitLab
+}
+
+func (g *GitLab) projectPath() string {
+	return "/projects/" + url.PathEscape(g.repoPath)
+}
+
+func (g *GitLab) FindPullRequest(ctx context.Context, branch string) (*PullRequest, error) {
+	query := url.Values{
+		"source_branch": {branch},
+		"order_by":      {"updated_at"},
+	}
+
+	var mergeRequests []gitlabMergeRequest
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/merge_requests?%s", g.projectPath(), query.Encode()), nil, &mergeRequests); err != nil {
+		return nil, err
+	}
+	if len(mergeRequests) == 0 {
+		return nil, nil
+	}
+
+	// The list endpoint omits the pipeline, so fetch the merge request itself
+	mergeRequest := mergeRequests[0]
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/merge_requests/%d", g.projectPath(), mergeRequest.IID), nil, &mergeRequest); err != nil {
+		return nil, err
+	}
+
+	pr := g.convert(mergeRequest)
+
+	var approvals struct {
+		Approved bool `json:"approved"`
+	}
+	if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/merge_requests/%d/approvals", g.projectPath(), pr.Number), nil, &approvals); err == nil {
+		pr.ReviewState = ReviewPending
+		if approvals.Approved {
+			pr.ReviewState = ReviewApproved
+		}
+	}
+
+	return pr, nil
+}
+
+func (g *GitLab) CreatePullRequest(ctx context.Context, opts CreatePullRequestOptions) (*PullRequest, error) {
+	title := opts.Title
+	if opts.Draft {
+		title = "Draft: " + title
+	}
+
+	body := map[string]interface{}{
+		"title":         title,
+		"description":   opts.Body,
+		"source_branch": opts.Head,
+		"target_branch": opts.Base,
+	}
+
+	var created gitlabMergeRequest
+	if err := g.client.do(ctx, http.MethodPost, g.projectPath()+"/merge_requests", body, &created); err != nil {
+		return nil, err
+	}
+	return g.convert(created), nil
+}
+
+func (g *GitLab) convert(mr gitlabMergeRequest) *PullRequest {
+	state := mr.State
+	switch state {
+	case "opened", "locked":
+		state = StateOpen
+	case "merged":
+		state = StateMerged
+	}
+
+	pr := &PullRequest{
+		Number:     mr.IID,
+		Title:      mr.Title,
+		URL:        mr.WebURL,
+		State:      state,
+		Draft:      mr.Draft,
+		HeadBranch: mr.SourceBranch,
+		BaseBranch: mr.TargetBranch,
+		HeadSHA:    mr.SHA,
+	}
+	if mr.HeadPipeline != nil {
+		pr.CIStatus = normaliseCIStatus(mr.HeadPipeline.Status)
+	}
+	return pr
+}
@@ -0,0 +1,226 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestServer serves canned JSON responses keyed by "METHOD path?query" and records request headers
+func newTestServer(t *testing.T, responses map[string]interface{}, headers *http.Header) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if headers != nil {
+			*headers = r.Header.Clone()
+		}
+
+		key := r.Method + " " + r.URL.EscapedPath()
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+
+		response, ok := responses[key]
+		if !ok {
+			t.Logf("unexpected request: %s", key)
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitHub_FindPullRequest(t *testing.T) {
+	var headers http.Header
+	server := newTestServer(t, map[string]interface{}{
+		"GET /repos/acme/app/pulls?head=acme%3Afeature&state=all": []map[string]interface{}{{
+			"number": 12, "title": "Add feature", "html_url": "https://github.com/acme/app/pull/12",
+			"state": "closed", "merged_at": "2024-01-01T00:00:00Z",
+			"head": map[string]string{"ref": "feature", "sha": "abc"}, "base": map[string]string{"ref": "main"},
+		}},
+		"GET /repos/acme/app/commits/abc/status": map[string]interface{}{"state": "success", "total_count": 1},
+		"GET /repos/acme/app/commits/abc/check-runs?per_page=100": map[string]interface{}{
+			"total_count": 1, "check_runs": []map[string]interface{}{{"status": "completed", "conclusion": "success"}},
+		},
+		"GET /repos/acme/app/pulls/12/reviews": []map[string]interface{}{
+			{"user": map[string]string{"login": "bob"}, "state": "CHANGES_REQUESTED"},
+			{"user": map[string]string{"login": "bob"}, "state": "APPROVED"},
+			{"user": map[string]string{"login": "bob"}, "state": "COMMENTED"},
+		},
+	}, &headers)
+
+	provider, err := NewProvider(TypeGitHub, server.URL, "secret", "acme/app")
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+
+	pr, err := provider.FindPullRequest(context.Background(), "feature")
+	if err != nil {
+		t.Fatalf("FindPullRequest failed: %v", err)
+	}
+
+	expected := PullRequest{
+		Number: 12, Title: "Add feature", URL: "https://github.com/acme/app/pull/12", State: StateMerged,
+		HeadBranch: "feature", BaseBranch: "main", HeadSHA: "abc", CIStatus: CISuccess, ReviewState: ReviewApproved,
+	}
+	if *pr != expected {
+		t.Errorf("FindPullRequest() = %+v, want %+v", *pr, expected)
+	}
+	if headers.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Expected bearer token authorization, got %q", headers.Get("Authorization"))
+	}
+}
+
+func TestGitHub_FindPullRequest_CIStatus(t *testing.T) {
+	noStatuses := map[string]interface{}{"state": "pending", "total_count": 0}
+	tests := map[string]struct {
+		status    map[string]interface{}
+		checkRuns []map[string]interface{}
+		expected  string
+	}{
+		"no CI": {noStatuses, nil, ""},
+		"actions only, running": {noStatuses, []map[string]interface{}{
+			{"status": "completed", "conclusion": "success"}, {"status": "in_progress"},
+		}, CIPending},
+		"actions only, passed": {noStatuses, []map[string]interface{}{
+			{"status": "completed", "conclusion": "success"}, {"status": "completed", "conclusion": "skipped"},
+		}, CISuccess},
+		"status passed, check failed": {map[string]interface{}{"state": "success", "total_count": 2}, []map[string]interface{}{
+			{"status": "completed", "conclusion": "timed_out"},
+		}, CIFailure},
+		"status pending, no checks": {map[string]interface{}{"state": "pending", "total_count": 1}, nil, CIPending},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newTestServer(t, map[string]interface{}{
+				"GET /repos/acme/app/pulls?head=acme%3Afeature&state=all": []map[string]interface{}{{
+					"number": 12, "state": "open", "head": map[string]string{"ref": "feature", "sha": "abc"},
+				}},
+				"GET /repos/acme/app/commits/abc/status": tt.status,
+				"GET /repos/acme/app/commits/abc/check-runs?per_page=100": map[string]interface{}{
+					"total_count": len(tt.checkRuns), "check_runs": tt.checkRuns,
+				},
+				"GET /repos/acme/app/pulls/12/reviews": []interface{}{},
+			}, nil)
+
+			provider, _ := NewProvider(TypeGitHub, server.URL, "", "acme/app")
+			pr, err := provider.FindPullRequest(context.Background(), "feature")
+			if err != nil {
+				t.Fatalf("FindPullRequest failed: %v", err)
+			}
+			if pr.CIStatus != tt.expected {
+				t.Errorf("Expected CI status %q, got %q", tt.expected, pr.CIStatus)
+			}
+		})
+	}
+}
+
+func TestGitHub_FindPullRequest_None(t *testing.T) {
+	server := newTestServer(t, map[string]interface{}{
+		"GET /repos/acme/app/pulls?head=acme%3Afeature&state=all": []interface{}{},
+	}, nil)
+
+	provider, _ := NewProvider(TypeGitHub, server.URL, "", "acme/app")
+	pr, err := provider.FindPullRequest(context.Background(), "feature")
+	if err != nil || pr != nil {
+		t.Errorf("Expected no pull request and no error, got %+v (err: %v)", pr, err)
+	}
+}
+
+func TestGitHub_CreatePullRequest(t *testing.T) {
+	server := newTestServer(t, map[string]interface{}{
+		"POST /repos/acme/app/pulls": map[string]interface{}{
+			"number": 13, "html_url": "https://github.com/acme/app/pull/13", "state": "open",
+			"head": map[string]string{"ref": "feature"}, "base": map[string]string{"ref": "main"},
+		},
+	}, nil)
+
+	provider, _ := NewProvider(TypeGitHub, server.URL, "secret", "acme/app")
+	pr, err := provider.CreatePullRequest(context.Background(), CreatePullRequestOptions{Title: "Add feature", Head: "feature", Base: "main"})
+	if err != nil {
+		t.Fatalf("CreatePullRequest failed: %v", err)
+	}
+	if pr.Number != 13 || pr.State != StateOpen {
+		t.Errorf("Expected open pull request #13, got %+v", pr)
+	}
+}
+
+func TestGitLab_FindPullRequest(t *testing.T) {
+	var headers http.Header
+	server := newTestServer(t, map[string]interface{}{
+		"GET /projects/group%2Fapp/merge_requests?order_by=updated_at&source_branch=feature": []map[string]interface{}{{
+			"iid": 5, "state": "opened", "source_branch": "feature", "target_branch": "main",
+		}},
+		"GET /projects/group%2Fapp/merge_requests/5": map[string]interface{}{
+			"iid": 5, "title": "Add feature", "web_url": "https://gitlab.com/group/app/-/merge_requests/5",
+			"state": "opened", "draft": true, "source_branch": "feature", "target_branch": "main", "sha": "def",
+			"head_pipeline": map[string]string{"status": "running"},
+		},
+		"GET /projects/group%2Fapp/merge_requests/5/approvals": map[string]bool{"approved": false},
+	}, &headers)
+
+	provider, _ := NewProvider(TypeGitLab, server.URL, "secret", "group/app")
+	pr, err := provider.FindPullRequest(context.Background(), "feature")
+	if err != nil {
+		t.Fatalf("FindPullRequest failed: %v", err)
+	}
+
+	expected := PullRequest{
+		Number: 5, Title: "Add feature", URL: "https://gitlab.com/group/app/-/merge_requests/5", State: StateOpen, Draft: true,
+		HeadBranch: "feature", BaseBranch: "main", HeadSHA: "def", CIStatus: CIPending, ReviewState: ReviewPending,
+	}
+	if *pr != expected {
+		t.Errorf("FindPullRequest() = %+v, want %+v", *pr, expected)
+	}
+	if headers.Get("PRIVATE-TOKEN") != "secret" {
+		t.Errorf("Expected PRIVATE-TOKEN header, got %q", headers.Get("PRIVATE-TOKEN"))
+	}
+}
+
+func TestGitea_FindPullRequest(t *testing.T) {
+	var headers http.Header
+	server := newTestServer(t, map[string]interface{}{
+		"GET /repos/org/app/pulls?state=all&sort=recentupdate&limit=50": []map[string]interface{}{
+			{"number": 2, "state": "open", "head": map[string]string{"ref": "other", "sha": "111"}},
+			{
+				"number": 3, "title": "Add feature", "html_url": "https://gitea.example.com/org/app/pulls/3",
+				"state": "open", "head": map[string]string{"ref": "feature", "sha": "222"}, "base": map[string]string{"ref": "main"},
+			},
+		},
+		"GET /repos/org/app/commits/222/status": map[string]string{"state": "failure"},
+		"GET /repos/org/app/pulls/3/reviews": []map[string]interface{}{
+			{"user": map[string]string{"login": "alice"}, "state": "APPROVED"},
+			{"user": map[string]string{"login": "bob"}, "state": "REQUEST_CHANGES"},
+		},
+	}, &headers)
+
+	provider, _ := NewProvider(TypeGitea, server.URL, "secret", "org/app")
+	pr, err := provider.FindPullRequest(context.Background(), "feature")
+	if err != nil {
+		t.Fatalf("FindPullRequest failed: %v", err)
+	}
+
+	expected := PullRequest{
+		Number: 3, Title: "Add feature", URL: "https://gitea.example.com/org/app/pulls/3", State: StateOpen,
+		HeadBranch: "feature", BaseBranch: "main", HeadSHA: "222", CIStatus: CIFailure, ReviewState: ReviewChangesRequested,
+	}
+	if *pr != expected {
+		t.Errorf("FindPullRequest() = %+v, want %+v", *pr, expected)
+	}
+	if headers.Get("Authorization") != "token secret" {
+		t.Errorf("Expected token authorization, got %q", headers.Get("Authorization"))
+	}
+}
+
+func TestClient_ErrorStatus(t *testing.T) {
+	server := newTestServer(t, map[string]interface{}{}, nil)
+
+	provider, _ := NewProvider(TypeGitHub, server.URL, "", "acme/app")
+	if _, err := provider.FindPullRequest(context.Background(), "feature"); err == nil {
+		t.Error("Expected error for a 404 response, got nil")
+	}
+}
@@ -0,0 +1,106 @@
+package forge
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/git"
+)
+
+// Supported forge types
+const (
+	TypeGitHub = "github"
+	TypeGitLab = "gitlab"
+	TypeGitea  = "gitea"
+)
+
+// Environment variables that override the forge settings from config
+const (
+	EnvForgeType  = "WT_FORGE_TYPE"
+	EnvForgeURL   = "WT_FORGE_URL"
+	EnvForgeToken = "WT_FORGE_TOKEN"
+)
+
+// tokenEnvVars lists the conventional token variables checked for each forge type
+var tokenEnvVars = map[string][]string{
+	TypeGitHub: {"GITHUB_TOKEN", "GH_TOKEN"},
+	TypeGitLab: {"GITLAB_TOKEN"},
+	TypeGitea:  {"GITEA_TOKEN"},
+}
+
+// ProviderForRemote builds the provider for a remote URL from config, environment and host-based defaults
+func ProviderForRemote(remoteURL string, forges []config.ForgeConfig) (Provider, error) {
+	host := git.ExtractHostFromURL(remoteURL)
+	repoPath := git.ExtractRepoPathFromURL(remoteURL)
+	if repoPath == "" {
+		return nil, fmt.Errorf("cannot determine repository path from remote URL '%s'", remoteURL)
+	}
+
+	var settings config.ForgeConfig
+	for _, forge := range forges {
+		if strings.EqualFold(forge.Host, host) {
+			settings = forge
+			break
+		}
+	}
+
+	if forgeType := os.Getenv(EnvForgeType); forgeType != "" {
+		settings.Type = forgeType
+	}
+	if settings.Type == "" {
+		settings.Type = detectType(host)
+	}
+	if settings.Type == "" {
+		return nil, fmt.Errorf("cannot detect forge type for host '%s'\n\n💡 Add it under 'forges' in the config or set %s", host, EnvForgeType)
+	}
+
+	if baseURL := os.Getenv(EnvForgeURL); baseURL != "" {
+		settings.BaseURL = baseURL
+	}
+	if settings.BaseURL == "" {
+		settings.BaseURL = defaultBaseURL(settings.Type, host)
+	}
+
+	if token := os.Getenv(EnvForgeToken); token != "" {
+		settings.Token = token
+	}
+	for _, name := range tokenEnvVars[settings.Type] {
+		if settings.Token != "" {
+			break
+		}
+		settings.Token = os.Getenv(name)
+	}
+
+	return NewProvider(settings.Type, settings.BaseURL, settings.Token, repoPath)
+}
+
+func detectType(host string) string {
+	host = strings.ToLower(host)
+
+	switch {
+	case strings.Contains(host, "github"):
+		return TypeGitHub
+	case strings.Contains(host, "gitlab"):
+		return TypeGitLab
+	case strings.Contains(host, "gitea"), host == "codeberg.org":
+		return TypeGitea
+	default:
+		return ""
+	}
+}
+
+func defaultBaseURL(forgeType, host string) string {
+	switch forgeType {
+	case TypeGitHub:
+		if host == "github.com" {
+			return "https://api.github.com"
+		}
+		return fmt.Sprintf("https://%s/api/v3", host)
+	case TypeGitLab:
+		return fmt.Sprintf("https://%s/api/v4", host)
+	default:
+		return fmt.Sprintf("https://%s/api/v1", host)
+	}
+}
@@ -0,0 +1,92 @@
+package forge
+
+import (
+	"testing"
+
+	"worktree-manager/internal/config"
+)
+
+func TestProviderForRemote(t *testing.T) {
+	t.Setenv(EnvForgeType, "")
+	t.Setenv(EnvForgeURL, "")
+	t.Setenv(EnvForgeToken, "")
+
+	tests := []struct {
+		name         string
+		url          string
+		forges       []config.ForgeConfig
+		expectedType string
+		expectedURL  string
+	}{
+		{
+			name:         "GitHub.com",
+			url:          "git@github.com:acme/app.git",
+			expectedType: TypeGitHub,
+			expectedURL:  "https://api.github.com",
+		},
+		{
+			name:         "Self-hosted GitLab",
+			url:          "https://gitlab.example.com/group/app.git",
+			expectedType: TypeGitLab,
+			expectedURL:  "https://gitlab.example.com/api/v4",
+		},
+		{
+			name:         "Configured Gitea host",
+			url:          "https://git.example.com/org/app.git",
+			forges:       []config.ForgeConfig{{Host: "git.example.com", Type: TypeGitea, BaseURL: "https://git.example.com/api/v1"}},
+			expectedType: TypeGitea,
+			expectedURL:  "https://git.example.com/api/v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := ProviderForRemote(tt.url, tt.forges)
+			if err != nil {
+				t.Fatalf("ProviderForRemote failed: %v", err)
+			}
+			if provider.Name() != tt.expectedType {
+				t.Errorf("Expected provider %s, got %s", tt.expectedType, provider.Name())
+			}
+			if baseURL := providerClient(provider).baseURL; baseURL != tt.expectedURL {
+				t.Errorf("Expected base URL %s, got %s", tt.expectedURL, baseURL)
+			}
+		})
+	}
+}
+
+func TestProviderForRemote_EnvironmentOverride(t *testing.T) {
+	t.Setenv(EnvForgeType, TypeGitea)
+	t.Setenv(EnvForgeURL, "http://127.0.0.1:3000/api/v1")
+	t.Setenv(EnvForgeToken, "from-env")
+
+	provider, err := ProviderForRemote("https://unknown.example.com/org/app.git", nil)
+	if err != nil {
+		t.Fatalf("ProviderForRemote failed: %v", err)
+	}
+
+	client := providerClient(provider)
+	if provider.Name() != TypeGitea || client.baseURL != "http://127.0.0.1:3000/api/v1" || client.token != "from-env" {
+		t.Errorf("Expected environment overrides to apply, got %s %s %s", provider.Name(), client.baseURL, client.token)
+	}
+}
+
+func TestProviderForRemote_UnknownHost(t *testing.T) {
+	t.Setenv(EnvForgeType, "")
+
+	if _, err := ProviderForRemote("https://unknown.example.com/org/app.git", nil); err == nil {
+		t.Error("Expected error for an unknown forge host, got nil")
+	}
+}
+
+func providerClient(provider Provider) *client {
+	switch p := provider.(type) {
+	case *GitHub:
+		return p.client
+	case *GitLab:
+		return p.client
+	case *Gitea:
+		return p.client
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package forge
+
+// normaliseCIStatus maps provider-specific commit and pipeline states onto success, failure or pending
+func normaliseCIStatus(status string) string {
+	switch status {
+	case "success", "passed":
+		return CISuccess
+	case "failure", "failed", "error", "canceled", "cancelled":
+		return CIFailure
+	case "":
+		return ""
+	default:
+		return CIPending
+	}
+}
+
+// combineCIStatus reduces the states of several CI systems to one: any failure fails, then anything still running
+// is pending. No states means no CI
+func combineCIStatus(states []string) string {
+	combined := ""
+	for _, state := range states {
+		switch {
+		case state == CIFailure:
+			return CIFailure
+		case state == CIPending:
+			combined = CIPending
+		case state == CISuccess && combined == "":
+			combined = CISuccess
+		}
+	}
+	return combined
+}
+
+// summariseReviews reduces each reviewer's latest review state to a single review state
+func summariseReviews(latestByReviewer map[string]string, approved, changesRequested string) string {
+	state := ReviewPending
+	for _, review := range latestByReviewer {
+		switch review {
+		case changesRequested:
+			return ReviewChangesRequested
+		case approved:
+			state = ReviewApproved
+		}
+	}
+	return state
+}
+
+// recordReview keeps a reviewer's latest decisive review, ignoring plain comments
+func recordReview(latestByReviewer map[string]string, reviewer, state string) {
+	switch state {
+	case "COMMENTED", "COMMENT", "PENDING":
+		return
+	}
+	latestByReviewer[reviewer] = state
+}
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"worktree-manager/internal/executors"
+)
+
+// Names of the git backends that can be selected with the git-backend config value
+const (
+	BackendCLI   = "cli"
+	BackendGoGit = "go-git"
+)
+
+// ErrUnsupported is returned by a backend for an operation or repository it cannot handle; the CLI is used instead
+var ErrUnsupported = errors.New("not supported by this git backend")
+
+// GitBackend performs the read-only git operations that wt runs most often, such as listing worktrees and their status.
+// Operations that change a repository always go through the git CLI
+type GitBackend interface {
+	Name() string
+	ListWorktrees(ctx context.Context, repoDir string) ([]Worktree, error)
+	ResolveRef(ctx context.Context, repoDir, ref string) (string, error)
+	Status(ctx context.Context, worktreePath string) (WorktreeStatus, error)
+	AheadBehind(ctx context.Context, repoDir, ref, upstream string) (ahead, behind int, err error)
+}
+
+// NewBackend returns the backend with the given name, running git through executor where it needs the CLI
+func NewBackend(name string, executor executors.CommandExecutor) (GitBackend, error) {
+	switch name {
+	case BackendCLI, "":
+		return &cliBackend{executor: executor}, nil
+	case BackendGoGit:
+		return &goGitBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unknown git backend '%s' (valid options: %s, %s)", name, BackendCLI, BackendGoGit)
+	}
+}
+
+// cliBackend runs the git executable for every operation
+type cliBackend struct {
+	executor executors.CommandExecutor
+}
+
+func (b *cliBackend) Name() string {
+	return BackendCLI
+}
+
+// output runs git in dir and returns its trimmed stdout
+func (b *cliBackend) output(ctx context.Context, dir string, args ...string) (string, error) {
+	result, err := b.executor.Execute(ctx, &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       args,
+		WorkingDir: dir,
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.TrimmedStdout(), nil
+}
+
+func (b *cliBackend) ListWorktrees(ctx context.Context, repoDir string) ([]Worktree, error) {
+	output, err := b.output(ctx, repoDir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	return parseWorktreeList(output), nil
+}
+
+func (b *cliBackend) ResolveRef(ctx context.Context, repoDir, ref string) (string, error) {
+	return b.output(ctx, repoDir, "rev-parse", "--verify", "--quiet", ref)
+}
+
+func (b *cliBackend) Status(ctx context.Context, worktreePath string) (WorktreeStatus, error) {
+	statusOutput, err := b.output(ctx, worktreePath, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return WorktreeStatus{}, fmt.Errorf("failed to get status of %s: %w", worktreePath, err)
+	}
+
+	status := parseStatus(statusOutput)
+
+	// A branch without commits has no log, which is not an error for status purposes
+	if logOutput, err := b.output(ctx, worktreePath, "log", "-1", "--format=%ct%x00%s"); err == nil {
+		status.LastCommitTime, status.LastCommitSubject = parseLastCommit(logOutput)
+	}
+
+	return status, nil
+}
+
+func (b *cliBackend) AheadBehind(ctx context.Context, repoDir, ref, upstream string) (int, int, error) {
+	output, err := b.output(ctx, repoDir, "rev-list", "--left-right", "--count", ref+"..."+upstream)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare %s with %s: %w", ref, upstream, err)
+	}
+
+	left, right, _ := strings.Cut(output, "\t")
+	ahead, err := strconv.Atoi(left)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	behind, err := strconv.Atoi(right)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	return ahead, behind, nil
+}
@@ -0,0 +1,255 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/executors"
+	"worktree-manager/internal/testutil"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupBackendRepo creates a clone with worktrees in every state the backends have to agree on
+func setupBackendRepo(t *testing.T) string {
+	t.Helper()
+	testutil.SetupHome(t)
+	origin, _ := testutil.NewOriginRepo(t)
+
+	repoDir := filepath.Join(t.TempDir(), "app")
+	worktrees := t.TempDir()
+	testutil.RunGit(t, "", "clone", "--quiet", origin, repoDir)
+	writeFile(t, filepath.Join(repoDir, ".gitignore"), "*.log\n")
+	testutil.RunGit(t, repoDir, "add", ".")
+	testutil.RunGit(t, repoDir, "commit", "--quiet", "-m", "Ignore logs\n\nWith a body")
+	testutil.RunGit(t, repoDir, "push", "--quiet", "origin", "main")
+	testutil.RunGit(t, repoDir, "push", "--quiet", "origin", "main:gone")
+	testutil.RunGit(t, repoDir, "fetch", "--quiet")
+
+	ahead := filepath.Join(worktrees, "ahead")
+	testutil.RunGit(t, repoDir, "worktree", "add", "--quiet", "-b", "ahead", ahead, "origin/main")
+	writeFile(t, filepath.Join(ahead, "ahead.txt"), "ahead\n")
+	testutil.RunGit(t, ahead, "add", ".")
+	testutil.RunGit(t, ahead, "commit", "--quiet", "-m", "Ahead work")
+
+	dirty := filepath.Join(worktrees, "dirty")
+	testutil.RunGit(t, repoDir, "worktree", "add", "--quiet", "-b", "dirty", dirty, "origin/main")
+	writeFile(t, filepath.Join(dirty, "untracked.txt"), "new\n")
+
+	ignored := filepath.Join(worktrees, "ignored")
+	testutil.RunGit(t, repoDir, "worktree", "add", "--quiet", "-b", "ignored", ignored, "main")
+	writeFile(t, filepath.Join(ignored, "debug.log"), "ignored\n")
+
+	// Files excluded in the shared info/exclude, such as the .wt.env wt writes, leave a linked worktree clean
+	excluded := filepath.Join(worktrees, "excluded")
+	testutil.RunGit(t, repoDir, "worktree", "add", "--quiet", "-b", "excluded", excluded, "main")
+	writeFile(t, filepath.Join(repoDir, ".git", "info", "exclude"), "# Written by wt\n/.wt.env\n")
+	writeFile(t, filepath.Join(excluded, ".wt.env"), "WT_BRANCH=excluded\n")
+
+	gone := filepath.Join(worktrees, "gone")
+	testutil.RunGit(t, repoDir, "worktree", "add", "--quiet", "-b", "gone", gone, "origin/gone")
+
+	detached := filepath.Join(worktrees, "detached")
+	testutil.RunGit(t, repoDir, "worktree", "add", "--quiet", "--detach", detached, "main")
+	testutil.RunGit(t, repoDir, "worktree", "lock", "--reason", "busy rebasing", detached)
+
+	missing := filepath.Join(worktrees, "missing")
+	testutil.RunGit(t, repoDir, "worktree", "add", "--quiet", "-b", "missing", missing, "main")
+	if err := os.RemoveAll(missing); err != nil {
+		t.Fatal(err)
+	}
+
+	// Move origin/main on so the tracking worktrees are also behind, and drop the gone branch
+	writeFile(t, filepath.Join(repoDir, "main.txt"), "main\n")
+	testutil.RunGit(t, repoDir, "add", ".")
+	testutil.RunGit(t, repoDir, "commit", "--quiet", "-m", "Main work")
+	testutil.RunGit(t, repoDir, "push", "--quiet", "origin", "main", ":gone")
+	testutil.RunGit(t, repoDir, "fetch", "--quiet", "--prune")
+
+	return repoDir
+}
+
+func TestGoGitBackend_MatchesCLI(t *testing.T) {
+	repoDir := setupBackendRepo(t)
+	ctx := context.Background()
+	cli := &cliBackend{executor: executors.NewSystemCommandExecutor()}
+	native := &goGitBackend{}
+
+	expected, err := cli.ListWorktrees(ctx, repoDir)
+	if err != nil {
+		t.Fatalf("cli ListWorktrees failed: %v", err)
+	}
+	worktrees, err := native.ListWorktrees(ctx, repoDir)
+	if err != nil {
+		t.Fatalf("go-git ListWorktrees failed: %v", err)
+	}
+	if len(worktrees) != len(expected) {
+		t.Fatalf("go-git listed %d worktrees, cli %d:\n%+v\n%+v", len(worktrees), len(expected), worktrees, expected)
+	}
+
+	byPath := map[string]Worktree{}
+	for _, wt := range expected {
+		byPath[wt.Path] = wt
+	}
+	for _, wt := range worktrees {
+		if byPath[wt.Path] != wt {
+			t.Errorf("go-git worktree %+v, cli %+v", wt, byPath[wt.Path])
+		}
+		if wt.Prunable {
+			continue
+		}
+
+		expectedStatus, err := cli.Status(ctx, wt.Path)
+		if err != nil {
+			t.Fatalf("cli Status of %s failed: %v", wt.Path, err)
+		}
+		status, err := native.Status(ctx, wt.Path)
+		if err != nil {
+			t.Fatalf("go-git Status of %s failed: %v", wt.Path, err)
+		}
+		if status != expectedStatus {
+			t.Errorf("Status of %s: go-git %+v, cli %+v", filepath.Base(wt.Path), status, expectedStatus)
+		}
+		if filepath.Base(wt.Path) == "excluded" && status.Dirty {
+			t.Errorf("Expected the excluded file to leave %s clean", wt.Path)
+		}
+	}
+
+	for _, ref := range []string{"refs/heads/ahead", "origin/main", "main"} {
+		expectedHash, _ := cli.ResolveRef(ctx, repoDir, ref)
+		hash, err := native.ResolveRef(ctx, repoDir, ref)
+		if err != nil || hash != expectedHash {
+			t.Errorf("ResolveRef(%s): go-git %s (%v), cli %s", ref, hash, err, expectedHash)
+		}
+	}
+
+	expectedAhead, expectedBehind, _ := cli.AheadBehind(ctx, repoDir, "ahead", "origin/main")
+	ahead, behind, err := native.AheadBehind(ctx, repoDir, "ahead", "origin/main")
+	if err != nil || ahead != expectedAhead || behind != expectedBehind || ahead != 1 || behind != 1 {
+		t.Errorf("AheadBehind: go-git %d/%d (%v), cli %d/%d", ahead, behind, err, expectedAhead, expectedBehind)
+	}
+}
+
+func TestGitOperations_FallsBackToCLI(t *testing.T) {
+	repoDir := setupBackendRepo(t)
+	testutil.RunGit(t, repoDir, "config", "core.sparseCheckout", "true")
+
+	if _, err := (&goGitBackend{}).Status(context.Background(), repoDir); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Expected go-git to leave sparse checkouts to the CLI, got %v", err)
+	}
+
+	backend, err := NewBackend(BackendGoGit, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops := NewGitOperations().WithBackend(backend)
+	if _, err := ops.GetWorktreeStatus(repoDir); err != nil {
+		t.Errorf("Expected the CLI to take over, got %v", err)
+	}
+}
+
+func TestNewBackend_Unknown(t *testing.T) {
+	if _, err := NewBackend("libgit2", nil); err == nil {
+		t.Error("Expected error for unknown backend, got nil")
+	}
+}
+
+func TestAheadBehind_CommitsOfTheSameDate(t *testing.T) {
+	repoDir := setupBackendRepo(t)
+	// Commits made within the same second leave the walk no date to order them by
+	t.Setenv("GIT_COMMITTER_DATE", "2024-01-01T00:00:00Z")
+	for _, name := range []string{"one", "two", "three"} {
+		writeFile(t, filepath.Join(repoDir, name+".txt"), name+"\n")
+		testutil.RunGit(t, repoDir, "add", ".")
+		testutil.RunGit(t, repoDir, "commit", "--quiet", "-m", name)
+	}
+
+	cli := &cliBackend{executor: executors.NewSystemCommandExecutor()}
+	for _, refs := range [][2]string{{"HEAD~1", "HEAD"}, {"HEAD", "HEAD~2"}, {"HEAD~3", "HEAD"}} {
+		expectedAhead, expectedBehind, err := cli.AheadBehind(context.Background(), repoDir, refs[0], refs[1])
+		if err != nil {
+			t.Fatalf("cli AheadBehind failed: %v", err)
+		}
+		ahead, behind, err := (&goGitBackend{}).AheadBehind(context.Background(), repoDir, refs[0], refs[1])
+		if err != nil {
+			t.Fatalf("go-git AheadBehind failed: %v", err)
+		}
+		if ahead != expectedAhead || behind != expectedBehind {
+			t.Errorf("%s...%s: go-git %d ahead %d behind, cli %d ahead %d behind", refs[0], refs[1], ahead, behind, expectedAhead, expectedBehind)
+		}
+	}
+}
+
+func TestAheadBehind_StopsAtSharedHistory(t *testing.T) {
+	testutil.SetupHome(t)
+	repoDir := t.TempDir()
+	testutil.RunGit(t, repoDir, "init", "--quiet", "--initial-branch=main")
+
+	// A long history where every commit has the same date, then one commit on local and two on upstream
+	const shared = 500
+	var stream strings.Builder
+	commit := func(ref string, mark, from int) {
+		message := fmt.Sprintf("commit %d", mark)
+		fmt.Fprintf(&stream, "commit %s\nmark :%d\ncommitter Test <test@example.com> 1704067200 +0000\ndata %d\n%s\n", ref, mark, len(message), message)
+		if from > 0 {
+			fmt.Fprintf(&stream, "from :%d\n", from)
+		}
+		stream.WriteString("\n")
+	}
+	for i := 1; i <= shared; i++ {
+		commit("refs/heads/main", i, i-1)
+	}
+	commit("refs/heads/local", shared+1, shared)
+	commit("refs/heads/upstream", shared+2, shared)
+	commit("refs/heads/upstream", shared+3, shared+2)
+
+	cmd := exec.Command("git", "fast-import", "--quiet")
+	cmd.Dir = repoDir
+	cmd.Stdin = strings.NewReader(stream.String())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git fast-import failed: %v\n%s", err, out)
+	}
+
+	repo, err := openRepository(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, _ := repo.ResolveRevision("local")
+	upstream, _ := repo.ResolveRevision("upstream")
+
+	walk := newHistoryWalk(repo)
+	ahead, behind, err := walk.aheadBehind(*local, *upstream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ahead != 1 || behind != 2 {
+		t.Errorf("Expected 1 ahead and 2 behind, got %d and %d", ahead, behind)
+	}
+	if walk.loaded > 20 {
+		t.Errorf("Expected the walk to stop near the merge base, it read %d of %d commits", walk.loaded, shared+3)
+	}
+}
+
+func TestCommitSubject(t *testing.T) {
+	tests := map[string]string{
+		"Fix login\n":                       "Fix login",
+		"Fix login\nredirect\n\nDetails\n":  "Fix login redirect",
+		"\nLeading blank line\n\nBody text": "Leading blank line",
+	}
+	for message, expected := range tests {
+		if subject := commitSubject(message); subject != expected {
+			t.Errorf("commitSubject(%q) = %q, want %q", message, subject, expected)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+package git
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// goGitBackend reads repositories in-process with go-git instead of starting git.
+// go-git has no notion of linked worktrees, so they are read from the administrative files git keeps for them
+type goGitBackend struct{}
+
+func (b *goGitBackend) Name() string {
+	return BackendGoGit
+}
+
+// unsupported marks a go-git failure so the operation is retried with the CLI, which reports any real error
+func unsupported(err error) error {
+	return fmt.Errorf("%w: %v", ErrUnsupported, err)
+}
+
+func openRepository(path string) (*gogit.Repository, error) {
+	repo, err := gogit.PlainOpenWithOptions(path, &gogit.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, unsupported(err)
+	}
+	return repo, nil
+}
+
+// gitDirs returns the git directory of a worktree or bare repository and the common directory shared by its worktrees
+func gitDirs(path string) (gitDir, commonDir string, err error) {
+	dotGit := filepath.Join(path, ".git")
+	info, statErr := os.Stat(dotGit)
+	switch {
+	case statErr == nil && info.IsDir():
+		gitDir = dotGit
+	case statErr == nil:
+		data, err := os.ReadFile(dotGit)
+		if err != nil {
+			return "", "", err
+		}
+		target, found := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+		if !found {
+			return "", "", fmt.Errorf("invalid .git file in %s", path)
+		}
+		gitDir = absoluteTo(path, target)
+	case fileExists(filepath.Join(path, "HEAD")) && fileExists(filepath.Join(path, "objects")):
+		gitDir = path
+	default:
+		return "", "", fmt.Errorf("not a git repository: %s", path)
+	}
+
+	commonDir = gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = absoluteTo(gitDir, strings.TrimSpace(string(data)))
+	}
+	return gitDir, commonDir, nil
+}
+
+// excludePatterns parses an exclude file, such as info/exclude, whose patterns apply from the root of the worktree
+func excludePatterns(path string) []gitignore.Pattern {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var patterns []gitignore.Pattern
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	return patterns
+}
+
+func absoluteTo(base, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Clean(path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (b *goGitBackend) ListWorktrees(ctx context.Context, repoDir string) ([]Worktree, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	_, commonDir, err := gitDirs(repoDir)
+	if err != nil {
+		return nil, unsupported(err)
+	}
+	repo, err := openRepository(commonDir)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, unsupported(err)
+	}
+
+	main := Worktree{Path: commonDir, Bare: true}
+	if !cfg.Core.IsBare {
+		main = Worktree{Path: filepath.Dir(commonDir)}
+		if err := readWorktreeHead(repo, filepath.Join(commonDir, "HEAD"), &main); err != nil {
+			return nil, err
+		}
+	}
+	worktrees := []Worktree{main}
+
+	adminDir := filepath.Join(commonDir, "worktrees")
+	entries, err := os.ReadDir(adminDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, unsupported(err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(adminDir, entry.Name())
+
+		data, err := os.ReadFile(filepath.Join(dir, "gitdir"))
+		if err != nil {
+			continue
+		}
+		wt := Worktree{Path: filepath.Dir(absoluteTo(dir, strings.TrimSpace(string(data))))}
+
+		if err := readWorktreeHead(repo, filepath.Join(dir, "HEAD"), &wt); err != nil {
+			return nil, err
+		}
+		if reason, err := os.ReadFile(filepath.Join(dir, "locked")); err == nil {
+			wt.Locked = true
+			wt.LockedReason = strings.TrimSpace(string(reason))
+		}
+		if !fileExists(filepath.Join(wt.Path, ".git")) {
+			wt.Prunable = true
+			wt.PrunableReason = "gitdir file points to non-existent location"
+		}
+		worktrees = append(worktrees, wt)
+	}
+
+	return worktrees, nil
+}
+
+// readWorktreeHead fills in the branch and commit of a worktree from its HEAD file
+func readWorktreeHead(repo *gogit.Repository, headPath string, wt *Worktree) error {
+	data, err := os.ReadFile(headPath)
+	if err != nil {
+		return unsupported(err)
+	}
+	head := strings.TrimSpace(string(data))
+
+	branch, symbolic := strings.CutPrefix(head, "ref: ")
+	if !symbolic {
+		wt.Head = head
+		wt.Detached = true
+		return nil
+	}
+
+	wt.Branch = branch
+	wt.Head = plumbing.ZeroHash.String()
+	if ref, err := repo.Reference(plumbing.ReferenceName(branch), true); err == nil {
+		wt.Head = ref.Hash().String()
+	}
+	return nil
+}
+
+func (b *goGitBackend) ResolveRef(ctx context.Context, repoDir, ref string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	repo, err := openRepository(repoDir)
+	if err != nil {
+		return "", err
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return hash.String(), nil
+}
+
+func (b *goGitBackend) Status(ctx context.Context, worktreePath string) (WorktreeStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return WorktreeStatus{}, err
+	}
+
+	repo, err := openRepository(worktreePath)
+	if err != nil {
+		return WorktreeStatus{}, err
+	}
+	gitDir, commonDir, err := gitDirs(worktreePath)
+	if err != nil {
+		return WorktreeStatus{}, unsupported(err)
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return WorktreeStatus{}, unsupported(err)
+	}
+	// go-git would report every file outside the sparse cone as deleted
+	if cfg.Raw.Section("core").Option("sparseCheckout") == "true" {
+		return WorktreeStatus{}, unsupported(errors.New("sparse checkouts"))
+	}
+
+	tree, err := repo.Worktree()
+	if err != nil {
+		return WorktreeStatus{}, unsupported(err)
+	}
+	// git also honours the user's and the system's excludes files
+	if patterns, err := gitignore.LoadGlobalPatterns(tree.Filesystem); err == nil {
+		tree.Excludes = append(tree.Excludes, patterns...)
+	}
+	if patterns, err := gitignore.LoadSystemPatterns(tree.Filesystem); err == nil {
+		tree.Excludes = append(tree.Excludes, patterns...)
+	}
+	// go-git only reads info/exclude below the worktree's .git, which in a linked worktree is a file; git reads the
+	// one in the common directory that all worktrees share
+	if gitDir != commonDir {
+		tree.Excludes = append(tree.Excludes, excludePatterns(filepath.Join(commonDir, "info", "exclude"))...)
+	}
+	changes, err := tree.Status()
+	if err != nil {
+		return WorktreeStatus{}, unsupported(err)
+	}
+	status := WorktreeStatus{Dirty: !changes.IsClean()}
+
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		// A branch without commits has neither upstream divergence nor a last commit
+		return status, nil
+	}
+	if err != nil {
+		return WorktreeStatus{}, unsupported(err)
+	}
+
+	if head.Name().IsBranch() {
+		if branch, ok := cfg.Branches[head.Name().Short()]; ok && branch.Merge != "" {
+			upstreamRef := plumbing.NewRemoteReferenceName(branch.Remote, branch.Merge.Short())
+			status.Upstream = branch.Remote + "/" + branch.Merge.Short()
+			if branch.Remote == "." {
+				upstreamRef = branch.Merge
+				status.Upstream = branch.Merge.Short()
+			}
+
+			// Like git, an upstream whose tracking ref is gone has no divergence
+			if upstream, err := repo.Reference(upstreamRef, true); err == nil {
+				status.Ahead, status.Behind, err = aheadBehind(repo, head.Hash(), upstream.Hash())
+				if err != nil {
+					return WorktreeStatus{}, unsupported(err)
+				}
+			}
+		}
+	}
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return WorktreeStatus{}, unsupported(err)
+	}
+	status.LastCommitTime = time.Unix(commit.Committer.When.Unix(), 0)
+	status.LastCommitSubject = commitSubject(commit.Message)
+
+	return status, nil
+}
+
+func (b *goGitBackend) AheadBehind(ctx context.Context, repoDir, ref, upstream string) (int, int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	repo, err := openRepository(repoDir)
+	if err != nil {
+		return 0, 0, err
+	}
+	local, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	remote, err := repo.ResolveRevision(plumbing.Revision(upstream))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve %s: %w", upstream, err)
+	}
+	return aheadBehind(repo, *local, *remote)
+}
+
+// commitSubject returns the first paragraph of a commit message on one line, as git's %s does
+func commitSubject(message string) string {
+	paragraph, _, _ := strings.Cut(strings.TrimLeft(message, "\n"), "\n\n")
+	return strings.Join(strings.Fields(strings.ReplaceAll(paragraph, "\n", " ")), " ")
+}
+
+// Sides of the history walk in aheadBehind
+const (
+	sideLocal uint8 = 1 << iota
+	sideUpstream
+	sideBoth = sideLocal | sideUpstream
+)
+
+// aheadBehind counts the commits only local and only upstream can reach
+func aheadBehind(repo *gogit.Repository, local, upstream plumbing.Hash) (int, int, error) {
+	if local == upstream {
+		return 0, 0, nil
+	}
+	return newHistoryWalk(repo).aheadBehind(local, upstream)
+}
+
+// historyWalk walks commits newest first from two tips, marking which side reaches them, until every commit left to
+// walk is reachable from both
+type historyWalk struct {
+	repo  *gogit.Repository
+	sides map[plumbing.Hash]uint8
+	queue commitQueue
+	// queued counts the entries of each commit in the queue, and stale the entries of commits both sides reach
+	queued map[plumbing.Hash]int
+	stale  int
+	// loaded counts the commits read, for tests
+	loaded int
+}
+
+func newHistoryWalk(repo *gogit.Repository) *historyWalk {
+	return &historyWalk{repo: repo, sides: map[plumbing.Hash]uint8{}, queued: map[plumbing.Hash]int{}}
+}
+
+func (w *historyWalk) aheadBehind(local, upstream plumbing.Hash) (int, int, error) {
+	if err := w.push(local, sideLocal); err != nil {
+		return 0, 0, err
+	}
+	if err := w.push(upstream, sideUpstream); err != nil {
+		return 0, 0, err
+	}
+
+	for w.queue.Len() > 0 && w.stale < w.queue.Len() {
+		next := heap.Pop(&w.queue).(*object.Commit)
+		w.queued[next.Hash]--
+		side := w.sides[next.Hash]
+		if side == sideBoth {
+			w.stale--
+		}
+		for _, parent := range next.ParentHashes {
+			if err := w.push(parent, side); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	ahead, behind := 0, 0
+	for _, side := range w.sides {
+		switch side {
+		case sideLocal:
+			ahead++
+		case sideUpstream:
+			behind++
+		}
+	}
+	return ahead, behind, nil
+}
+
+// push queues a commit for the sides that reach it
+func (w *historyWalk) push(hash plumbing.Hash, side uint8) error {
+	previous := w.sides[hash]
+	if previous&side == side {
+		return nil
+	}
+	commit, err := w.commit(hash)
+	if err != nil {
+		return err
+	}
+	w.mark(hash, previous|side)
+	heap.Push(&w.queue, commit)
+	w.queued[hash]++
+	if w.sides[hash] == sideBoth {
+		w.stale++
+	}
+	// Commits of the same date can be walked before a descendant, so ancestors already walked from this commit
+	// are told it is reachable from the other side too
+	if previous != 0 {
+		return w.markWalkedAncestors(commit, side)
+	}
+	return nil
+}
+
+// mark records the sides that reach a commit, counting its queued entries as stale once both do
+func (w *historyWalk) mark(hash plumbing.Hash, sides uint8) {
+	if sides == sideBoth && w.sides[hash] != sideBoth {
+		w.stale += w.queued[hash]
+	}
+	w.sides[hash] = sides
+}
+
+// markWalkedAncestors adds side to the ancestors of commit the walk has already reached
+func (w *historyWalk) markWalkedAncestors(commit *object.Commit, side uint8) error {
+	pending := slices.Clone(commit.ParentHashes)
+	for len(pending) > 0 {
+		hash := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		previous, walked := w.sides[hash]
+		if !walked || previous&side == side {
+			continue
+		}
+		w.mark(hash, previous|side)
+
+		parent, err := w.commit(hash)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, parent.ParentHashes...)
+	}
+	return nil
+}
+
+func (w *historyWalk) commit(hash plumbing.Hash) (*object.Commit, error) {
+	w.loaded++
+	return w.repo.CommitObject(hash)
+}
+
+// commitQueue is a heap of commits, newest committer date first
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int { return len(q) }
+func (q commitQueue) Less(i, j int) bool {
+	return q[i].Committer.When.After(q[j].Committer.When)
+}
+func (q commitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)   { *q = append(*q, x.(*object.Commit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -1,27 +1,100 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"golang.org/x/term"
 	"worktree-manager/internal/executors"
+	"worktree-manager/internal/output"
 )
 
 type GitOperations struct {
 	cmdExecutor executors.CommandExecutor
+	ctx         context.Context
+	backend     GitBackend
+	cli         *cliBackend
 }
 
 func NewGitOperations() *GitOperations {
+	return NewGitOperationsWithExecutor(executors.NewSystemCommandExecutor())
+}
+
+// NewGitOperationsWithExecutor runs git through the given executor, such as a ReplayExecutor in tests
+func NewGitOperationsWithExecutor(executor executors.CommandExecutor) *GitOperations {
+	cli := &cliBackend{executor: executor}
 	return &GitOperations{
-		cmdExecutor: executors.NewSystemCommandExecutor(),
+		cmdExecutor: executor,
+		ctx:         context.Background(),
+		backend:     cli,
+		cli:         cli,
 	}
 }
 
+// WithBackend returns a copy that serves read-only operations from backend, falling back to the CLI where it cannot
+func (g *GitOperations) WithBackend(backend GitBackend) *GitOperations {
+	ops := *g
+	ops.backend = backend
+	return &ops
+}
+
+// WithContext returns a copy whose git commands are cancelled when ctx is done
+func (g *GitOperations) WithContext(ctx context.Context) *GitOperations {
+	ops := *g
+	ops.ctx = ctx
+	return &ops
+}
+
 var defaultGitOps = NewGitOperations()
 
+// SetDefaultExecutor makes the package-level functions run git through executor and returns a function that restores the previous one
+func SetDefaultExecutor(executor executors.CommandExecutor) (restore func()) {
+	previous := defaultGitOps
+	defaultGitOps = NewGitOperationsWithExecutor(executor)
+	return func() { defaultGitOps = previous }
+}
+
+// SetDefaultBackend selects the backend the package-level functions use for read-only operations by name
+func SetDefaultBackend(name string) error {
+	backend, err := NewBackend(name, defaultGitOps.cmdExecutor)
+	if err != nil {
+		return err
+	}
+	defaultGitOps = defaultGitOps.WithBackend(backend)
+	return nil
+}
+
+// BackendName returns the name of the backend read-only operations are served from
+func (g *GitOperations) BackendName() string {
+	return g.backend.Name()
+}
+
+// fallback reports whether an operation the backend could not handle should be retried with the CLI
+func (g *GitOperations) fallback(err error) bool {
+	return errors.Is(err, ErrUnsupported) && g.backend != GitBackend(g.cli)
+}
+
+func (g *GitOperations) execute(execCtx *executors.CommandExecutionContext) (*executors.CommandResult, error) {
+	return g.cmdExecutor.Execute(g.ctx, execCtx)
+}
+
+// run executes a git command whose exit code is all that matters
+func (g *GitOperations) run(execCtx *executors.CommandExecutionContext) error {
+	_, err := g.execute(execCtx)
+	return err
+}
+
+// output executes a git command in dir and returns its trimmed stdout
+func (g *GitOperations) output(dir string, args ...string) (string, error) {
+	return g.cli.output(g.ctx, dir, args...)
+}
+
 func FetchFromOrigin(repoDir string) error {
 	return defaultGitOps.FetchFromOrigin(repoDir)
 }
@@ -32,7 +105,7 @@ func (g *GitOperations) FetchFromOrigin(repoDir string) error {
 		Args:       []string{"fetch", "origin"},
 		WorkingDir: repoDir,
 	}
-	return g.cmdExecutor.Execute(ctx)
+	return g.run(ctx)
 }
 
 func RemoteBranchExists(repoDir, branch string) bool {
@@ -45,7 +118,7 @@ func (g *GitOperations) RemoteBranchExists(repoDir, branch string) bool {
 		Args:       []string{"ls-remote", "--exit-code", "--heads", "origin", branch},
 		WorkingDir: repoDir,
 	}
-	err := g.cmdExecutor.Execute(ctx)
+	err := g.run(ctx)
 	return err == nil
 }
 
@@ -59,7 +132,7 @@ func (g *GitOperations) GetBaseBranch(repoDir string) (string, error) {
 		Args:       []string{"ls-remote", "--exit-code", "--heads", "origin", "main"},
 		WorkingDir: repoDir,
 	}
-	if err := g.cmdExecutor.Execute(mainCtx); err == nil {
+	if err := g.run(mainCtx); err == nil {
 		return "origin/main", nil
 	}
 
@@ -68,13 +141,231 @@ func (g *GitOperations) GetBaseBranch(repoDir string) (string, error) {
 		Args:       []string{"ls-remote", "--exit-code", "--heads", "origin", "master"},
 		WorkingDir: repoDir,
 	}
-	if err := g.cmdExecutor.Execute(masterCtx); err == nil {
+	if err := g.run(masterCtx); err == nil {
 		return "origin/master", nil
 	}
 
 	return "", fmt.Errorf("neither origin/main nor origin/master exists")
 }
 
+func MergeFastForward(worktreePath, target string) error {
+	return defaultGitOps.MergeFastForward(worktreePath, target)
+}
+
+func (g *GitOperations) MergeFastForward(worktreePath, target string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"merge", "--ff-only", target},
+		WorkingDir: worktreePath,
+	}
+	return g.run(ctx)
+}
+
+func Rebase(worktreePath, onto string) error {
+	return defaultGitOps.Rebase(worktreePath, onto)
+}
+
+// Rebase rebases the worktree's branch onto the given ref, aborting the rebase if it fails
+func (g *GitOperations) Rebase(worktreePath, onto string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"rebase", onto},
+		WorkingDir: worktreePath,
+	}
+	if err := g.run(ctx); err != nil {
+		abortCtx := &executors.CommandExecutionContext{
+			Command:    "git",
+			Args:       []string{"rebase", "--abort"},
+			WorkingDir: worktreePath,
+		}
+		if abortErr := g.run(abortCtx); abortErr != nil {
+			return fmt.Errorf("rebase failed: %v (abort also failed: %v)", err, abortErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func IsAncestor(repoDir, ancestor, ref string) bool {
+	return defaultGitOps.IsAncestor(repoDir, ancestor, ref)
+}
+
+func (g *GitOperations) IsAncestor(repoDir, ancestor, ref string) bool {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"merge-base", "--is-ancestor", ancestor, ref},
+		WorkingDir: repoDir,
+	}
+	return g.run(ctx) == nil
+}
+
+func GetRemoteURL(repoDir string) (string, error) {
+	return defaultGitOps.GetRemoteURL(repoDir)
+}
+
+func (g *GitOperations) GetRemoteURL(repoDir string) (string, error) {
+	url, err := g.output(repoDir, "remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("failed to get origin URL: %w", err)
+	}
+
+	return url, nil
+}
+
+// ExcludeFilePath returns the info/exclude file of a repository, which its worktrees share
+func ExcludeFilePath(repoDir string) (string, error) {
+	return defaultGitOps.ExcludeFilePath(repoDir)
+}
+
+func (g *GitOperations) ExcludeFilePath(repoDir string) (string, error) {
+	path, err := g.output(repoDir, "rev-parse", "--git-path", "info/exclude")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate info/exclude: %w", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoDir, path)
+	}
+	return path, nil
+}
+
+func RemoteRefExists(repoDir, ref string) bool {
+	return defaultGitOps.RemoteRefExists(repoDir, ref)
+}
+
+func (g *GitOperations) RemoteRefExists(repoDir, ref string) bool {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"ls-remote", "--exit-code", "origin", ref},
+		WorkingDir: repoDir,
+	}
+	return g.run(ctx) == nil
+}
+
+func FetchRef(repoDir, src, dst string) error {
+	return defaultGitOps.FetchRef(repoDir, src, dst)
+}
+
+// FetchRef force-fetches a single remote ref from origin into a local ref
+func (g *GitOperations) FetchRef(repoDir, src, dst string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"fetch", "origin", fmt.Sprintf("+%s:%s", src, dst)},
+		WorkingDir: repoDir,
+		ShowOutput: true,
+	}
+	return g.run(ctx)
+}
+
+func SetBranchUpstream(repoDir, branch, mergeRef string) error {
+	return defaultGitOps.SetBranchUpstream(repoDir, branch, mergeRef)
+}
+
+// SetBranchUpstream makes a branch pull from an arbitrary ref on origin
+func (g *GitOperations) SetBranchUpstream(repoDir, branch, mergeRef string) error {
+	settings := [][]string{
+		{fmt.Sprintf("branch.%s.remote", branch), "origin"},
+		{fmt.Sprintf("branch.%s.merge", branch), mergeRef},
+	}
+
+	for _, setting := range settings {
+		ctx := &executors.CommandExecutionContext{
+			Command:    "git",
+			Args:       []string{"config", setting[0], setting[1]},
+			WorkingDir: repoDir,
+		}
+		if err := g.run(ctx); err != nil {
+			return fmt.Errorf("failed to set %s: %w", setting[0], err)
+		}
+	}
+	return nil
+}
+
+func GetBranchMergeRef(repoDir, branch string) (string, error) {
+	return defaultGitOps.GetBranchMergeRef(repoDir, branch)
+}
+
+func (g *GitOperations) GetBranchMergeRef(repoDir, branch string) (string, error) {
+	mergeRef, err := g.output(repoDir, "config", "--get", fmt.Sprintf("branch.%s.merge", branch))
+	if err != nil {
+		return "", fmt.Errorf("branch '%s' has no upstream configured", branch)
+	}
+
+	return mergeRef, nil
+}
+
+func ResetHard(worktreePath, ref string) error {
+	return defaultGitOps.ResetHard(worktreePath, ref)
+}
+
+func (g *GitOperations) ResetHard(worktreePath, ref string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"reset", "--hard", ref},
+		WorkingDir: worktreePath,
+	}
+	return g.run(ctx)
+}
+
+func LocalBranchExists(repoDir, branch string) bool {
+	return defaultGitOps.LocalBranchExists(repoDir, branch)
+}
+
+func (g *GitOperations) LocalBranchExists(repoDir, branch string) bool {
+	_, err := g.ResolveRef(repoDir, "refs/heads/"+branch)
+	return err == nil
+}
+
+func ResolveRef(repoDir, ref string) (string, error) {
+	return defaultGitOps.ResolveRef(repoDir, ref)
+}
+
+// ResolveRef returns the commit a ref or revision points to
+func (g *GitOperations) ResolveRef(repoDir, ref string) (string, error) {
+	commit, err := g.backend.ResolveRef(g.ctx, repoDir, ref)
+	if g.fallback(err) {
+		commit, err = g.cli.ResolveRef(g.ctx, repoDir, ref)
+	}
+	return commit, err
+}
+
+func PushBranch(worktreePath, branch string) error {
+	return defaultGitOps.PushBranch(worktreePath, branch)
+}
+
+// PushBranch pushes a branch to origin and sets it as the upstream
+func (g *GitOperations) PushBranch(worktreePath, branch string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"push", "--set-upstream", "origin", branch},
+		WorkingDir: worktreePath,
+		ShowOutput: true,
+	}
+	return g.run(ctx)
+}
+
+func Clone(url, dir string, extraArgs []string) error {
+	return defaultGitOps.Clone(url, dir, extraArgs)
+}
+
+// Clone clones url into dir, passing extraArgs such as --depth to git clone
+func (g *GitOperations) Clone(url, dir string, extraArgs []string) error {
+	args := append([]string{"clone"}, extraArgs...)
+	// git only reports progress to a terminal, and the output reaches it through a pipe
+	if output.Stderr() == os.Stderr && term.IsTerminal(int(os.Stderr.Fd())) {
+		args = append(args, "--progress")
+	}
+	args = append(args, url, dir)
+
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       args,
+		ShowOutput: true,
+		Stdout:     output.Stdout(),
+		Stderr:     output.Stderr(),
+	}
+	return g.run(ctx)
+}
+
 func IsGitRepository(path string) bool {
 	gitDir := filepath.Join(path, ".git")
 	_, err := os.Stat(gitDir)
@@ -90,6 +381,8 @@ func (g *GitOperations) CreateWorktree(repoDir string, opts WorktreeCreateOption
 
 	if opts.CreateBranch {
 		args = append(args, "-b", opts.Branch)
+	} else if opts.Detach {
+		args = append(args, "--detach")
 	}
 
 	args = append(args, opts.WorktreePath)
@@ -104,7 +397,7 @@ func (g *GitOperations) CreateWorktree(repoDir string, opts WorktreeCreateOption
 		WorkingDir: repoDir,
 		ShowOutput: true,
 	}
-	return g.cmdExecutor.Execute(ctx)
+	return g.run(ctx)
 }
 
 func RemoveWorktree(repoDir, worktreePath string) error {
@@ -117,7 +410,36 @@ func (g *GitOperations) RemoveWorktree(repoDir, worktreePath string) error {
 		Args:       []string{"worktree", "remove", "--force", worktreePath},
 		WorkingDir: repoDir,
 	}
-	return g.cmdExecutor.Execute(ctx)
+	return g.run(ctx)
+}
+
+func PruneWorktrees(repoDir string) error {
+	return defaultGitOps.PruneWorktrees(repoDir)
+}
+
+func (g *GitOperations) PruneWorktrees(repoDir string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"worktree", "prune"},
+		WorkingDir: repoDir,
+		ShowOutput: true,
+	}
+	return g.run(ctx)
+}
+
+func RepairWorktrees(repoDir string, worktreePaths []string) error {
+	return defaultGitOps.RepairWorktrees(repoDir, worktreePaths)
+}
+
+// RepairWorktrees re-links a repository and its worktrees in both directions after either has been moved
+func (g *GitOperations) RepairWorktrees(repoDir string, worktreePaths []string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       append([]string{"worktree", "repair"}, worktreePaths...),
+		WorkingDir: repoDir,
+		ShowOutput: true,
+	}
+	return g.run(ctx)
 }
 
 func ListWorktrees(repoDir string) ([]Worktree, error) {
@@ -125,15 +447,11 @@ func ListWorktrees(repoDir string) ([]Worktree, error) {
 }
 
 func (g *GitOperations) ListWorktrees(repoDir string) ([]Worktree, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = repoDir
-
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	worktrees, err := g.backend.ListWorktrees(g.ctx, repoDir)
+	if g.fallback(err) {
+		worktrees, err = g.cli.ListWorktrees(g.ctx, repoDir)
 	}
-
-	return parseWorktreeList(string(output)), nil
+	return worktrees, err
 }
 
 func parseWorktreeList(output string) []Worktree {
@@ -158,9 +476,7 @@ func parseWorktreeList(output string) []Worktree {
 				Path: strings.TrimPrefix(line, "worktree "),
 			}
 		} else if current != nil {
-			if strings.HasPrefix(line, "branch ") {
-				current.Branch = strings.TrimPrefix(line, "branch ")
-			}
+			parseWorktreeAttribute(current, line)
 		}
 	}
 
@@ -170,3 +486,209 @@ func parseWorktreeList(output string) []Worktree {
 
 	return worktrees
 }
+
+func parseWorktreeAttribute(wt *Worktree, line string) {
+	key, value, _ := strings.Cut(line, " ")
+
+	switch key {
+	case "HEAD":
+		wt.Head = value
+	case "branch":
+		wt.Branch = value
+	case "bare":
+		wt.Bare = true
+	case "detached":
+		wt.Detached = true
+	case "locked":
+		wt.Locked = true
+		wt.LockedReason = value
+	case "prunable":
+		wt.Prunable = true
+		wt.PrunableReason = value
+	}
+}
+
+func CommitExists(repoDir, commit string) bool {
+	return defaultGitOps.CommitExists(repoDir, commit)
+}
+
+func (g *GitOperations) CommitExists(repoDir, commit string) bool {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"cat-file", "-e", commit + "^{commit}"},
+		WorkingDir: repoDir,
+	}
+	return g.run(ctx) == nil
+}
+
+func HasUnpushedCommits(worktreePath string) bool {
+	return defaultGitOps.HasUnpushedCommits(worktreePath)
+}
+
+// HasUnpushedCommits reports whether HEAD has commits that no remote-tracking branch contains
+func (g *GitOperations) HasUnpushedCommits(worktreePath string) bool {
+	output, err := g.output(worktreePath, "rev-list", "-n", "1", "HEAD", "--not", "--remotes")
+	return err == nil && output != ""
+}
+
+func CreateBundle(worktreePath, bundlePath string) error {
+	return defaultGitOps.CreateBundle(worktreePath, bundlePath)
+}
+
+// CreateBundle writes the commits of HEAD that are not on any remote to a bundle file
+func (g *GitOperations) CreateBundle(worktreePath, bundlePath string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"bundle", "create", "--quiet", bundlePath, "HEAD", "--not", "--remotes"},
+		WorkingDir: worktreePath,
+		ShowOutput: true,
+	}
+	return g.run(ctx)
+}
+
+func FetchBundle(repoDir, bundlePath string) error {
+	return defaultGitOps.FetchBundle(repoDir, bundlePath)
+}
+
+// FetchBundle fetches the HEAD recorded in a bundle so its commits are available in the repository
+func (g *GitOperations) FetchBundle(repoDir, bundlePath string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"fetch", "--quiet", bundlePath, "HEAD"},
+		WorkingDir: repoDir,
+		ShowOutput: true,
+	}
+	return g.run(ctx)
+}
+
+func DiffWorkingTree(worktreePath string) ([]byte, error) {
+	return defaultGitOps.DiffWorkingTree(worktreePath)
+}
+
+// DiffWorkingTree returns a binary patch of all uncommitted changes, untracked files included, against HEAD.
+// Untracked files are staged in a scratch copy of the index so the worktree's own index is left alone
+func (g *GitOperations) DiffWorkingTree(worktreePath string) ([]byte, error) {
+	index, err := g.output(worktreePath, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the index of %s: %w", worktreePath, err)
+	}
+	if !filepath.IsAbs(index) {
+		index = filepath.Join(worktreePath, index)
+	}
+
+	scratch, err := os.CreateTemp("", "wt-index-")
+	if err != nil {
+		return nil, err
+	}
+	scratch.Close()
+	defer os.Remove(scratch.Name())
+
+	// Without an index yet, git builds a fresh one in place of the empty scratch file
+	data, err := os.ReadFile(index)
+	if err != nil {
+		os.Remove(scratch.Name())
+	} else if err := os.WriteFile(scratch.Name(), data, 0600); err != nil {
+		return nil, err
+	}
+
+	env := append(os.Environ(), "GIT_INDEX_FILE="+scratch.Name())
+
+	addCtx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"add", "--all"},
+		WorkingDir: worktreePath,
+		Env:        env,
+		ShowOutput: true,
+	}
+	if err := g.run(addCtx); err != nil {
+		return nil, fmt.Errorf("failed to stage changes of %s: %w", worktreePath, err)
+	}
+
+	diffCtx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"diff", "--cached", "--binary", "HEAD"},
+		WorkingDir: worktreePath,
+		Env:        env,
+	}
+	result, err := g.execute(diffCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s: %w", worktreePath, err)
+	}
+	return result.Stdout, nil
+}
+
+func ApplyPatch(worktreePath, patchPath string) error {
+	return defaultGitOps.ApplyPatch(worktreePath, patchPath)
+}
+
+// ApplyPatch applies a patch from DiffWorkingTree to the working tree without staging it
+func (g *GitOperations) ApplyPatch(worktreePath, patchPath string) error {
+	ctx := &executors.CommandExecutionContext{
+		Command:    "git",
+		Args:       []string{"apply", "--binary", patchPath},
+		WorkingDir: worktreePath,
+		ShowOutput: true,
+	}
+	return g.run(ctx)
+}
+
+func GetWorktreeStatus(worktreePath string) (WorktreeStatus, error) {
+	return defaultGitOps.GetWorktreeStatus(worktreePath)
+}
+
+func (g *GitOperations) GetWorktreeStatus(worktreePath string) (WorktreeStatus, error) {
+	status, err := g.backend.Status(g.ctx, worktreePath)
+	if g.fallback(err) {
+		status, err = g.cli.Status(g.ctx, worktreePath)
+	}
+	return status, err
+}
+
+func AheadBehind(repoDir, ref, upstream string) (int, int, error) {
+	return defaultGitOps.AheadBehind(repoDir, ref, upstream)
+}
+
+// AheadBehind counts the commits only ref has and the commits only upstream has
+func (g *GitOperations) AheadBehind(repoDir, ref, upstream string) (int, int, error) {
+	ahead, behind, err := g.backend.AheadBehind(g.ctx, repoDir, ref, upstream)
+	if g.fallback(err) {
+		ahead, behind, err = g.cli.AheadBehind(g.ctx, repoDir, ref, upstream)
+	}
+	return ahead, behind, err
+}
+
+func parseStatus(output string) WorktreeStatus {
+	var status WorktreeStatus
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "# ") {
+			status.Dirty = true
+			continue
+		}
+
+		key, value, _ := strings.Cut(strings.TrimPrefix(line, "# "), " ")
+		switch key {
+		case "branch.upstream":
+			status.Upstream = value
+		case "branch.ab":
+			fmt.Sscanf(value, "+%d -%d", &status.Ahead, &status.Behind)
+		}
+	}
+
+	return status
+}
+
+func parseLastCommit(output string) (time.Time, string) {
+	timestamp, subject, _ := strings.Cut(strings.TrimSpace(output), "\x00")
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, subject
+	}
+
+	return time.Unix(seconds, 0), subject
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWorktreeList(t *testing.T) {
+	input := `worktree /repos/app
+HEAD 73f8c5997e70a64edd2d22cf9f3b0c9a969d4383
+branch refs/heads/main
+
+worktree /worktrees/app/feature
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/feature/login
+
+worktree /worktrees/app/detached
+HEAD 2222222222222222222222222222222222222222
+detached
+locked busy rebasing
+
+worktree /worktrees/app/gone
+HEAD 3333333333333333333333333333333333333333
+branch refs/heads/gone
+prunable gitdir file points to non-existent location
+`
+
+	worktrees := parseWorktreeList(input)
+	if len(worktrees) != 4 {
+		t.Fatalf("Expected 4 worktrees, got %d", len(worktrees))
+	}
+
+	feature := worktrees[1]
+	if feature.ShortBranch() != "feature/login" {
+		t.Errorf("Expected short branch 'feature/login', got %q", feature.ShortBranch())
+	}
+	if feature.ShortHead() != "1111111" {
+		t.Errorf("Expected short head '1111111', got %q", feature.ShortHead())
+	}
+
+	detached := worktrees[2]
+	if !detached.Detached || !detached.Locked || detached.LockedReason != "busy rebasing" {
+		t.Errorf("Expected detached locked worktree with reason, got %+v", detached)
+	}
+
+	gone := worktrees[3]
+	if !gone.Prunable || gone.PrunableReason != "gitdir file points to non-existent location" {
+		t.Errorf("Expected prunable worktree with reason, got %+v", gone)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected WorktreeStatus
+	}{
+		{
+			name:     "Clean without upstream",
+			output:   "# branch.oid abc\n# branch.head main\n",
+			expected: WorktreeStatus{},
+		},
+		{
+			name:     "Clean with divergence",
+			output:   "# branch.oid abc\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +2 -3\n",
+			expected: WorktreeStatus{Upstream: "origin/main", Ahead: 2, Behind: 3},
+		},
+		{
+			name:     "Dirty with untracked file",
+			output:   "# branch.oid abc\n# branch.head main\n? notes.txt\n",
+			expected: WorktreeStatus{Dirty: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseStatus(tt.output)
+			if result != tt.expected {
+				t.Errorf("parseStatus() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseLastCommit(t *testing.T) {
+	commitTime, subject := parseLastCommit("1700000000\x00Fix login redirect\n")
+
+	if !commitTime.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Expected commit time %v, got %v", time.Unix(1700000000, 0), commitTime)
+	}
+	if subject != "Fix login redirect" {
+		t.Errorf("Expected subject 'Fix login redirect', got %q", subject)
+	}
+}
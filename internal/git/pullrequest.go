@@ -0,0 +1,61 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PullRequestProvider identifies how a forge exposes pull request heads as refs
+type PullRequestProvider string
+
+const (
+	ProviderGitHub  PullRequestProvider = "github"
+	ProviderGitLab  PullRequestProvider = "gitlab"
+	ProviderUnknown PullRequestProvider = ""
+)
+
+var pullRequestRefPattern = regexp.MustCompile(`^refs/(pull|merge-requests)/(\d+)/head$`)
+
+// DetectPullRequestProvider guesses the forge from the host of a remote URL
+func DetectPullRequestProvider(url string) PullRequestProvider {
+	host := strings.ToLower(ExtractHostFromURL(url))
+
+	switch {
+	case strings.Contains(host, "github"):
+		return ProviderGitHub
+	case strings.Contains(host, "gitlab"):
+		return ProviderGitLab
+	default:
+		return ProviderUnknown
+	}
+}
+
+// PullRequestRef returns the remote ref holding the head of a pull or merge request
+func PullRequestRef(provider PullRequestProvider, number int) string {
+	if provider == ProviderGitLab {
+		return fmt.Sprintf("refs/merge-requests/%d/head", number)
+	}
+	return fmt.Sprintf("refs/pull/%d/head", number)
+}
+
+// PullRequestBranch returns the local branch name used for a pull request worktree
+func PullRequestBranch(number int) string {
+	return fmt.Sprintf("pr/%d", number)
+}
+
+// PullRequestTrackingRef returns the remote-tracking ref a pull request head is fetched into
+func PullRequestTrackingRef(number int) string {
+	return fmt.Sprintf("refs/remotes/origin/pr/%d", number)
+}
+
+// ParsePullRequestRef extracts the pull request number from a pull or merge request head ref
+func ParsePullRequestRef(ref string) (int, bool) {
+	matches := pullRequestRefPattern.FindStringSubmatch(ref)
+	if matches == nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(matches[2])
+	return number, err == nil
+}
@@ -0,0 +1,36 @@
+package git
+
+import "testing"
+
+func TestDetectPullRequestProvider(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected PullRequestProvider
+	}{
+		{url: "https://github.com/user/repo.git", expected: ProviderGitHub},
+		{url: "git@github.com:user/repo.git", expected: ProviderGitHub},
+		{url: "https://gitlab.com/group/repo.git", expected: ProviderGitLab},
+		{url: "git@gitlab.internal.example.com:group/repo.git", expected: ProviderGitLab},
+		{url: "/srv/git/repo.git", expected: ProviderUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if result := DetectPullRequestProvider(tt.url); result != tt.expected {
+				t.Errorf("DetectPullRequestProvider(%q) = %q, want %q", tt.url, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParsePullRequestRef(t *testing.T) {
+	if number, ok := ParsePullRequestRef(PullRequestRef(ProviderGitHub, 42)); !ok || number != 42 {
+		t.Errorf("Expected GitHub ref to parse as 42, got %d (ok: %v)", number, ok)
+	}
+	if number, ok := ParsePullRequestRef(PullRequestRef(ProviderGitLab, 7)); !ok || number != 7 {
+		t.Errorf("Expected GitLab ref to parse as 7, got %d (ok: %v)", number, ok)
+	}
+	if _, ok := ParsePullRequestRef("refs/heads/main"); ok {
+		t.Error("Expected refs/heads/main not to parse as a pull request ref")
+	}
+}
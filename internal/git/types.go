@@ -1,8 +1,43 @@
 package git
 
+import (
+	"strings"
+	"time"
+)
+
 type Worktree struct {
-	Path   string
-	Branch string
+	Path           string
+	Head           string
+	Branch         string
+	Bare           bool
+	Detached       bool
+	Locked         bool
+	LockedReason   string
+	Prunable       bool
+	PrunableReason string
+}
+
+// ShortBranch returns the branch name without the refs/heads/ prefix
+func (w Worktree) ShortBranch() string {
+	return strings.TrimPrefix(w.Branch, "refs/heads/")
+}
+
+// ShortHead returns the abbreviated HEAD commit SHA
+func (w Worktree) ShortHead() string {
+	if len(w.Head) > 7 {
+		return w.Head[:7]
+	}
+	return w.Head
+}
+
+// WorktreeStatus describes the working tree and upstream state of a worktree
+type WorktreeStatus struct {
+	Dirty             bool
+	Upstream          string
+	Ahead             int
+	Behind            int
+	LastCommitTime    time.Time
+	LastCommitSubject string
 }
 
 type WorktreeCreateOptions struct {
@@ -10,4 +45,5 @@ type WorktreeCreateOptions struct {
 	WorktreePath string
 	SourceBranch string
 	CreateBranch bool
+	Detach       bool
 }
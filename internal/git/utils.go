@@ -17,3 +17,42 @@ func ExtractRepoNameFromURL(url string) string {
 
 	return repoName
 }
+
+// ExtractHostFromURL returns the host of an https, ssh:// or scp-style git remote URL, or "" for local paths
+func ExtractHostFromURL(url string) string {
+	if _, rest, found := strings.Cut(url, "://"); found {
+		host, _, _ := strings.Cut(rest, "/")
+		if _, afterUser, hasUser := strings.Cut(host, "@"); hasUser {
+			host = afterUser
+		}
+		host, _, _ = strings.Cut(host, ":")
+		return host
+	}
+
+	// scp-style URLs look like [user@]host:path
+	hostPart, _, found := strings.Cut(url, ":")
+	if !found || strings.Contains(hostPart, "/") {
+		return ""
+	}
+	if _, afterUser, hasUser := strings.Cut(hostPart, "@"); hasUser {
+		hostPart = afterUser
+	}
+	return hostPart
+}
+
+// ExtractRepoPathFromURL returns the repository path of a remote URL, e.g. "owner/repo" or "group/subgroup/repo"
+func ExtractRepoPathFromURL(url string) string {
+	url = strings.TrimSuffix(url, ".git")
+
+	if _, rest, found := strings.Cut(url, "://"); found {
+		_, repoPath, _ := strings.Cut(rest, "/")
+		return strings.Trim(repoPath, "/")
+	}
+
+	if host := ExtractHostFromURL(url); host != "" {
+		_, repoPath, _ := strings.Cut(url, ":")
+		return strings.Trim(repoPath, "/")
+	}
+
+	return ""
+}
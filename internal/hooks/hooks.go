@@ -0,0 +1,120 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/state"
+)
+
+// Names lists the hooks wt runs
+var Names = []string{consts.HookPostWorktreeAdd, consts.HookWorkOn}
+
+// States of a hook script, as shown by 'wt hooks list'
+const (
+	ScriptMissing  = "missing"
+	ScriptEmpty    = "empty"
+	ScriptTemplate = "template"
+	ScriptCustom   = "custom"
+)
+
+// Hook describes a hook as it runs for a repository: its declared commands, then its script
+type Hook struct {
+	Name   string
+	Script string
+	// State is one of the Script* values
+	State string
+	// Templates are the built-in templates the script was generated from, if any
+	Templates []string
+	Commands  []state.HookCommand
+	// Timeout is zero for hooks without one
+	Timeout time.Duration
+}
+
+// ValidateName checks that a hook name is one wt runs
+func ValidateName(hook string) error {
+	if !slices.Contains(Names, hook) {
+		return fmt.Errorf("unknown hook '%s' (available: %s)", hook, strings.Join(Names, ", "))
+	}
+	return nil
+}
+
+// ScriptPath returns the script of a hook; the post-worktree-add script belongs to a repository, the work-on script
+// is shared by all of them
+func ScriptPath(hook, repoAlias string) string {
+	if hook == consts.HookWorkOn {
+		return consts.GetFilePaths().WorkOnScript
+	}
+	return consts.GetFilePaths().PostWorktreeAddScript(repoAlias)
+}
+
+// DefaultScript returns the content a hook's script is created with
+func DefaultScript(hook, repoAlias string) string {
+	if hook == consts.HookWorkOn {
+		return consts.GetWorkOnScriptContent()
+	}
+	return consts.GetPostWorktreeAddScriptContent(repoAlias)
+}
+
+// Inspect returns what a hook runs for a repository
+func Inspect(cfg *config.Config, repo *state.Repo, hook string) (Hook, error) {
+	if err := ValidateName(hook); err != nil {
+		return Hook{}, err
+	}
+
+	info := Hook{
+		Name:     hook,
+		Script:   ScriptPath(hook, repo.Alias),
+		Commands: append(append([]state.HookCommand{}, cfg.HookCommands[hook]...), repo.Hooks[hook]...),
+	}
+	// An invalid timeout is reported by 'wt doctor'; the hook runs with its default
+	info.Timeout, _ = cfg.GetHookTimeout(hook)
+
+	content, err := os.ReadFile(info.Script)
+	switch {
+	case os.IsNotExist(err):
+		info.State = ScriptMissing
+	case err != nil:
+		return Hook{}, fmt.Errorf("failed to read %s: %w", info.Script, err)
+	default:
+		info.State, info.Templates = scriptState(string(content), repo.Alias)
+	}
+	return info, nil
+}
+
+// scriptState tells scripts that do nothing and unchanged template scripts from scripts written by hand
+func scriptState(content, repoAlias string) (string, []string) {
+	names := scriptTemplates(content)
+	switch {
+	case len(names) > 0 && content == RenderPostWorktreeAdd(repoAlias, names):
+		return ScriptTemplate, names
+	case onlyComments(content):
+		return ScriptEmpty, nil
+	}
+	return ScriptCustom, names
+}
+
+func onlyComments(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteScript creates or replaces a hook script
+func WriteScript(path, content string) error {
+	if err := fileops.CreateExecutableScript(path, content); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of a file that already exists
+	return os.Chmod(path, 0755)
+}
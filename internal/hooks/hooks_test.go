@@ -0,0 +1,61 @@
+package hooks
+
+import (
+	"os"
+	"testing"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/state"
+)
+
+func TestInspect_ScriptStates(t *testing.T) {
+	t.Setenv("WT_HOME", t.TempDir())
+	cfg := &config.Config{
+		HookCommands: state.HookCommands{consts.HookPostWorktreeAdd: {{Run: "make deps"}}},
+	}
+	repo := &state.Repo{Alias: "app", Dir: t.TempDir()}
+	scriptPath := ScriptPath(consts.HookPostWorktreeAdd, repo.Alias)
+
+	inspect := func() Hook {
+		t.Helper()
+		hook, err := Inspect(cfg, repo, consts.HookPostWorktreeAdd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hook
+	}
+
+	if hook := inspect(); hook.State != ScriptMissing || len(hook.Commands) != 1 || hook.Timeout != 0 {
+		t.Errorf("Expected a missing script with one command and no timeout, got %+v", hook)
+	}
+
+	steps := []struct {
+		content string
+		state   string
+	}{
+		{DefaultScript(consts.HookPostWorktreeAdd, repo.Alias), ScriptEmpty},
+		{RenderPostWorktreeAdd(repo.Alias, []string{"node"}), ScriptTemplate},
+		{RenderPostWorktreeAdd(repo.Alias, []string{"node"}) + "npm run build\n", ScriptCustom},
+	}
+	for _, step := range steps {
+		if err := WriteScript(scriptPath, step.content); err != nil {
+			t.Fatal(err)
+		}
+		if hook := inspect(); hook.State != step.state {
+			t.Errorf("Expected state %s, got %s for:\n%s", step.state, hook.State, step.content)
+		}
+	}
+
+	info, err := os.Stat(scriptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&0111 == 0 {
+		t.Errorf("Expected the script to be executable, got mode %v", info.Mode())
+	}
+
+	if _, err := Inspect(cfg, repo, "pre-commit"); err == nil {
+		t.Error("Expected an error for an unknown hook, got nil")
+	}
+}
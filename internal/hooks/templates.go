@@ -0,0 +1,223 @@
+package hooks
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"worktree-manager/internal/compose"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+)
+
+// TemplateAuto selects the templates detected from the files in a repository
+const TemplateAuto = "auto"
+
+// templateMarker starts the line recording which templates a post-worktree-add script was generated from
+const templateMarker = "# Templates: "
+
+// Template is a built-in post-worktree-add setup for a kind of project
+type Template struct {
+	Name        string
+	Description string
+	// Markers are files at the root of a repository that suggest the template
+	Markers []string
+	// Body is the setup, run from the root of the new worktree
+	Body string
+}
+
+// templates are in the order their setups run, so compose images are built once the project's own dependencies are installed
+var templates = []Template{
+	{
+		Name:        "node",
+		Description: "Install packages with pnpm, yarn, bun or npm, whichever the lockfile belongs to",
+		Markers:     []string{"package.json"},
+		Body: `if [ -f pnpm-lock.yaml ]; then
+    pnpm install --frozen-lockfile
+elif [ -f yarn.lock ]; then
+    yarn install --frozen-lockfile
+elif [ -f bun.lockb ] || [ -f bun.lock ]; then
+    bun install --frozen-lockfile
+elif [ -f package-lock.json ]; then
+    npm ci
+else
+    npm install
+fi
+`,
+	},
+	{
+		Name:        "go",
+		Description: "Download Go modules",
+		Markers:     []string{"go.mod"},
+		Body: `go mod download
+`,
+	},
+	{
+		Name:        "python",
+		Description: "Sync the environment with uv, or create a .venv with pip",
+		Markers:     []string{"pyproject.toml", "requirements.txt", "uv.lock"},
+		Body: `if command -v uv > /dev/null 2>&1 && [ -f pyproject.toml ]; then
+    uv sync
+else
+    python3 -m venv .venv
+    if [ -f requirements.txt ]; then
+        .venv/bin/pip install -r requirements.txt
+    elif [ -f pyproject.toml ]; then
+        .venv/bin/pip install -e .
+    fi
+fi
+`,
+	},
+	{
+		Name:        "rust",
+		Description: "Fetch crates with cargo",
+		Markers:     []string{"Cargo.toml"},
+		Body: `cargo fetch
+`,
+	},
+	{
+		Name:        "gradle",
+		Description: "Resolve dependencies and compile with the Gradle wrapper, or gradle",
+		Markers:     []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"},
+		Body: `if [ -x ./gradlew ]; then
+    ./gradlew --quiet classes
+else
+    gradle --quiet classes
+fi
+`,
+	},
+	{
+		Name:        "docker-compose",
+		Description: "Pull and build the compose services under a project name of the worktree's own",
+		Markers:     compose.Files,
+		Body: `# A project name per worktree keeps the containers of worktrees apart
+export COMPOSE_PROJECT_NAME="$WT_COMPOSE_PROJECT"
+docker compose pull --quiet --ignore-pull-failures
+docker compose build
+`,
+	},
+}
+
+// templateAliases are other names templates are known by
+var templateAliases = map[string]string{
+	"npm":     "node",
+	"pnpm":    "node",
+	"yarn":    "node",
+	"golang":  "go",
+	"uv":      "python",
+	"cargo":   "rust",
+	"java":    "gradle",
+	"docker":  "docker-compose",
+	"compose": "docker-compose",
+}
+
+// Templates returns the built-in templates
+func Templates() []Template {
+	return slices.Clone(templates)
+}
+
+// FindTemplate returns the template with a name or alias
+func FindTemplate(name string) (Template, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := templateAliases[name]; ok {
+		name = alias
+	}
+	for _, template := range templates {
+		if template.Name == name {
+			return template, true
+		}
+	}
+	return Template{}, false
+}
+
+// Detect returns the names of the templates whose marker files are at the root of dir
+func Detect(dir string) []string {
+	var names []string
+	for _, template := range templates {
+		for _, marker := range template.Markers {
+			if fileops.FileExists(filepath.Join(dir, marker)) {
+				names = append(names, template.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// ResolveTemplates turns a comma separated list of template names into templates in their built-in order. "auto"
+// stands for the templates detected in dir
+func ResolveTemplates(value, dir string) ([]string, error) {
+	var requested []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			continue
+		case strings.EqualFold(name, TemplateAuto):
+			requested = append(requested, Detect(dir)...)
+			continue
+		}
+		template, ok := FindTemplate(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown template '%s' (available: %s)", name, strings.Join(templateNames(), ", "))
+		}
+		requested = append(requested, template.Name)
+	}
+
+	var names []string
+	for _, template := range templates {
+		if slices.Contains(requested, template.Name) {
+			names = append(names, template.Name)
+		}
+	}
+	return names, nil
+}
+
+// ValidateTemplates checks a comma separated list of template names without looking at any repository
+func ValidateTemplates(value string) error {
+	_, err := ResolveTemplates(value, "")
+	return err
+}
+
+func templateNames() []string {
+	names := make([]string, 0, len(templates))
+	for _, template := range templates {
+		names = append(names, template.Name)
+	}
+	return names
+}
+
+// RenderPostWorktreeAdd returns a post-worktree-add script that runs the named templates from the root of the new
+// worktree, or the empty default script without any
+func RenderPostWorktreeAdd(repoAlias string, names []string) string {
+	content := consts.GetPostWorktreeAddScriptContent(repoAlias)
+	if len(names) == 0 {
+		return content
+	}
+
+	var script strings.Builder
+	script.WriteString(content)
+	script.WriteString(templateMarker + strings.Join(names, ", ") + "\n")
+	script.WriteString("\nset -euo pipefail\n")
+	script.WriteString("cd \"$WT_WORKTREE_PATH\"\n")
+	for _, name := range names {
+		template, ok := FindTemplate(name)
+		if !ok {
+			continue
+		}
+		script.WriteString(fmt.Sprintf("\n# %s: %s\n", template.Name, template.Description))
+		script.WriteString(template.Body)
+	}
+	return script.String()
+}
+
+// scriptTemplates returns the templates a post-worktree-add script records it was generated from
+func scriptTemplates(content string) []string {
+	for _, line := range strings.Split(content, "\n") {
+		if list, ok := strings.CutPrefix(line, templateMarker); ok {
+			return strings.Split(list, ", ")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"package.json", "compose.yaml", "go.mod"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{"node", "go", "docker-compose"}
+	if detected := Detect(dir); !reflect.DeepEqual(detected, expected) {
+		t.Errorf("Expected %v, got %v", expected, detected)
+	}
+	if detected := Detect(t.TempDir()); len(detected) != 0 {
+		t.Errorf("Expected nothing detected in an empty directory, got %v", detected)
+	}
+}
+
+func TestResolveTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pyproject.toml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]string{
+		"pnpm":               {"node"},
+		"docker-compose, uv": {"python", "docker-compose"},
+		"auto":               {"python"},
+		"auto,python,java":   {"python", "gradle"},
+		"":                   nil,
+	}
+	for value, expected := range tests {
+		names, err := ResolveTemplates(value, dir)
+		if err != nil {
+			t.Errorf("ResolveTemplates(%q) failed: %v", value, err)
+			continue
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("ResolveTemplates(%q) = %v, want %v", value, names, expected)
+		}
+	}
+
+	if err := ValidateTemplates("node,cobol"); err == nil || !strings.Contains(err.Error(), "cobol") {
+		t.Errorf("Expected an error naming the unknown template, got %v", err)
+	}
+}
+
+func TestRenderPostWorktreeAdd(t *testing.T) {
+	script := RenderPostWorktreeAdd("app", []string{"go", "docker-compose"})
+
+	for _, expected := range []string{"#!/bin/bash\n", "# Templates: go, docker-compose\n", "cd \"$WT_WORKTREE_PATH\"\n", "go mod download\n", "docker compose build\n"} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected the script to contain %q:\n%s", expected, script)
+		}
+	}
+	if names := scriptTemplates(script); !reflect.DeepEqual(names, []string{"go", "docker-compose"}) {
+		t.Errorf("Expected the script to record its templates, got %v", names)
+	}
+
+	if _, err := exec.LookPath("bash"); err == nil {
+		for _, template := range Templates() {
+			cmd := exec.Command("bash", "-n")
+			cmd.Stdin = strings.NewReader(RenderPostWorktreeAdd("app", []string{template.Name}))
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("The %s template is not valid bash: %v\n%s", template.Name, err, out)
+			}
+		}
+	}
+}
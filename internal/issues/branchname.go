@@ -0,0 +1,70 @@
+package issues
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// maxSlugLength keeps branch names derived from long issue titles manageable
+const maxSlugLength = 50
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// BranchNameData is the data available to the branch name template
+type BranchNameData struct {
+	User  string
+	Key   string
+	Title string
+}
+
+// Slug lowercases s and replaces runs of anything but letters and digits with a single dash
+func Slug(s string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// BuildBranchName renders the branch name template for an issue
+func BuildBranchName(branchTemplate string, issue *Issue) (string, error) {
+	tmpl, err := template.New("branch").Funcs(template.FuncMap{
+		"slug":  Slug,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}).Option("missingkey=error").Parse(branchTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid branch template '%s': %w", branchTemplate, err)
+	}
+
+	data := BranchNameData{
+		User:  currentUser(),
+		Key:   issue.Key,
+		Title: issue.Title,
+	}
+
+	var branch strings.Builder
+	if err := tmpl.Execute(&branch, data); err != nil {
+		return "", fmt.Errorf("failed to render branch template: %w", err)
+	}
+
+	name := strings.Trim(branch.String(), "-/")
+	if name == "" || strings.ContainsAny(name, " \t~^:?*[\\") {
+		return "", fmt.Errorf("branch template produced an invalid branch name '%s'", name)
+	}
+	return name, nil
+}
+
+func currentUser() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "user"
+}
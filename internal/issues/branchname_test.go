@@ -0,0 +1,56 @@
+package issues
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Fix login redirect", "fix-login-redirect"},
+		{"  Support --dry-run in CLI!  ", "support-dry-run-in-cli"},
+		{"Ünïcode & symbols", "n-code-symbols"},
+		{"", ""},
+		{"This title is far too long to be used as a branch name without trimming it", "this-title-is-far-too-long-to-be-used-as-a-branch"},
+	}
+
+	for _, tt := range tests {
+		if got := Slug(tt.input); got != tt.expected {
+			t.Errorf("Slug(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestBuildBranchName(t *testing.T) {
+	t.Setenv("USER", "alex")
+	issue := &Issue{Key: "PROJ-123", Title: "Fix login redirect"}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"{{.Key}}-{{.Title | slug}}", "PROJ-123-fix-login-redirect"},
+		{"{{.User}}/{{.Key}}-{{.Title | slug}}", "alex/PROJ-123-fix-login-redirect"},
+		{"feature/{{.Key | lower}}", "feature/proj-123"},
+	}
+
+	for _, tt := range tests {
+		got, err := BuildBranchName(tt.template, issue)
+		if err != nil {
+			t.Fatalf("BuildBranchName(%q) returned error: %v", tt.template, err)
+		}
+		if got != tt.expected {
+			t.Errorf("BuildBranchName(%q) = %q, expected %q", tt.template, got, tt.expected)
+		}
+	}
+}
+
+func TestBuildBranchNameInvalid(t *testing.T) {
+	issue := &Issue{Key: "PROJ-1", Title: "Title"}
+
+	for _, template := range []string{"{{.Key", "{{.Missing}}", "{{.Key}} {{.Title}}", ""} {
+		if _, err := BuildBranchName(template, issue); err == nil {
+			t.Errorf("BuildBranchName(%q) expected an error", template)
+		}
+	}
+}
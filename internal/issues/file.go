@@ -0,0 +1,32 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+
+	"worktree-manager/internal/fileops"
+)
+
+// FileTracker reads issues from a local JSON file mapping keys to issues
+type FileTracker struct {
+	path string
+}
+
+func NewFileTracker(path string) *FileTracker {
+	return &FileTracker{path: fileops.ExpandEnvVars(path)}
+}
+
+func (f *FileTracker) Lookup(ctx context.Context, key string) (*Issue, error) {
+	var issues map[string]Issue
+	if err := fileops.ReadJSONFile(f.path, &issues); err != nil {
+		return nil, err
+	}
+
+	issue, ok := issues[key]
+	if !ok {
+		return nil, fmt.Errorf("issue '%s' not found in %s", key, f.path)
+	}
+
+	issue.Key = key
+	return &issue, nil
+}
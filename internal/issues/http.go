@@ -0,0 +1,86 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPTracker fetches issues as JSON from a URL template containing {key}
+type HTTPTracker struct {
+	urlTemplate string
+	token       string
+	httpClient  *http.Client
+}
+
+func NewHTTPTracker(urlTemplate, token string) *HTTPTracker {
+	return &HTTPTracker{
+		urlTemplate: urlTemplate,
+		token:       token,
+		httpClient:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (h *HTTPTracker) Lookup(ctx context.Context, key string) (*Issue, error) {
+	issueURL := strings.ReplaceAll(h.urlTemplate, "{key}", url.PathEscape(key))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issueURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up issue '%s': %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("looking up issue '%s' returned %s: %s", key, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode issue '%s': %w", key, err)
+	}
+
+	issue := &Issue{
+		Key:   key,
+		Title: firstString(body, "title", "summary", "fields.summary"),
+		URL:   firstString(body, "url", "html_url", "web_url"),
+	}
+	if issue.Title == "" {
+		return nil, fmt.Errorf("issue '%s' response has no title", key)
+	}
+	return issue, nil
+}
+
+// firstString returns the first non-empty string found at the given dotted paths
+func firstString(body map[string]interface{}, paths ...string) string {
+	for _, path := range paths {
+		var value interface{} = body
+		for _, field := range strings.Split(path, ".") {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				value = nil
+				break
+			}
+			value = object[field]
+		}
+
+		if s, ok := value.(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
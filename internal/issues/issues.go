@@ -0,0 +1,48 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+
+	"worktree-manager/internal/config"
+)
+
+// Issue is a ticket from an issue tracker
+type Issue struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
+}
+
+// Tracker looks up issues by key
+type Tracker interface {
+	Lookup(ctx context.Context, key string) (*Issue, error)
+}
+
+// Supported tracker types
+const (
+	TypeFile = "file"
+	TypeHTTP = "http"
+)
+
+// NewTracker creates the tracker described by the issue tracker config
+func NewTracker(cfg *config.IssueTrackerConfig) (Tracker, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no issue tracker configured\n\n💡 Add an 'issue-tracker' section to the config with type '%s' or '%s'", TypeFile, TypeHTTP)
+	}
+
+	switch cfg.Type {
+	case TypeFile:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("issue tracker of type '%s' requires a path", TypeFile)
+		}
+		return NewFileTracker(cfg.Path), nil
+	case TypeHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("issue tracker of type '%s' requires a url", TypeHTTP)
+		}
+		return NewHTTPTracker(cfg.URL, cfg.Token), nil
+	default:
+		return nil, fmt.Errorf("unsupported issue tracker type '%s'", cfg.Type)
+	}
+}
@@ -0,0 +1,84 @@
+package issues
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"worktree-manager/internal/config"
+)
+
+func TestFileTrackerLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.json")
+	content := `{"PROJ-1": {"title": "Add dark mode", "url": "https://example.com/PROJ-1"}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := NewFileTracker(path)
+
+	issue, err := tracker.Lookup(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatalf("Lookup returned error: %v", err)
+	}
+	if issue.Key != "PROJ-1" || issue.Title != "Add dark mode" || issue.URL != "https://example.com/PROJ-1" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+
+	if _, err := tracker.Lookup(context.Background(), "PROJ-2"); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
+
+func TestHTTPTrackerLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/issues/PROJ-7":
+			w.Write([]byte(`{"fields": {"summary": "Speed up search"}, "web_url": "https://tracker/PROJ-7"}`))
+		case "/issues/PROJ-8":
+			w.Write([]byte(`{"id": 8}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tracker := NewHTTPTracker(server.URL+"/issues/{key}", "secret")
+
+	issue, err := tracker.Lookup(context.Background(), "PROJ-7")
+	if err != nil {
+		t.Fatalf("Lookup returned error: %v", err)
+	}
+	if issue.Title != "Speed up search" || issue.URL != "https://tracker/PROJ-7" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+
+	if _, err := tracker.Lookup(context.Background(), "PROJ-8"); err == nil {
+		t.Error("expected an error for a response without a title")
+	}
+	if _, err := tracker.Lookup(context.Background(), "PROJ-9"); err == nil {
+		t.Error("expected an error for a missing issue")
+	}
+}
+
+func TestNewTracker(t *testing.T) {
+	if _, err := NewTracker(nil); err == nil {
+		t.Error("expected an error without an issue tracker config")
+	}
+	if _, err := NewTracker(&config.IssueTrackerConfig{Type: TypeFile}); err == nil {
+		t.Error("expected an error for a file tracker without a path")
+	}
+	if _, err := NewTracker(&config.IssueTrackerConfig{Type: "jira"}); err == nil {
+		t.Error("expected an error for an unsupported type")
+	}
+	if _, err := NewTracker(&config.IssueTrackerConfig{Type: TypeHTTP, URL: "https://tracker/{key}"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
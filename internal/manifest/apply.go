@@ -0,0 +1,333 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// ApplyOptions controls how a workspace file is applied
+type ApplyOptions struct {
+	DryRun bool
+}
+
+// Change records how one part of the workspace file compares to this machine
+type Change struct {
+	Target string
+	Action string
+	Detail string
+}
+
+const (
+	actionInSync   = "in sync"
+	actionClone    = "clone"
+	actionRegister = "register"
+	actionUpdate   = "update"
+	actionDrift    = "drift"
+	actionFailed   = "failed"
+)
+
+// actionResults maps an action to how it is reported once done and in a dry run
+var actionResults = map[string][2]string{
+	actionInSync:   {"in sync", "in sync"},
+	actionClone:    {"cloned", "would clone"},
+	actionRegister: {"registered", "would register"},
+	actionUpdate:   {"updated", "would update"},
+	actionDrift:    {"drift", "drift"},
+	actionFailed:   {"failed", "failed"},
+}
+
+type applier struct {
+	cfg      *config.Config
+	appState *state.State
+	dryRun   bool
+	changes  []Change
+}
+
+// Apply brings config, scripts and repositories in line with the workspace file. Applying the same file twice changes nothing;
+// differences it will not fix on its own, such as a repository cloned from another URL, are reported as drift
+func Apply(cfg *config.Config, appState *state.State, m *Manifest, opts ApplyOptions) ([]Change, error) {
+	a := &applier{cfg: cfg, appState: appState, dryRun: opts.DryRun}
+
+	a.applyConfig(m.Config)
+	if m.Hooks != nil {
+		a.applyScript("hook work-on", consts.GetFilePaths().WorkOnScript, m.Hooks.WorkOn)
+	}
+	for _, spec := range m.Repos {
+		a.applyRepo(spec)
+	}
+	a.reportUndeclaredRepos(m)
+	a.applyActiveRepo(m.ActiveRepo)
+
+	PrintChanges(a.changes, opts.DryRun)
+
+	failed := 0
+	for _, change := range a.changes {
+		if change.Action == actionFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return a.changes, fmt.Errorf("%d change(s) could not be applied", failed)
+	}
+	return a.changes, nil
+}
+
+func (a *applier) record(target, action, detail string) {
+	a.changes = append(a.changes, Change{Target: target, Action: action, Detail: detail})
+}
+
+func (a *applier) applyConfig(values map[string]any) {
+	if len(values) == 0 {
+		return
+	}
+
+	merged, changed, err := mergeConfig(a.cfg, values)
+	if err != nil {
+		a.record("config", actionFailed, err.Error())
+		return
+	}
+	if len(changed) == 0 {
+		a.record("config", actionInSync, "")
+		return
+	}
+
+	if !a.dryRun {
+		*a.cfg = *merged
+		if err := a.cfg.Save(); err != nil {
+			a.record("config", actionFailed, fmt.Sprintf("failed to save config: %v", err))
+			return
+		}
+	}
+	a.record("config", actionUpdate, "set "+strings.Join(changed, ", "))
+}
+
+// applyScript writes a script whose content differs from the workspace file; empty content leaves the script unmanaged
+func (a *applier) applyScript(target, path, content string) {
+	if content == "" {
+		return
+	}
+
+	existing, err := os.ReadFile(path)
+	if err == nil && string(existing) == content {
+		a.record(target, actionInSync, "")
+		return
+	}
+
+	detail := "content differs"
+	if err != nil {
+		detail = "create " + path
+	}
+
+	if !a.dryRun {
+		if err := fileops.CreateExecutableScript(path, content); err != nil {
+			a.record(target, actionFailed, err.Error())
+			return
+		}
+	}
+	a.record(target, actionUpdate, detail)
+}
+
+func (a *applier) applyRepo(spec RepoSpec) {
+	target := "repo " + spec.Alias
+	before := len(a.changes)
+
+	dir := filepath.Join(consts.GetDirectoryPaths().DefaultGitReposDir, spec.Alias)
+	if spec.Dir != "" {
+		dir = fileops.ExpandEnvVars(spec.Dir)
+	}
+
+	existing, err := a.appState.FindRepoByAlias(spec.Alias)
+	registered := err == nil
+	if registered {
+		if spec.Dir != "" && filepath.Clean(existing.Dir) != filepath.Clean(dir) {
+			a.record(target, actionDrift, fmt.Sprintf("registered at %s, the workspace file says %s", existing.Dir, dir))
+		}
+		dir = existing.Dir
+	}
+
+	switch {
+	case !fileops.FileExists(dir):
+		if !a.cloneRepo(target, spec, dir) {
+			return
+		}
+	case !git.IsGitRepository(dir):
+		a.record(target, actionFailed, fmt.Sprintf("%s exists but is not a git repository", dir))
+		return
+	default:
+		a.checkRemote(target, spec, dir)
+		if !registered {
+			a.record(target, actionRegister, fmt.Sprintf("existing clone at %s", dir))
+		}
+	}
+
+	if registered {
+		a.updateRepoSettings(target, spec)
+	} else if !a.dryRun {
+		repo := state.Repo{Alias: spec.Alias, Dir: dir, BaseBranch: spec.BaseBranch, Files: spec.Files, Hooks: spec.HookCommands, Ports: spec.Ports, Env: spec.Env}
+		if err := a.appState.AddRepo(repo); err != nil {
+			a.record(target, actionFailed, fmt.Sprintf("failed to add repository to state: %v", err))
+			return
+		}
+	}
+
+	if len(a.changes) == before {
+		a.record(target, actionInSync, "")
+	}
+
+	if spec.Hooks != nil {
+		a.applyScript(fmt.Sprintf("hook %s/post-worktree-add", spec.Alias), consts.GetFilePaths().PostWorktreeAddScript(spec.Alias), spec.Hooks.PostWorktreeAdd)
+	}
+}
+
+// cloneRepo clones a missing repository and reports whether the rest of its spec can be applied
+func (a *applier) cloneRepo(target string, spec RepoSpec, dir string) bool {
+	if spec.URL == "" {
+		a.record(target, actionFailed, fmt.Sprintf("%s does not exist and no url is given to clone it from", dir))
+		return false
+	}
+
+	if !a.dryRun {
+		if err := fileops.EnsureDir(filepath.Dir(dir)); err != nil {
+			a.record(target, actionFailed, err.Error())
+			return false
+		}
+		output.Progress("Cloning %s into %s...", spec.URL, dir)
+		if err := git.Clone(spec.URL, dir, spec.CloneArgs); err != nil {
+			a.record(target, actionFailed, fmt.Sprintf("failed to clone %s: %v", spec.URL, err))
+			return false
+		}
+	}
+	a.record(target, actionClone, fmt.Sprintf("%s into %s", spec.URL, dir))
+	return true
+}
+
+func (a *applier) checkRemote(target string, spec RepoSpec, dir string) {
+	if spec.URL == "" {
+		return
+	}
+	url, err := git.GetRemoteURL(dir)
+	if err != nil {
+		a.record(target, actionDrift, fmt.Sprintf("no origin remote, the workspace file says %s", spec.URL))
+		return
+	}
+	if url != spec.URL {
+		a.record(target, actionDrift, fmt.Sprintf("origin is %s, the workspace file says %s", url, spec.URL))
+	}
+}
+
+// updateRepoSettings aligns the state of a registered repository; unset fields in the spec leave the state alone
+func (a *applier) updateRepoSettings(target string, spec RepoSpec) {
+	i := slices.IndexFunc(a.appState.Repos, func(r state.Repo) bool { return r.Alias == spec.Alias })
+	repo := &a.appState.Repos[i]
+
+	var changed []string
+	if spec.BaseBranch != "" && repo.BaseBranch != spec.BaseBranch {
+		changed = append(changed, "base-branch")
+	}
+	if spec.Files != nil && !sameValue(repo.Files, spec.Files) {
+		changed = append(changed, "files")
+	}
+	if spec.HookCommands != nil && !sameValue(repo.Hooks, spec.HookCommands) {
+		changed = append(changed, "hook-commands")
+	}
+	if spec.Ports != nil && !sameValue(repo.Ports, spec.Ports) {
+		changed = append(changed, "ports")
+	}
+	if spec.Env != nil && !sameValue(repo.Env, spec.Env) {
+		changed = append(changed, "env")
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	if !a.dryRun {
+		if spec.BaseBranch != "" {
+			repo.BaseBranch = spec.BaseBranch
+		}
+		if spec.Files != nil {
+			repo.Files = spec.Files
+		}
+		if spec.HookCommands != nil {
+			repo.Hooks = spec.HookCommands
+		}
+		if spec.Ports != nil {
+			repo.Ports = spec.Ports
+		}
+		if spec.Env != nil {
+			repo.Env = spec.Env
+		}
+		if err := a.appState.Save(); err != nil {
+			a.record(target, actionFailed, fmt.Sprintf("failed to save state: %v", err))
+			return
+		}
+	}
+	a.record(target, actionUpdate, "set "+strings.Join(changed, ", "))
+}
+
+func (a *applier) reportUndeclaredRepos(m *Manifest) {
+	for _, repo := range a.appState.Repos {
+		declared := slices.ContainsFunc(m.Repos, func(spec RepoSpec) bool { return spec.Alias == repo.Alias })
+		if !declared {
+			a.record("repo "+repo.Alias, actionDrift, "not declared in the workspace file")
+		}
+	}
+}
+
+func (a *applier) applyActiveRepo(alias string) {
+	if alias == "" || a.appState.ActiveRepo == alias {
+		return
+	}
+
+	if !a.dryRun {
+		if err := a.appState.SetActiveRepo(alias); err != nil {
+			a.record("active repo", actionFailed, err.Error())
+			return
+		}
+	}
+	a.record("active repo", actionUpdate, alias)
+}
+
+// PrintChanges displays the outcome of applying a workspace file
+func PrintChanges(changes []Change, dryRun bool) {
+	if len(changes) == 0 {
+		output.Hint("The workspace file declares nothing to apply")
+		return
+	}
+
+	rows := make([][]string, 0, len(changes))
+	pending, drift := 0, 0
+	for _, change := range changes {
+		result := actionResults[change.Action][0]
+		if dryRun {
+			result = actionResults[change.Action][1]
+		}
+		rows = append(rows, []string{change.Target, result, change.Detail})
+
+		switch change.Action {
+		case actionClone, actionRegister, actionUpdate:
+			pending++
+		case actionDrift:
+			drift++
+		}
+	}
+
+	output.Info("Apply summary:")
+	output.Table([]string{"TARGET", "RESULT", "DETAIL"}, rows)
+
+	if dryRun && pending > 0 {
+		output.Hint("Run without --dry-run to apply %d change(s)", pending)
+	}
+	if drift > 0 {
+		output.Warning("%d difference(s) are not fixed by apply and need attention", drift)
+	}
+}
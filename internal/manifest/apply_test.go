@@ -0,0 +1,188 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/testutil"
+)
+
+// setupHome points HOME at an empty worktree-manager directory and returns the URL of a bare origin with one commit
+func setupHome(t *testing.T) string {
+	t.Helper()
+	testutil.SetupHome(t)
+	if err := fileops.EnsureDir(consts.GetDirectoryPaths().WorktreeManagerDir); err != nil {
+		t.Fatal(err)
+	}
+
+	origin, _ := testutil.NewOriginRepo(t)
+	return origin
+}
+
+func testManifest(url string) *Manifest {
+	return &Manifest{
+		Version:    Version,
+		Config:     map[string]any{"picker": "fzf"},
+		Hooks:      &GlobalHooks{WorkOn: "#!/bin/bash\necho work\n"},
+		ActiveRepo: "app",
+		Repos: []RepoSpec{{
+			Alias:      "app",
+			URL:        url,
+			BaseBranch: "main",
+			CloneArgs:  []string{"--depth", "1"},
+			Files:      &state.FileRules{Copy: []string{".env"}},
+			Hooks:      &RepoHooks{PostWorktreeAdd: "#!/bin/bash\nnpm install\n"},
+		}},
+	}
+}
+
+func actions(changes []Change) map[string]string {
+	result := make(map[string]string)
+	for _, change := range changes {
+		result[change.Target] = change.Action
+	}
+	return result
+}
+
+func TestApply_BootstrapsAndIsIdempotent(t *testing.T) {
+	url := setupHome(t)
+	cfg := &config.Config{Picker: "builtin"}
+	appState := &state.State{}
+	m := testManifest(url)
+
+	changes, err := Apply(cfg, appState, m, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"config":                     actionUpdate,
+		"hook work-on":               actionUpdate,
+		"repo app":                   actionClone,
+		"hook app/post-worktree-add": actionUpdate,
+		"active repo":                actionUpdate,
+	}
+	if got := actions(changes); !sameValue(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	repoDir := filepath.Join(consts.GetDirectoryPaths().DefaultGitReposDir, "app")
+	if !fileops.FileExists(filepath.Join(repoDir, "README")) {
+		t.Errorf("Expected the repository to be cloned to %s", repoDir)
+	}
+	repo, err := appState.FindRepoByAlias("app")
+	if err != nil || repo.BaseBranch != "main" || repo.Files.Copy[0] != ".env" {
+		t.Errorf("Expected the repository in the state with its settings, got %+v (err: %v)", repo, err)
+	}
+	if appState.ActiveRepo != "app" || cfg.Picker != "fzf" {
+		t.Errorf("Expected active repo app and picker fzf, got %q and %q", appState.ActiveRepo, cfg.Picker)
+	}
+	if data, _ := os.ReadFile(consts.GetFilePaths().PostWorktreeAddScript("app")); string(data) != m.Repos[0].Hooks.PostWorktreeAdd {
+		t.Errorf("Expected the post-worktree-add hook to be written, got %q", data)
+	}
+
+	// The saved files reflect the changes too
+	var saved config.Config
+	if err := fileops.ReadJSONFile(consts.GetFilePaths().Config, &saved); err != nil || saved.Picker != "fzf" {
+		t.Errorf("Expected the config to be saved, got %+v (err: %v)", saved, err)
+	}
+
+	changes, err = Apply(cfg, appState, m, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("Second apply failed: %v", err)
+	}
+	for _, change := range changes {
+		if change.Action != actionInSync {
+			t.Errorf("Expected everything in sync on the second apply, got %+v", change)
+		}
+	}
+}
+
+func TestApply_DryRunChangesNothing(t *testing.T) {
+	url := setupHome(t)
+	cfg := &config.Config{}
+	appState := &state.State{}
+
+	changes, err := Apply(cfg, appState, testManifest(url), ApplyOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if actions(changes)["repo app"] != actionClone {
+		t.Errorf("Expected a pending clone, got %v", actions(changes))
+	}
+
+	if len(appState.Repos) != 0 || appState.ActiveRepo != "" || cfg.Picker != "" {
+		t.Errorf("Expected no changes in memory, got %+v and %+v", appState, cfg)
+	}
+	for _, path := range []string{
+		consts.GetDirectoryPaths().DefaultGitReposDir,
+		consts.GetFilePaths().WorkOnScript,
+		consts.GetFilePaths().Config,
+	} {
+		if fileops.FileExists(path) {
+			t.Errorf("Expected %s not to be written in a dry run", path)
+		}
+	}
+}
+
+func TestApply_RegistersExistingCloneAndReportsDrift(t *testing.T) {
+	url := setupHome(t)
+	repoDir := filepath.Join(t.TempDir(), "app")
+	testutil.RunGit(t, "", "clone", "--quiet", url, repoDir)
+
+	appState := &state.State{Repos: []state.Repo{{Alias: "old", Dir: t.TempDir()}}}
+	m := &Manifest{Repos: []RepoSpec{
+		{Alias: "app", URL: url, Dir: repoDir},
+		{Alias: "other", URL: "git@example.com:acme/other.git", Dir: repoDir},
+	}}
+
+	changes, err := Apply(&config.Config{}, appState, m, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	got := actions(changes)
+	if got["repo app"] != actionRegister {
+		t.Errorf("Expected the existing clone to be registered, got %v", got)
+	}
+	// Registering the same directory under a second alias shows the origin mismatch
+	if got["repo other"] != actionRegister {
+		t.Errorf("Expected other to be registered, got %v", got)
+	}
+	if got["repo old"] != actionDrift {
+		t.Errorf("Expected the undeclared repository to be reported as drift, got %v", got)
+	}
+
+	var originDrift bool
+	for _, change := range changes {
+		if change.Target == "repo other" && change.Action == actionDrift && strings.Contains(change.Detail, "origin is") {
+			originDrift = true
+		}
+	}
+	if !originDrift {
+		t.Errorf("Expected origin drift for other, got %+v", changes)
+	}
+
+	if _, err := appState.FindRepoByAlias("app"); err != nil {
+		t.Errorf("Expected app to be registered: %v", err)
+	}
+}
+
+func TestApply_FailsWithoutURL(t *testing.T) {
+	setupHome(t)
+	m := &Manifest{Repos: []RepoSpec{{Alias: "app"}}}
+
+	changes, err := Apply(&config.Config{}, &state.State{}, m, ApplyOptions{})
+	if err == nil {
+		t.Fatal("Expected an error for a missing repository without a url")
+	}
+	if got := actions(changes); got["repo app"] != actionFailed {
+		t.Errorf("Expected app to fail, got %v", got)
+	}
+}
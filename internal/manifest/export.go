@@ -0,0 +1,92 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/state"
+)
+
+// ExportOptions controls what 'wt export' writes
+type ExportOptions struct {
+	IncludeSecrets bool
+}
+
+// Export describes the current config, scripts and repositories as a workspace file
+func Export(cfg *config.Config, appState *state.State, opts ExportOptions) (*Manifest, error) {
+	exported := *cfg
+	if !opts.IncludeSecrets {
+		stripSecrets(&exported)
+	}
+
+	values, err := configValues(&exported)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{
+		Version:    Version,
+		Config:     values,
+		ActiveRepo: appState.ActiveRepo,
+	}
+
+	if workOn := readCustomScript(consts.GetFilePaths().WorkOnScript, consts.GetWorkOnScriptContent()); workOn != "" {
+		m.Hooks = &GlobalHooks{WorkOn: workOn}
+	}
+
+	for _, repo := range appState.Repos {
+		spec := RepoSpec{
+			Alias:        repo.Alias,
+			BaseBranch:   repo.BaseBranch,
+			Files:        repo.Files,
+			HookCommands: repo.Hooks,
+			Ports:        repo.Ports,
+			Env:          repo.Env,
+		}
+		if url, err := git.GetRemoteURL(repo.Dir); err == nil {
+			spec.URL = url
+		}
+		if filepath.Clean(repo.Dir) != filepath.Join(consts.GetDirectoryPaths().DefaultGitReposDir, repo.Alias) {
+			spec.Dir = fileops.PortablePath(repo.Dir)
+		}
+
+		postAdd := consts.GetFilePaths().PostWorktreeAddScript(repo.Alias)
+		if content := readCustomScript(postAdd, consts.GetPostWorktreeAddScriptContent(repo.Alias)); content != "" {
+			spec.Hooks = &RepoHooks{PostWorktreeAdd: content}
+		}
+
+		m.Repos = append(m.Repos, spec)
+	}
+
+	return m, nil
+}
+
+// stripSecrets blanks API tokens so the workspace file can be shared
+func stripSecrets(cfg *config.Config) {
+	if len(cfg.Forges) > 0 {
+		forges := make([]config.ForgeConfig, len(cfg.Forges))
+		for i, forge := range cfg.Forges {
+			forge.Token = ""
+			forges[i] = forge
+		}
+		cfg.Forges = forges
+	}
+	if cfg.IssueTracker != nil {
+		tracker := *cfg.IssueTracker
+		tracker.Token = ""
+		cfg.IssueTracker = &tracker
+	}
+}
+
+// readCustomScript returns a script's content, or "" when it is missing or still the generated template
+func readCustomScript(path, template string) string {
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) == template {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,86 @@
+package manifest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/state"
+)
+
+func TestExport_RoundTripsThroughApply(t *testing.T) {
+	url := setupHome(t)
+	cfg := &config.Config{
+		Picker: "fzf",
+		Forges: []config.ForgeConfig{{Host: "github.com", Type: "github", Token: "secret"}},
+	}
+	appState := &state.State{}
+	if _, err := Apply(cfg, appState, testManifest(url), ApplyOptions{}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	m, err := Export(cfg, appState, ExportOptions{})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if len(m.Repos) != 1 {
+		t.Fatalf("Expected one repository, got %+v", m.Repos)
+	}
+	spec := m.Repos[0]
+	if spec.URL != url || spec.Dir != "" || spec.BaseBranch != "main" || spec.Hooks == nil {
+		t.Errorf("Unexpected repository spec: %+v", spec)
+	}
+	if m.Hooks == nil || m.ActiveRepo != "app" {
+		t.Errorf("Expected the work-on hook and active repo, got %+v", m)
+	}
+	forges := m.Config["forges"].([]any)
+	if token := forges[0].(map[string]any)["token"]; token != nil {
+		t.Errorf("Expected tokens to be left out, got %v", token)
+	}
+	if cfg.Forges[0].Token != "secret" {
+		t.Error("Expected the exported config not to change the loaded one")
+	}
+
+	// Applying the export to the same machine finds nothing to do
+	data, err := m.Marshal("workspace.yaml")
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v\n%s", err, data)
+	}
+
+	// The token left out of the export is kept rather than cleared
+	changes, err := Apply(cfg, appState, parsed, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	for _, change := range changes {
+		if change.Action != actionInSync {
+			t.Errorf("Expected the export to match the machine, got %+v", change)
+		}
+	}
+	if cfg.Forges[0].Token != "secret" {
+		t.Errorf("Expected the forge token to be kept, got %q", cfg.Forges[0].Token)
+	}
+}
+
+func TestExport_SkipsTemplateScriptsAndPortableDirs(t *testing.T) {
+	setupHome(t)
+	home := filepath.Dir(consts.GetDirectoryPaths().WorktreeManagerDir)
+	appState := &state.State{Repos: []state.Repo{{Alias: "app", Dir: filepath.Join(home, "src", "app")}}}
+
+	m, err := Export(&config.Config{}, appState, ExportOptions{})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if m.Hooks != nil || m.Repos[0].Hooks != nil {
+		t.Errorf("Expected no hooks without scripts, got %+v", m)
+	}
+	if m.Repos[0].Dir != filepath.Join("$HOME", "src", "app") {
+		t.Errorf("Expected the directory relative to $HOME, got %s", m.Repos[0].Dir)
+	}
+}
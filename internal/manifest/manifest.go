@@ -0,0 +1,252 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/state"
+)
+
+// Version is the workspace file format written by 'wt export' and understood by 'wt apply'
+const Version = 1
+
+// Manifest declares a machine's repositories, hooks and config so it can be bootstrapped with 'wt apply'
+type Manifest struct {
+	Version    int            `json:"version"`
+	Config     map[string]any `json:"config,omitempty"`
+	Hooks      *GlobalHooks   `json:"hooks,omitempty"`
+	ActiveRepo string         `json:"active-repo,omitempty"`
+	Repos      []RepoSpec     `json:"repos,omitempty"`
+}
+
+// GlobalHooks holds the content of scripts shared by all repositories
+type GlobalHooks struct {
+	WorkOn string `json:"work-on,omitempty"`
+}
+
+// RepoSpec declares one repository and where it is cloned from
+type RepoSpec struct {
+	Alias        string             `json:"alias,omitempty"`
+	URL          string             `json:"url,omitempty"`
+	Dir          string             `json:"dir,omitempty"`
+	BaseBranch   string             `json:"base-branch,omitempty"`
+	CloneArgs    []string           `json:"clone-args,omitempty"`
+	Files        *state.FileRules   `json:"files,omitempty"`
+	Hooks        *RepoHooks         `json:"hooks,omitempty"`
+	HookCommands state.HookCommands `json:"hook-commands,omitempty"`
+	Ports        state.PortSlots    `json:"ports,omitempty"`
+	Env          state.EnvTemplates `json:"env,omitempty"`
+}
+
+// RepoHooks holds the content of a repository's scripts
+type RepoHooks struct {
+	PostWorktreeAdd string `json:"post-worktree-add,omitempty"`
+}
+
+// Load reads a workspace file, in YAML or JSON, and validates it
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace file %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Parse decodes a workspace file. YAML is converted to JSON first so both formats share the kebab-case json tags
+func Parse(data []byte) (*Manifest, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("the file is empty")
+	}
+
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (m *Manifest) validate() error {
+	if m.Version > Version {
+		return fmt.Errorf("version %d is newer than this wt supports (%d)", m.Version, Version)
+	}
+
+	if _, _, err := mergeConfig(&config.Config{}, m.Config); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for i := range m.Repos {
+		spec := &m.Repos[i]
+		if spec.Alias == "" {
+			if spec.URL == "" {
+				return fmt.Errorf("repos[%d] needs an alias or a url", i)
+			}
+			spec.Alias = git.ExtractRepoNameFromURL(spec.URL)
+		}
+		if seen[spec.Alias] {
+			return fmt.Errorf("repository alias '%s' is declared more than once", spec.Alias)
+		}
+		if err := spec.HookCommands.Validate(); err != nil {
+			return fmt.Errorf("repository '%s': %w", spec.Alias, err)
+		}
+		if err := spec.Ports.Validate(); err != nil {
+			return fmt.Errorf("repository '%s': %w", spec.Alias, err)
+		}
+		if err := spec.Env.Validate(); err != nil {
+			return fmt.Errorf("repository '%s': %w", spec.Alias, err)
+		}
+		seen[spec.Alias] = true
+	}
+
+	if m.ActiveRepo != "" && !seen[m.ActiveRepo] {
+		return fmt.Errorf("active-repo '%s' is not one of the declared repos", m.ActiveRepo)
+	}
+	return nil
+}
+
+// Marshal encodes the manifest as JSON for a .json path and as YAML otherwise
+func (m *Manifest) Marshal(path string) ([]byte, error) {
+	jsonData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return append(jsonData, '\n'), nil
+	}
+
+	// Decoding the JSON into a node keeps the field order of the structs
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonData, &node); err != nil {
+		return nil, err
+	}
+	plainStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// plainStyle drops the JSON flow style and quoting, and writes multi-line scripts as literal blocks
+func plainStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && strings.Contains(node.Value, "\n") {
+		node.Style = yaml.LiteralStyle
+	}
+	for _, child := range node.Content {
+		plainStyle(child)
+	}
+}
+
+// mergeConfig overlays values, keyed like config.json, onto a copy of cfg and reports which keys changed.
+// Tokens left out of the values, as 'wt export' does, keep their current value
+func mergeConfig(cfg *config.Config, values map[string]any) (*config.Config, []string, error) {
+	before, err := configValues(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overlaid := make(map[string]any, len(before))
+	for key, value := range before {
+		overlaid[key] = value
+	}
+	for key, value := range values {
+		overlaid[key] = value
+	}
+
+	data, err := json.Marshal(overlaid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var merged config.Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&merged); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	keepSecrets(&merged, cfg)
+
+	after, err := configValues(&merged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changed []string
+	for key := range values {
+		if !sameValue(before[key], after[key]) {
+			changed = append(changed, key)
+		}
+	}
+	slices.Sort(changed)
+	return &merged, changed, nil
+}
+
+// keepSecrets copies tokens from current into merged where merged has none for the same forge host or issue tracker
+func keepSecrets(merged, current *config.Config) {
+	for i := range merged.Forges {
+		if merged.Forges[i].Token != "" {
+			continue
+		}
+		for _, forge := range current.Forges {
+			if forge.Host == merged.Forges[i].Host {
+				merged.Forges[i].Token = forge.Token
+			}
+		}
+	}
+
+	if merged.IssueTracker != nil && merged.IssueTracker.Token == "" && current.IssueTracker != nil && current.IssueTracker.Type == merged.IssueTracker.Type {
+		merged.IssueTracker.Token = current.IssueTracker.Token
+	}
+}
+
+// configValues returns cfg as it would appear in config.json
+func configValues(cfg *config.Config) (map[string]any, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any)
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// sameValue compares two decoded JSON values, normalising numbers and nested types through a JSON round trip
+func sameValue(a, b any) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
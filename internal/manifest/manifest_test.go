@@ -0,0 +1,130 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/state"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(`
+version: 1
+config:
+  picker: fzf
+  automatic-work-on-after-add: false
+hooks:
+  work-on: |
+    #!/bin/bash
+    code "$WT_WORKTREE_PATH"
+active-repo: api
+repos:
+  - url: git@github.com:acme/api.git
+    base-branch: develop
+    clone-args: [--depth, "1"]
+    files:
+      copy: [.env]
+    hooks:
+      post-worktree-add: |
+        npm install
+  - alias: web
+    url: https://github.com/acme/web.git
+    dir: $HOME/src/web
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(m.Repos) != 2 || m.Repos[0].Alias != "api" || m.Repos[1].Alias != "web" {
+		t.Fatalf("Expected repos api and web, got %+v", m.Repos)
+	}
+	api := m.Repos[0]
+	if api.BaseBranch != "develop" || strings.Join(api.CloneArgs, " ") != "--depth 1" || api.Files.Copy[0] != ".env" {
+		t.Errorf("Unexpected api spec: %+v", api)
+	}
+	if api.Hooks.PostWorktreeAdd != "npm install\n" {
+		t.Errorf("Unexpected post-worktree-add hook: %q", api.Hooks.PostWorktreeAdd)
+	}
+	if m.Hooks.WorkOn != "#!/bin/bash\ncode \"$WT_WORKTREE_PATH\"\n" {
+		t.Errorf("Unexpected work-on hook: %q", m.Hooks.WorkOn)
+	}
+	if m.Config["picker"] != "fzf" || m.Config["automatic-work-on-after-add"] != false {
+		t.Errorf("Unexpected config: %v", m.Config)
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	m, err := Parse([]byte(`{"version": 1, "repos": [{"alias": "api", "dir": "/src/api"}]}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(m.Repos) != 1 || m.Repos[0].Dir != "/src/api" {
+		t.Errorf("Unexpected repos: %+v", m.Repos)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"empty", "", "empty"},
+		{"unknown field", "repos:\n  - alias: api\n    branch: main\n", "unknown field \"branch\""},
+		{"unknown config key", "config:\n  colour: blue\n", "unknown field \"colour\""},
+		{"wrong config type", "config:\n  picker: [fzf]\n", "invalid config"},
+		{"missing alias and url", "repos:\n  - dir: /src/api\n", "needs an alias or a url"},
+		{"duplicate alias", "repos:\n  - url: git@x:a/api.git\n  - alias: api\n", "more than once"},
+		{"unknown active repo", "active-repo: web\nrepos:\n  - alias: api\n", "not one of the declared repos"},
+		{"newer version", "version: 2\n", "newer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	m := &Manifest{
+		Version:    Version,
+		Config:     map[string]any{"picker": "fzf", "automatic-work-on-after-add": true},
+		Hooks:      &GlobalHooks{WorkOn: "#!/bin/bash\necho work\n"},
+		ActiveRepo: "api",
+		Repos: []RepoSpec{{
+			Alias:      "api",
+			URL:        "git@github.com:acme/api.git",
+			BaseBranch: "develop",
+			Files:      &state.FileRules{Symlink: []string{"node_modules"}},
+			Hooks:      &RepoHooks{PostWorktreeAdd: "npm install"},
+		}},
+	}
+
+	for _, path := range []string{"workspace.yaml", "workspace.json"} {
+		data, err := m.Marshal(path)
+		if err != nil {
+			t.Fatalf("Marshal(%s) failed: %v", path, err)
+		}
+
+		parsed, err := Parse(data)
+		if err != nil {
+			t.Fatalf("Parse of %s output failed: %v\n%s", path, err, data)
+		}
+		if !sameValue(parsed, m) {
+			t.Errorf("Round trip through %s changed the manifest:\n%s", path, data)
+		}
+	}
+
+	data, _ := m.Marshal("workspace.yaml")
+	yaml := string(data)
+	if !strings.HasPrefix(yaml, "version: 1\n") {
+		t.Errorf("Expected fields in struct order, got:\n%s", yaml)
+	}
+	if !strings.Contains(yaml, "work-on: |\n    #!/bin/bash\n    echo work\n") {
+		t.Errorf("Expected scripts as literal blocks, got:\n%s", yaml)
+	}
+}
@@ -0,0 +1,334 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+// Options controls where 'wt migrate-home' moves data to
+type Options struct {
+	Source consts.Layout
+	Target consts.Layout
+	DryRun bool
+}
+
+// Move is one file, directory, repository or worktree to relocate
+type Move struct {
+	Kind string
+	Name string
+	From string
+	To   string
+}
+
+const (
+	kindFile     = "file"
+	kindDir      = "directory"
+	kindRepo     = "repo"
+	kindWorktree = "worktree"
+)
+
+// repoPlan lists the moves of one repository; stay holds the worktrees that keep their path but still need re-linking
+type repoPlan struct {
+	index     int
+	repo      *Move
+	worktrees []Move
+	stay      []string
+	roots     []string
+}
+
+type plan struct {
+	files      []Move
+	state      *Move
+	repos      []repoPlan
+	cleanRoots []string
+}
+
+func (p *plan) moves() []Move {
+	var moves []Move
+	for _, rp := range p.repos {
+		if rp.repo != nil {
+			moves = append(moves, *rp.repo)
+		}
+		moves = append(moves, rp.worktrees...)
+	}
+	moves = append(moves, p.files...)
+	if p.state != nil {
+		moves = append(moves, *p.state)
+	}
+	return moves
+}
+
+// MigrateHome moves config, state, scripts, repositories and worktrees from the source layout to the target layout.
+// Repositories and worktrees are only moved when they sit where wt put them, and are re-linked with 'git worktree repair'
+func MigrateHome(appState *state.State, opts Options) ([]Move, error) {
+	p, err := buildPlan(appState, opts.Source, opts.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	moves := p.moves()
+	PrintMoves(moves, opts.DryRun)
+	if opts.DryRun || len(moves) == 0 {
+		return moves, nil
+	}
+
+	if err := p.execute(appState); err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
+func buildPlan(appState *state.State, source, target consts.Layout) (*plan, error) {
+	overrides := consts.GetDirectoryOverrides()
+	src := source.Directories(overrides)
+	dst := target.Directories(overrides)
+	p := &plan{}
+
+	if source != target {
+		srcFiles := consts.FilePathsFor(src)
+		dstFiles := consts.FilePathsFor(dst)
+		for _, move := range []Move{
+			{Kind: kindFile, Name: "config.json", From: srcFiles.Config, To: dstFiles.Config},
+			{Kind: kindDir, Name: "scripts", From: src.ScriptsDir, To: dst.ScriptsDir},
+			{Kind: kindDir, Name: "workspaces", From: src.WorkspacesDir, To: dst.WorkspacesDir},
+			{Kind: kindDir, Name: "logs", From: src.LogsDir, To: dst.LogsDir},
+		} {
+			if move.From != move.To && fileops.FileExists(move.From) {
+				p.files = append(p.files, move)
+			}
+		}
+		if srcFiles.State != dstFiles.State {
+			p.state = &Move{Kind: kindFile, Name: "state.json", From: srcFiles.State, To: dstFiles.State}
+		}
+		p.cleanRoots = []string{source.ConfigDir, source.StateDir, source.DataDir}
+	}
+
+	repoRoots := candidateDirs(source, overrides, func(d consts.DirectoryPaths) string { return d.DefaultGitReposDir })
+	worktreeRoots := candidateDirs(source, overrides, func(d consts.DirectoryPaths) string { return d.DefaultWorktreesDir })
+
+	for i, repo := range appState.Repos {
+		rp := repoPlan{index: i}
+
+		if root := placedUnder(repo.Dir, repoRoots, repo.Alias); root != "" {
+			to := filepath.Join(dst.DefaultGitReposDir, repo.Alias)
+			if !samePath(repo.Dir, to) {
+				rp.repo = &Move{Kind: kindRepo, Name: repo.Alias, From: repo.Dir, To: to}
+				rp.roots = append(rp.roots, root)
+			}
+		}
+
+		infos, err := worktree.RepoWorktrees(&appState.Repos[i])
+		if err != nil && fileops.FileExists(repo.Dir) {
+			return nil, fmt.Errorf("failed to list worktrees of %s: %w", repo.Alias, err)
+		}
+		for _, info := range infos {
+			branch := info.ShortBranch()
+			rel := filepath.Join(repo.Alias, branch)
+			root := ""
+			if branch != "" {
+				root = placedUnder(info.Path, worktreeRoots, rel)
+			}
+			to := filepath.Join(dst.DefaultWorktreesDir, rel)
+			if root == "" || samePath(info.Path, to) {
+				rp.stay = append(rp.stay, info.Path)
+				continue
+			}
+			rp.worktrees = append(rp.worktrees, Move{Kind: kindWorktree, Name: repo.Alias + ":" + branch, From: info.Path, To: to})
+			rp.roots = append(rp.roots, root)
+		}
+
+		p.repos = append(p.repos, rp)
+	}
+
+	for _, move := range p.moves() {
+		if move.Kind != kindFile || move.Name != "state.json" {
+			if fileops.FileExists(move.To) {
+				return nil, fmt.Errorf("cannot move %s: %s already exists", move.Name, move.To)
+			}
+		}
+	}
+	if p.state != nil && fileops.FileExists(p.state.To) {
+		return nil, fmt.Errorf("cannot move state.json: %s already exists", p.state.To)
+	}
+
+	return p, nil
+}
+
+// candidateDirs lists the directories wt may have placed repositories or worktrees in: the layout's own,
+// the configured override and those of ~/.worktree-manager
+func candidateDirs(source consts.Layout, overrides consts.DirectoryOverrides, dir func(consts.DirectoryPaths) string) []string {
+	dirs := []string{
+		dir(source.Directories(consts.DirectoryOverrides{})),
+		dir(source.Directories(overrides)),
+	}
+	if legacy, err := consts.LegacyRoot(); err == nil {
+		dirs = append(dirs, dir(consts.SingleRootLayout(legacy).Directories(consts.DirectoryOverrides{})))
+	}
+
+	var unique []string
+	for _, d := range dirs {
+		if !slices.Contains(unique, d) {
+			unique = append(unique, d)
+		}
+	}
+	return unique
+}
+
+// placedUnder returns the root that path equals root/rel for, or "" when wt did not place it
+func placedUnder(path string, roots []string, rel string) string {
+	for _, root := range roots {
+		if samePath(path, filepath.Join(root, rel)) {
+			return root
+		}
+	}
+	return ""
+}
+
+// samePath compares two paths, resolving symlinks where the paths exist
+func samePath(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && resolvedA == resolvedB
+}
+
+func (p *plan) execute(appState *state.State) error {
+	for _, rp := range p.repos {
+		if err := p.executeRepo(appState, rp); err != nil {
+			// Record the repositories moved so far; the state file itself has not moved yet
+			if saveErr := appState.Save(); saveErr != nil {
+				output.Warning("Failed to save state: %v", saveErr)
+			}
+			return fmt.Errorf("migration stopped: %w", err)
+		}
+	}
+
+	for _, move := range p.files {
+		if err := fileops.MoveTree(move.From, move.To); err != nil {
+			return fmt.Errorf("failed to move %s: %w", move.Name, err)
+		}
+		removeEmptyParents(move.From, p.cleanRoots)
+	}
+
+	statePath := consts.GetFilePaths().State
+	if p.state != nil {
+		statePath = p.state.To
+	}
+	if err := fileops.EnsureDir(filepath.Dir(statePath)); err != nil {
+		return err
+	}
+	if err := fileops.WriteJSONFile(statePath, appState); err != nil {
+		return err
+	}
+	if p.state != nil {
+		os.Remove(p.state.From)
+		removeEmptyParents(p.state.From, p.cleanRoots)
+	}
+
+	for _, root := range p.cleanRoots {
+		removeEmptyDirs(root)
+	}
+
+	// ~/.worktree-manager takes precedence over the XDG directories, so it must not outlive a migration away from it
+	if legacy, err := consts.LegacyRoot(); err == nil && slices.Contains(p.cleanRoots, legacy) && fileops.FileExists(legacy) {
+		renamed := legacy + ".old"
+		if err := os.Rename(legacy, renamed); err != nil {
+			return fmt.Errorf("failed to move %s out of the way: %w", legacy, err)
+		}
+		output.Warning("%s still held files after the migration and was renamed to %s", legacy, renamed)
+	}
+	return nil
+}
+
+func (p *plan) executeRepo(appState *state.State, rp repoPlan) error {
+	repo := &appState.Repos[rp.index]
+	if rp.repo == nil && len(rp.worktrees) == 0 {
+		return nil
+	}
+
+	if rp.repo != nil {
+		output.Progress("Moving repository %s to %s...", repo.Alias, rp.repo.To)
+		if err := fileops.MoveTree(rp.repo.From, rp.repo.To); err != nil {
+			return err
+		}
+		repo.Dir = rp.repo.To
+		removeEmptyParents(rp.repo.From, rp.roots)
+	}
+
+	paths := slices.Clone(rp.stay)
+	for _, move := range rp.worktrees {
+		output.Progress("Moving worktree %s to %s...", move.Name, move.To)
+		if err := fileops.MoveTree(move.From, move.To); err != nil {
+			return err
+		}
+		paths = append(paths, move.To)
+		removeEmptyParents(move.From, rp.roots)
+	}
+
+	if len(paths) > 0 {
+		if err := git.RepairWorktrees(repo.Dir, paths); err != nil {
+			return fmt.Errorf("failed to repair worktree links of %s: %w", repo.Alias, err)
+		}
+		// Environment files hold the paths that just changed
+		worktree.RefreshRepoEnvFiles(appState, repo)
+	}
+	return nil
+}
+
+// removeEmptyParents removes the directories left empty above a moved path, stopping at the first root it reaches
+func removeEmptyParents(path string, roots []string) {
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if slices.Contains(roots, dir) || dir == filepath.Dir(dir) {
+			os.Remove(dir)
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
+
+// removeEmptyDirs removes root and the directories below it that hold no files
+func removeEmptyDirs(root string) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			removeEmptyDirs(filepath.Join(root, entry.Name()))
+		}
+	}
+	os.Remove(root)
+}
+
+// PrintMoves displays the moves of a migration
+func PrintMoves(moves []Move, dryRun bool) {
+	if len(moves) == 0 {
+		output.Success("Everything is already in place")
+		return
+	}
+
+	if dryRun {
+		output.Info("Planned moves:")
+	} else {
+		output.Info("Moving:")
+	}
+
+	rows := make([][]string, 0, len(moves))
+	for _, move := range moves {
+		rows = append(rows, []string{move.Kind, move.Name, move.From, move.To})
+	}
+	output.Table([]string{"KIND", "NAME", "FROM", "TO"}, rows)
+}
@@ -0,0 +1,147 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/testutil"
+)
+
+// setupHome creates a WT_HOME with config, state and a repo with one worktree in the default places
+func setupHome(t *testing.T) (*state.State, string) {
+	t.Helper()
+	home := testutil.SetupHome(t)
+	t.Setenv("WT_HOME", filepath.Join(home, "wt"))
+	consts.SetDirectoryOverrides(consts.DirectoryOverrides{})
+	t.Cleanup(func() { consts.SetDirectoryOverrides(consts.DirectoryOverrides{}) })
+
+	dirs := consts.GetDirectoryPaths()
+	repoDir := filepath.Join(dirs.DefaultGitReposDir, "app")
+	testutil.RunGit(t, "", "init", "--quiet", "--initial-branch=main", repoDir)
+	testutil.RunGit(t, repoDir, "commit", "--quiet", "--allow-empty", "-m", "Initial commit")
+
+	worktreePath := filepath.Join(dirs.DefaultWorktreesDir, "app", "feature", "one")
+	testutil.RunGit(t, repoDir, "worktree", "add", "--quiet", "-b", "feature/one", worktreePath)
+
+	appState := &state.State{ActiveRepo: "app", Repos: []state.Repo{{Alias: "app", Dir: repoDir}}}
+	if err := appState.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(consts.GetFilePaths().Config, []byte(`{"config-editor": "vi"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return appState, worktreePath
+}
+
+func sourceLayout(t *testing.T) consts.Layout {
+	t.Helper()
+	layout, err := consts.ResolveLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return layout
+}
+
+func TestMigrateHome_WorktreesDir(t *testing.T) {
+	appState, oldPath := setupHome(t)
+	source := sourceLayout(t)
+
+	worktreesDir := filepath.Join(t.TempDir(), "trees")
+	consts.SetDirectoryOverrides(consts.DirectoryOverrides{WorktreesDir: worktreesDir})
+
+	moves, err := MigrateHome(appState, Options{Source: source, Target: source, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(moves) != 1 || moves[0].Kind != kindWorktree {
+		t.Fatalf("Expected one worktree move, got %+v", moves)
+	}
+	if !fileops.FileExists(oldPath) {
+		t.Fatal("dry run moved the worktree")
+	}
+
+	if _, err := MigrateHome(appState, Options{Source: source, Target: source}); err != nil {
+		t.Fatalf("MigrateHome failed: %v", err)
+	}
+
+	newPath := filepath.Join(worktreesDir, "app", "feature", "one")
+	if fileops.FileExists(oldPath) {
+		t.Error("old worktree path still exists")
+	}
+	if branch := testutil.RunGit(t, newPath, "branch", "--show-current"); branch != "feature/one" {
+		t.Errorf("moved worktree is on %q, want feature/one", branch)
+	}
+	list := testutil.RunGit(t, appState.Repos[0].Dir, "worktree", "list", "--porcelain")
+	if !strings.Contains(list, newPath) {
+		t.Errorf("repository does not know the new worktree path:\n%s", list)
+	}
+
+	// Running again finds nothing to move
+	moves, err = MigrateHome(appState, Options{Source: source, Target: source, DryRun: true})
+	if err != nil || len(moves) != 0 {
+		t.Errorf("Expected nothing left to move, got %+v, %v", moves, err)
+	}
+}
+
+func TestMigrateHome_To(t *testing.T) {
+	appState, _ := setupHome(t)
+	source := sourceLayout(t)
+	oldRoot := source.ConfigDir
+
+	external := filepath.Join(t.TempDir(), "external")
+	testutil.RunGit(t, "", "init", "--quiet", external)
+	appState.Repos = append(appState.Repos, state.Repo{Alias: "external", Dir: external})
+
+	newRoot := filepath.Join(t.TempDir(), "newroot")
+	target := consts.SingleRootLayout(newRoot)
+	if _, err := MigrateHome(appState, Options{Source: source, Target: target}); err != nil {
+		t.Fatalf("MigrateHome failed: %v", err)
+	}
+
+	t.Setenv("WT_HOME", newRoot)
+	paths := consts.GetFilePaths()
+	for _, path := range []string{paths.Config, paths.State} {
+		if !fileops.FileExists(path) {
+			t.Errorf("%s was not moved", path)
+		}
+	}
+	if fileops.FileExists(oldRoot) {
+		t.Errorf("old root %s was left behind", oldRoot)
+	}
+
+	migrated, err := state.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDir := filepath.Join(newRoot, "repos", "app")
+	if migrated.Repos[0].Dir != wantDir {
+		t.Errorf("repo dir = %s, want %s", migrated.Repos[0].Dir, wantDir)
+	}
+	if migrated.Repos[1].Dir != external {
+		t.Errorf("repository outside the repos directory was moved to %s", migrated.Repos[1].Dir)
+	}
+
+	worktreePath := filepath.Join(newRoot, "worktrees", "app", "feature", "one")
+	if branch := testutil.RunGit(t, worktreePath, "branch", "--show-current"); branch != "feature/one" {
+		t.Errorf("moved worktree is on %q, want feature/one", branch)
+	}
+}
+
+func TestMigrateHome_RefusesExistingTarget(t *testing.T) {
+	appState, _ := setupHome(t)
+	source := sourceLayout(t)
+
+	newRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(newRoot, "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := MigrateHome(appState, Options{Source: source, Target: consts.SingleRootLayout(newRoot)}); err == nil {
+		t.Fatal("Expected an error when the target already holds a config")
+	}
+}
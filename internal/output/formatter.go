@@ -1,41 +1,98 @@
 package output
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
 )
 
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// Redirect sends all output, including that of scripts, to the given writers until the returned restore function is called
+func Redirect(out, errOut io.Writer) (restore func()) {
+	prevOut, prevErr := stdout, stderr
+	stdout, stderr = out, errOut
+	return func() {
+		stdout, stderr = prevOut, prevErr
+	}
+}
+
+// Stdout returns the writer that output currently goes to
+func Stdout() io.Writer {
+	return stdout
+}
+
+// Stderr returns the writer that error output of child processes currently goes to
+func Stderr() io.Writer {
+	return stderr
+}
+
+// Interactive reports whether output goes to the terminal, so child processes may also read from it
+func Interactive() bool {
+	return stdout == os.Stdout
+}
+
 func Success(format string, args ...interface{}) {
-	fmt.Printf("✅  "+format+"\n", args...)
+	fmt.Fprintf(stdout, "✅  "+format+"\n", args...)
 }
 
 func Error(format string, args ...interface{}) {
-	fmt.Printf("❌  "+format+"\n", args...)
+	fmt.Fprintf(stdout, "❌  "+format+"\n", args...)
 }
 
 func Progress(format string, args ...interface{}) {
-	fmt.Printf("🔄  "+format+"\n", args...)
+	fmt.Fprintf(stdout, "🔄  "+format+"\n", args...)
 }
 
 func Info(format string, args ...interface{}) {
-	fmt.Printf("📁  "+format+"\n", args...)
+	fmt.Fprintf(stdout, "📁  "+format+"\n", args...)
 }
 
 func Hint(format string, args ...interface{}) {
-	fmt.Printf("💡  "+format+"\n", args...)
+	fmt.Fprintf(stdout, "💡  "+format+"\n", args...)
 }
 
 func Warning(format string, args ...interface{}) {
-	fmt.Printf("⚠️  "+format+"\n", args...)
+	fmt.Fprintf(stdout, "⚠️  "+format+"\n", args...)
 }
 
 func Item(format string, args ...interface{}) {
-	fmt.Printf("🔸  "+format+"\n", args...)
+	fmt.Fprintf(stdout, "🔸  "+format+"\n", args...)
 }
 
 func Cleanup(format string, args ...interface{}) {
-	fmt.Printf("🗑️  "+format+"\n", args...)
+	fmt.Fprintf(stdout, "🗑️  "+format+"\n", args...)
 }
 
 func Question(format string, args ...interface{}) {
-	fmt.Printf("❓  "+format, args...)
+	fmt.Fprintf(stdout, "❓  "+format, args...)
+}
+
+// Table prints rows aligned in columns beneath the given headers
+func Table(headers []string, rows [][]string) {
+	for _, line := range AlignColumns(append([][]string{headers}, rows...)) {
+		fmt.Fprintln(stdout, line)
+	}
+}
+
+// AlignColumns pads each row's cells into aligned columns and returns one line per row
+func AlignColumns(rows [][]string) []string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
 }
@@ -0,0 +1,23 @@
+package output
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAlignColumns(t *testing.T) {
+	lines := AlignColumns([][]string{
+		{"BRANCH", "STATE"},
+		{"feature/long-name", "dirty"},
+		{"main", ""},
+	})
+
+	expected := []string{
+		"BRANCH             STATE",
+		"feature/long-name  dirty",
+		"main",
+	}
+	if !slices.Equal(lines, expected) {
+		t.Errorf("AlignColumns() = %q, expected %q", lines, expected)
+	}
+}
@@ -0,0 +1,43 @@
+package output
+
+import "bytes"
+
+// LineWriter hands each complete line written to it, newline included, to a callback. It is not safe for concurrent
+// use; writers that share a destination serialize in the callback
+type LineWriter struct {
+	onLine func(line []byte) error
+	buf    bytes.Buffer
+}
+
+// NewLineWriter creates a LineWriter calling onLine for every line
+func NewLineWriter(onLine func(line []byte) error) *LineWriter {
+	return &LineWriter{onLine: onLine}
+}
+
+func (l *LineWriter) Write(data []byte) (int, error) {
+	l.buf.Write(data)
+
+	for {
+		line, err := l.buf.ReadBytes('\n')
+		if err != nil {
+			// Keep the incomplete line until the rest of it arrives
+			l.buf.Write(line)
+			break
+		}
+		if err := l.onLine(line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+// Flush hands on any buffered partial line, ending it with a newline
+func (l *LineWriter) Flush() error {
+	if l.buf.Len() == 0 {
+		return nil
+	}
+	line := append(l.buf.Bytes(), '\n')
+	l.buf.Reset()
+	return l.onLine(line)
+}
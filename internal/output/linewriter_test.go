@@ -0,0 +1,39 @@
+package output
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	writer := NewLineWriter(func(line []byte) error {
+		lines = append(lines, string(line))
+		return nil
+	})
+
+	writer.Write([]byte("one\ntw"))
+	writer.Write([]byte("o\n\nthree"))
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	expected := []string{"one\n", "two\n", "\n", "three\n"}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected lines %q, got %q", expected, lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Expected line %d to be %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
+
+func TestLineWriter_CallbackError(t *testing.T) {
+	failure := errors.New("closed")
+	writer := NewLineWriter(func(line []byte) error { return failure })
+
+	if _, err := writer.Write([]byte("line\n")); !errors.Is(err, failure) {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+package output
+
+import (
+	"io"
+	"sync"
+)
+
+// PrefixWriter writes each complete line to the underlying writer with a prefix
+type PrefixWriter struct {
+	*LineWriter
+}
+
+// NewPrefixWriter creates a PrefixWriter; writers sharing mu never interleave within a line
+func NewPrefixWriter(w io.Writer, prefix string, mu *sync.Mutex) *PrefixWriter {
+	return &PrefixWriter{NewLineWriter(func(line []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := w.Write(append([]byte(prefix), line...))
+		return err
+	})}
+}
@@ -0,0 +1,30 @@
+package output
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	writer := NewPrefixWriter(&buf, "[api/main] ", &mu)
+
+	writer.Write([]byte("first line\nsecond "))
+	writer.Write([]byte("line\npartial"))
+
+	expected := "[api/main] first line\n[api/main] second line\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q before flush, got %q", expected, buf.String())
+	}
+
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	expected += "[api/main] partial\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q after flush, got %q", expected, buf.String())
+	}
+}
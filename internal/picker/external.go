@@ -0,0 +1,67 @@
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// multiSelectFlags adds multi-select to the external finders known to support it
+var multiSelectFlags = map[string]string{
+	"fzf": "--multi",
+	"sk":  "--multi",
+}
+
+// runExternal pipes the item labels to an external finder such as fzf and maps the lines it prints back to items
+func runExternal(items []Item, opts Options) ([]Item, error) {
+	fields := strings.Fields(opts.Command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("picker command is empty")
+	}
+
+	command := opts.Command
+	if opts.Multi {
+		if flag, ok := multiSelectFlags[filepath.Base(fields[0])]; ok {
+			command += " " + flag
+		}
+	}
+
+	labels := make([]string, len(items))
+	byLabel := make(map[string]Item, len(items))
+	for i, item := range items {
+		labels[i] = item.Label
+		byLabel[item.Label] = item
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = strings.NewReader(strings.Join(labels, "\n") + "\n")
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		// fzf exits with 1 when nothing matched and 130 when interrupted
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 1 || exitErr.ExitCode() == 130) {
+			return nil, ErrCancelled
+		}
+		return nil, fmt.Errorf("picker command '%s' failed: %w", opts.Command, err)
+	}
+
+	var selected []Item
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if item, ok := byLabel[line]; ok {
+			selected = append(selected, item)
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, ErrCancelled
+	}
+	if !opts.Multi {
+		selected = selected[:1]
+	}
+	return selected, nil
+}
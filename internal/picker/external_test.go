@@ -0,0 +1,52 @@
+package picker
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+
+	"golang.org/x/term"
+)
+
+func TestRunExternal(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+
+	items := testItems()
+
+	selected, err := Pick(items, Options{Command: "grep feature", Multi: true})
+	if err != nil {
+		t.Fatalf("Pick failed: %v", err)
+	}
+	if got := values(selected); !slices.Equal(got, []string{"feature/login", "feature/logout"}) {
+		t.Errorf("Expected both feature branches, got %v", got)
+	}
+
+	selected, err = Pick(items, Options{Command: "grep feature"})
+	if err != nil {
+		t.Fatalf("Pick failed: %v", err)
+	}
+	if got := values(selected); !slices.Equal(got, []string{"feature/login"}) {
+		t.Errorf("Expected only the first line without multi-select, got %v", got)
+	}
+
+	if _, err := Pick(items, Options{Command: "grep nothing"}); !errors.Is(err, ErrCancelled) {
+		t.Errorf("Expected ErrCancelled when the command selects nothing, got %v", err)
+	}
+	if _, err := Pick(items, Options{Command: "exit 2"}); err == nil || errors.Is(err, ErrCancelled) {
+		t.Errorf("Expected a failure for exit status 2, got %v", err)
+	}
+}
+
+func TestPick_NoTerminal(t *testing.T) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		t.Skip("stdin is a terminal")
+	}
+
+	if _, err := Pick(testItems(), Options{Command: BuiltinCommand}); !errors.Is(err, ErrNoTerminal) {
+		t.Errorf("Expected ErrNoTerminal, got %v", err)
+	}
+}
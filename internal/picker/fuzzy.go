@@ -0,0 +1,92 @@
+package picker
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scores awarded while matching; consecutive and word-start matches rank higher than scattered ones
+const (
+	scoreMatch       = 1
+	scoreConsecutive = 4
+	scoreWordStart   = 6
+	penaltyGap       = 1
+)
+
+// Match reports whether every rune of pattern appears in text in order, ignoring case, and how well it matches
+func Match(pattern, text string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+
+	patternRunes := []rune(strings.ToLower(pattern))
+	textRunes := []rune(strings.ToLower(text))
+
+	score := 0
+	p := 0
+	last := -1
+	for i := 0; i < len(textRunes) && p < len(patternRunes); i++ {
+		if textRunes[i] != patternRunes[p] {
+			continue
+		}
+
+		score += scoreMatch
+		switch {
+		case last >= 0 && i == last+1:
+			score += scoreConsecutive
+		case last >= 0:
+			score -= penaltyGap
+		}
+		if i == 0 || isSeparator(textRunes[i-1]) {
+			score += scoreWordStart
+		}
+
+		last = i
+		p++
+	}
+
+	if p < len(patternRunes) {
+		return 0, false
+	}
+	return score, true
+}
+
+// Filter returns the indexes of the items whose labels match every space-separated term of the query, best matches first
+func Filter(items []Item, query string) []int {
+	type scored struct {
+		index int
+		score int
+	}
+
+	terms := strings.Fields(query)
+
+	var matches []scored
+	for i, item := range items {
+		total := 0
+		matched := true
+		for _, term := range terms {
+			score, ok := Match(term, item.Label)
+			if !ok {
+				matched = false
+				break
+			}
+			total += score
+		}
+		if matched {
+			matches = append(matches, scored{i, total})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	indexes := make([]int, len(matches))
+	for i, m := range matches {
+		indexes[i] = m.index
+	}
+	return indexes
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("/-_.", r)
+}
@@ -0,0 +1,61 @@
+package picker
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		matches bool
+	}{
+		{"", "anything", true},
+		{"fbr", "feature/bar", true},
+		{"FEAT", "feature/bar", true},
+		{"rab", "feature/bar", false},
+		{"feature/bar/x", "feature/bar", false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := Match(tt.pattern, tt.text); ok != tt.matches {
+			t.Errorf("Match(%q, %q) = %v, expected %v", tt.pattern, tt.text, ok, tt.matches)
+		}
+	}
+}
+
+func TestMatch_PrefersConsecutiveAndWordStarts(t *testing.T) {
+	consecutive, _ := Match("login", "fix/login-page")
+	scattered, _ := Match("login", "follow-up-in-gin")
+	if consecutive <= scattered {
+		t.Errorf("Expected consecutive match to score higher (%d <= %d)", consecutive, scattered)
+	}
+
+	wordStart, _ := Match("bar", "foo/bar")
+	midWord, _ := Match("bar", "foobar")
+	if wordStart <= midWord {
+		t.Errorf("Expected word-start match to score higher (%d <= %d)", wordStart, midWord)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	items := []Item{
+		{Label: "follow-up-in-gin  clean"},
+		{Label: "fix/login-page    dirty"},
+		{Label: "main              clean"},
+	}
+
+	if got := Filter(items, ""); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("Expected an empty query to keep every item in order, got %v", got)
+	}
+	if got := Filter(items, "login"); !slices.Equal(got, []int{1, 0}) {
+		t.Errorf("Expected best match first, got %v", got)
+	}
+	if got := Filter(items, "login dirty"); !slices.Equal(got, []int{1}) {
+		t.Errorf("Expected every term to be required, got %v", got)
+	}
+	if got := Filter(items, "zzz"); len(got) != 0 {
+		t.Errorf("Expected no matches, got %v", got)
+	}
+}
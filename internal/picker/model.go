@@ -0,0 +1,161 @@
+package picker
+
+import (
+	"fmt"
+	"unicode"
+
+	"worktree-manager/internal/terminal"
+)
+
+type action int
+
+const (
+	actionNone action = iota
+	actionAccept
+	actionCancel
+)
+
+// model holds the picker state independently of the terminal so it can be driven by key events
+type model struct {
+	items    []Item
+	multi    bool
+	header   string
+	prompt   string
+	height   int
+	query    []rune
+	matches  []int
+	cursor   int
+	offset   int
+	selected map[int]bool
+}
+
+func newModel(items []Item, opts Options, height int) *model {
+	if height < 1 {
+		height = 1
+	}
+	m := &model{
+		items:    items,
+		multi:    opts.Multi,
+		header:   opts.Header,
+		prompt:   opts.Prompt,
+		height:   height,
+		selected: map[int]bool{},
+	}
+	m.refilter()
+	return m
+}
+
+func (m *model) refilter() {
+	m.matches = Filter(m.items, string(m.query))
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m *model) move(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+
+	m.cursor = (m.cursor + delta + len(m.matches)) % len(m.matches)
+
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+// handle applies a key event and reports whether the picker should close
+func (m *model) handle(key terminal.Key) action {
+	switch key.Kind {
+	case terminal.KeyEnter:
+		return actionAccept
+	case terminal.KeyEscape, terminal.KeyInterrupt:
+		return actionCancel
+	case terminal.KeyUp:
+		m.move(-1)
+	case terminal.KeyDown:
+		m.move(1)
+	case terminal.KeyTab:
+		if m.multi && len(m.matches) > 0 {
+			index := m.matches[m.cursor]
+			if m.selected[index] {
+				delete(m.selected, index)
+			} else {
+				m.selected[index] = true
+			}
+			m.move(1)
+		}
+	case terminal.KeyBackspace:
+		if len(m.query) > 0 {
+			m.query = m.query[:len(m.query)-1]
+			m.refilter()
+		}
+	case terminal.KeyClear:
+		m.query = nil
+		m.refilter()
+	case terminal.KeyRune:
+		if unicode.IsPrint(key.Rune) {
+			m.query = append(m.query, key.Rune)
+			m.refilter()
+		}
+	}
+	return actionNone
+}
+
+// result returns the selected items in their original order, or the item under the cursor if none are selected
+func (m *model) result() []Item {
+	if m.multi && len(m.selected) > 0 {
+		var items []Item
+		for i, item := range m.items {
+			if m.selected[i] {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+
+	if len(m.matches) == 0 {
+		return nil
+	}
+	return []Item{m.items[m.matches[m.cursor]]}
+}
+
+// view renders the prompt line followed by the counter, the header and the visible matches
+func (m *model) view() []string {
+	lines := []string{m.prompt + string(m.query)}
+
+	counter := fmt.Sprintf("  %d/%d", len(m.matches), len(m.items))
+	if m.multi {
+		counter += fmt.Sprintf(" (%d selected, tab to toggle)", len(m.selected))
+	}
+	lines = append(lines, counter)
+
+	if m.header != "" {
+		lines = append(lines, "    "+m.header)
+	}
+
+	end := min(m.offset+m.height, len(m.matches))
+	for i := m.offset; i < end; i++ {
+		index := m.matches[i]
+
+		marker := "  "
+		if i == m.cursor {
+			marker = "▶ "
+		}
+		if m.multi {
+			if m.selected[index] {
+				marker += "● "
+			} else {
+				marker += "○ "
+			}
+		} else {
+			marker += "  "
+		}
+
+		lines = append(lines, marker+m.items[index].Label)
+	}
+
+	return lines
+}
@@ -0,0 +1,100 @@
+package picker
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/terminal"
+)
+
+func typeKeys(m *model, input string) action {
+	var last action
+	for _, key := range terminal.ParseKeys([]byte(input)) {
+		last = m.handle(key)
+	}
+	return last
+}
+
+func values(items []Item) []string {
+	var vals []string
+	for _, item := range items {
+		vals = append(vals, item.Value)
+	}
+	return vals
+}
+
+func testItems() []Item {
+	return []Item{
+		{Value: "main", Label: "main"},
+		{Value: "feature/login", Label: "feature/login"},
+		{Value: "feature/logout", Label: "feature/logout"},
+		{Value: "bugfix/crash", Label: "bugfix/crash"},
+	}
+}
+
+func TestModel_FilterAndAccept(t *testing.T) {
+	m := newModel(testItems(), Options{}, 10)
+
+	typeKeys(m, "logo")
+	if got := values(m.result()); !slices.Equal(got, []string{"feature/logout"}) {
+		t.Errorf("Expected feature/logout under the cursor, got %v", got)
+	}
+
+	typeKeys(m, "\x7f\x7f")
+	if len(m.matches) != 2 {
+		t.Fatalf("Expected backspace to widen the matches to 2, got %d", len(m.matches))
+	}
+
+	typeKeys(m, "\x1b[B")
+	if action := typeKeys(m, "\r"); action != actionAccept {
+		t.Fatalf("Expected enter to accept, got %v", action)
+	}
+	if got := values(m.result()); len(got) != 1 {
+		t.Errorf("Expected a single result, got %v", got)
+	}
+}
+
+func TestModel_CursorWrapsAndScrolls(t *testing.T) {
+	m := newModel(testItems(), Options{}, 2)
+
+	typeKeys(m, "\x1b[A")
+	if m.cursor != 3 || m.offset != 2 {
+		t.Errorf("Expected cursor to wrap to the last item and scroll, got cursor %d offset %d", m.cursor, m.offset)
+	}
+
+	view := m.view()
+	if len(view) != 4 || !strings.Contains(view[3], "▶") {
+		t.Errorf("Expected the cursor on the last visible line, got %q", view)
+	}
+}
+
+func TestModel_MultiSelect(t *testing.T) {
+	m := newModel(testItems(), Options{Multi: true}, 10)
+
+	// Toggle bugfix/crash and main, in reverse order of the list
+	typeKeys(m, "crash\t")
+	typeKeys(m, "\x15main\t")
+
+	if got := values(m.result()); !slices.Equal(got, []string{"main", "bugfix/crash"}) {
+		t.Errorf("Expected selections in list order, got %v", got)
+	}
+
+	// Toggling again deselects
+	typeKeys(m, "\t")
+	if got := values(m.result()); !slices.Equal(got, []string{"bugfix/crash"}) {
+		t.Errorf("Expected main to be deselected, got %v", got)
+	}
+}
+
+func TestModel_Cancel(t *testing.T) {
+	m := newModel(testItems(), Options{}, 10)
+	if action := typeKeys(m, "\x03"); action != actionCancel {
+		t.Errorf("Expected ctrl-c to cancel, got %v", action)
+	}
+
+	typeKeys(m, "nomatch")
+	if result := m.result(); result != nil {
+		t.Errorf("Expected no result without matches, got %v", result)
+	}
+}
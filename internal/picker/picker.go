@@ -0,0 +1,46 @@
+package picker
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCancelled is returned when the user leaves the picker without choosing anything
+var ErrCancelled = errors.New("selection cancelled")
+
+// ErrNoTerminal is returned when the built-in picker is needed but stdin is not a terminal
+var ErrNoTerminal = errors.New("no terminal available for interactive selection")
+
+// BuiltinCommand selects the built-in picker explicitly in the config
+const BuiltinCommand = "builtin"
+
+// Item is an entry the user can pick; Label is what is shown and matched, Value what the caller gets back
+type Item struct {
+	Value string
+	Label string
+}
+
+// Options controls how the picker is shown
+type Options struct {
+	Prompt  string
+	Header  string
+	Multi   bool
+	Command string
+}
+
+// Pick lets the user choose one item, or several when Multi is set, using the configured external
+// command if there is one and the built-in terminal finder otherwise
+func Pick(items []Item, opts Options) ([]Item, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("nothing to pick from")
+	}
+
+	if opts.Prompt == "" {
+		opts.Prompt = "> "
+	}
+
+	if opts.Command != "" && opts.Command != BuiltinCommand {
+		return runExternal(items, opts)
+	}
+	return runTerminal(items, opts)
+}
@@ -0,0 +1,107 @@
+package picker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+	"worktree-manager/internal/terminal"
+)
+
+// maxHeight caps how many matches the built-in picker shows at once
+const maxHeight = 15
+
+// runTerminal runs the built-in finder, reading keys from stdin and drawing on stderr so stdout stays free for results
+func runTerminal(items []Item, opts Options) ([]Item, error) {
+	inFd := int(os.Stdin.Fd())
+	outFd := int(os.Stderr.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return nil, ErrNoTerminal
+	}
+
+	width, rows, err := term.GetSize(outFd)
+	if err != nil || width <= 0 || rows <= 0 {
+		width, rows = 80, 24
+	}
+
+	// Leave room for the prompt, counter and header lines
+	height := min(maxHeight, rows-4, len(items))
+
+	oldState, err := term.MakeRaw(inFd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to switch terminal to raw mode: %w", err)
+	}
+	defer term.Restore(inFd, oldState)
+
+	m := newModel(items, opts, height)
+	screen := &screen{w: os.Stderr, width: width}
+	defer screen.clear()
+
+	buf := make([]byte, 256)
+	for {
+		screen.draw(m.view())
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from terminal: %w", err)
+		}
+
+		for _, key := range terminal.ParseKeys(buf[:n]) {
+			switch m.handle(key) {
+			case actionAccept:
+				if result := m.result(); len(result) > 0 {
+					return result, nil
+				}
+			case actionCancel:
+				return nil, ErrCancelled
+			}
+		}
+	}
+}
+
+// screen redraws the picker in place below the cursor position it started at
+type screen struct {
+	w     io.Writer
+	width int
+	drawn bool
+}
+
+func (s *screen) draw(lines []string) {
+	var b strings.Builder
+
+	b.WriteString("\r\x1b[J")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(s.fit(line))
+	}
+
+	// Return the cursor to the end of the prompt line
+	if len(lines) > 1 {
+		fmt.Fprintf(&b, "\x1b[%dA", len(lines)-1)
+	}
+	b.WriteString("\r")
+	if promptWidth := terminal.Width(s.fit(lines[0])); promptWidth > 0 {
+		fmt.Fprintf(&b, "\x1b[%dC", promptWidth)
+	}
+
+	io.WriteString(s.w, b.String())
+	s.drawn = true
+}
+
+func (s *screen) clear() {
+	if s.drawn {
+		io.WriteString(s.w, "\r\x1b[J")
+	}
+}
+
+// fit truncates a line to the terminal width so it never wraps and breaks the redraw
+func (s *screen) fit(line string) string {
+	if s.width > 1 {
+		return terminal.Truncate(line, s.width-1)
+	}
+	return line
+}
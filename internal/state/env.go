@@ -0,0 +1,45 @@
+package state
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// EnvTemplates maps the names of variables a repository's worktrees are given to values rendered for each worktree
+// with text/template, such as app_{{.Branch | slug}} or http://localhost:{{.Ports.web}}
+type EnvTemplates map[string]string
+
+// envNamePattern matches names the shell accepts for variables
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Validate checks that variable names are usable in a shell and leave the WT_ prefix to wt
+func (e EnvTemplates) Validate() error {
+	for _, name := range slices.Sorted(maps.Keys(e)) {
+		if !envNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid variable name '%s' in env", name)
+		}
+		if strings.HasPrefix(name, "WT_") {
+			return fmt.Errorf("variable '%s' in env uses the WT_ prefix, which is reserved for wt", name)
+		}
+	}
+	return nil
+}
+
+// SetEnvTemplates replaces the variables of a repository and saves the state
+func (s *State) SetEnvTemplates(alias string, env EnvTemplates) error {
+	if err := env.Validate(); err != nil {
+		return err
+	}
+	i := s.findRepoIndex(alias)
+	if i < 0 {
+		return fmt.Errorf("repository with alias '%s' not found", alias)
+	}
+	if len(env) == 0 {
+		env = nil
+	}
+	s.Repos[i].Env = env
+	return s.Save()
+}
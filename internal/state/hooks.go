@@ -0,0 +1,83 @@
+package state
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"worktree-manager/internal/consts"
+)
+
+// HookCommand is a command a hook runs, declared in the config or a repository's state instead of in a script file
+type HookCommand struct {
+	// Run is the command line, run with bash
+	Run string `json:"run"`
+	// Dir is where the command runs, relative to the worktree; the worktree itself by default
+	Dir string            `json:"dir,omitempty"`
+	Env map[string]string `json:"env,omitempty"`
+	If  *HookCondition    `json:"if,omitempty"`
+}
+
+// HookCondition limits when a hook command runs; every field that is set must match
+type HookCondition struct {
+	// Exists and NotExists are glob patterns relative to the worktree
+	Exists    string `json:"exists,omitempty"`
+	NotExists string `json:"not-exists,omitempty"`
+	// Branch is a glob pattern such as feature/*
+	Branch string `json:"branch,omitempty"`
+	// Operation is what ran the hook: add, pull-request or work-on
+	Operation string `json:"operation,omitempty"`
+}
+
+// HookCommands maps hook names, such as post-worktree-add, to the commands they run in order
+type HookCommands map[string][]HookCommand
+
+// Validate checks that commands are declared for known hooks and have a command line and valid patterns
+func (h HookCommands) Validate() error {
+	for hook, commands := range h {
+		if _, ok := consts.DefaultHookTimeouts[hook]; !ok {
+			return fmt.Errorf("unknown hook '%s' in hook-commands (valid hooks: %s, %s)", hook, consts.HookPostWorktreeAdd, consts.HookWorkOn)
+		}
+		for i, command := range commands {
+			if command.Run == "" {
+				return fmt.Errorf("%s hook command %d has no 'run' command line", hook, i+1)
+			}
+			if command.If == nil {
+				continue
+			}
+			for _, pattern := range []string{command.If.Exists, command.If.NotExists, command.If.Branch} {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("%s hook command '%s' has an invalid pattern '%s'", hook, command.Run, pattern)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a command with this condition runs for the given worktree; a nil condition always matches
+func (c *HookCondition) Matches(worktreePath, branch, operation string) bool {
+	if c == nil {
+		return true
+	}
+	if c.Exists != "" && !globMatches(worktreePath, c.Exists) {
+		return false
+	}
+	if c.NotExists != "" && globMatches(worktreePath, c.NotExists) {
+		return false
+	}
+	if c.Branch != "" {
+		if matched, _ := path.Match(c.Branch, branch); !matched {
+			return false
+		}
+	}
+	if c.Operation != "" && c.Operation != operation {
+		return false
+	}
+	return true
+}
+
+func globMatches(dir, pattern string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+	return len(matches) > 0
+}
@@ -0,0 +1,146 @@
+package state
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// PortSlots maps the names of ports a repository's services listen on, such as web, to their base port. The main
+// checkout keeps the base ports and the worktree with index N is given base+N, so base ports should be further apart
+// than the number of worktrees kept at once
+type PortSlots map[string]int
+
+// portNamePattern keeps port names usable in environment variable names
+var portNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Validate checks that port names can be used in WT_PORT_<NAME> and base ports are valid port numbers
+func (p PortSlots) Validate() error {
+	for _, name := range slices.Sorted(maps.Keys(p)) {
+		if !portNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid port name '%s' (use letters, digits, '-' and '_')", name)
+		}
+		if port := p[name]; port < 1 || port > 65535 {
+			return fmt.Errorf("port '%s' has an invalid base port %d", name, port)
+		}
+	}
+	return nil
+}
+
+// PortEnvName returns the environment variable a port is passed to hooks in, such as WT_PORT_WEB for web
+func PortEnvName(name string) string {
+	return "WT_PORT_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// WorktreeResources are what a worktree is given so that its services can run next to those of other worktrees
+type WorktreeResources struct {
+	// Index is unique among the worktrees of a repository, starting at 1; zero means none is allocated
+	Index          int
+	Ports          map[string]int
+	ComposeProject string
+}
+
+// Resources returns the resources of a worktree given the index allocated to it
+func (r *Repo) Resources(branch string, index int) WorktreeResources {
+	resources := WorktreeResources{Index: index, ComposeProject: ComposeProjectName(r.Alias, branch)}
+
+	for name, base := range r.Ports {
+		if port := base + index; port <= 65535 {
+			if resources.Ports == nil {
+				resources.Ports = make(map[string]int)
+			}
+			resources.Ports[name] = port
+		}
+	}
+	return resources
+}
+
+// ComposeProjectName returns the docker compose project name of a worktree, <alias>-<branch-slug>. It is lowercased and
+// runs of what compose does not accept in project names, such as the slashes of feature/login, become a single '-'
+func ComposeProjectName(alias, branch string) string {
+	var name strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(alias + "-" + branch) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			name.WriteRune(r)
+			dash = false
+		} else if !dash {
+			name.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.Trim(name.String(), "-_")
+}
+
+// AllocateWorktreeIndex returns the index of a worktree, allocating the lowest one no other worktree of the repository
+// holds if it has none yet. Indexes are released with the worktree's metadata
+func (s *State) AllocateWorktreeIndex(alias, branch string) (int, error) {
+	if branch == "" {
+		return 0, fmt.Errorf("a detached worktree has no index")
+	}
+	i := s.findRepoIndex(alias)
+	if i < 0 {
+		return 0, fmt.Errorf("repository with alias '%s' not found", alias)
+	}
+
+	if meta, ok := s.GetWorktreeMetadata(alias, branch); ok && meta.Index > 0 {
+		return meta.Index, nil
+	}
+
+	taken := make(map[int]bool)
+	for _, meta := range s.Repos[i].Worktrees {
+		taken[meta.Index] = true
+	}
+	index := 1
+	for taken[index] {
+		index++
+	}
+
+	if err := s.UpdateWorktreeMetadata(alias, branch, func(meta *WorktreeMetadata) {
+		meta.Index = index
+	}); err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// Conflicts returns the pairs of port slots that give out the same port to worktrees with indexes up to maxIndex
+func (p PortSlots) Conflicts(maxIndex int) []string {
+	names := slices.Sorted(maps.Keys(p))
+	var conflicts []string
+	for i, name := range names {
+		for _, other := range names[i+1:] {
+			if distance := p[name] - p[other]; distance >= -maxIndex && distance <= maxIndex {
+				conflicts = append(conflicts, fmt.Sprintf("%s (%d) and %s (%d)", name, p[name], other, p[other]))
+			}
+		}
+	}
+	return conflicts
+}
+
+// MaxWorktreeIndex returns the highest index allocated to a worktree of the repository
+func (r *Repo) MaxWorktreeIndex() int {
+	highest := 0
+	for _, meta := range r.Worktrees {
+		highest = max(highest, meta.Index)
+	}
+	return highest
+}
+
+// SetPortSlots replaces the port slots of a repository and saves the state
+func (s *State) SetPortSlots(alias string, ports PortSlots) error {
+	if err := ports.Validate(); err != nil {
+		return err
+	}
+	i := s.findRepoIndex(alias)
+	if i < 0 {
+		return fmt.Errorf("repository with alias '%s' not found", alias)
+	}
+	if len(ports) == 0 {
+		ports = nil
+	}
+	s.Repos[i].Ports = ports
+	return s.Save()
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"worktree-manager/internal/consts"
 	"worktree-manager/internal/fileops"
@@ -20,8 +21,51 @@ type State struct {
 
 // Repo represents a repository in the state
 type Repo struct {
-	Alias string `json:"alias"`
-	Dir   string `json:"dir"`
+	Alias      string             `json:"alias"`
+	Dir        string             `json:"dir"`
+	BaseBranch string             `json:"base-branch,omitempty"`
+	Files      *FileRules         `json:"files,omitempty"`
+	Hooks      HookCommands       `json:"hook-commands,omitempty"`
+	Ports      PortSlots          `json:"ports,omitempty"`
+	Env        EnvTemplates       `json:"env,omitempty"`
+	Worktrees  []WorktreeMetadata `json:"worktrees,omitempty"`
+}
+
+// WorktreeMetadata records what worktree-manager knows about a worktree beyond what git tracks
+type WorktreeMetadata struct {
+	Branch     string     `json:"branch"`
+	IssueKey   string     `json:"issue-key,omitempty"`
+	IssueTitle string     `json:"issue-title,omitempty"`
+	BaseRef    string     `json:"base-ref,omitempty"`
+	Index      int        `json:"index,omitempty"`
+	LastUsed   *time.Time `json:"last-used,omitempty"`
+	// Compose is the docker compose stack wt last brought up or down for the worktree
+	Compose *ComposeStatus `json:"compose,omitempty"`
+}
+
+// States of a worktree's compose stack
+const (
+	ComposeUp   = "up"
+	ComposeDown = "down"
+)
+
+// ComposeStatus records the state wt left a worktree's compose stack in
+type ComposeStatus struct {
+	Project string    `json:"project"`
+	State   string    `json:"state"`
+	Updated time.Time `json:"updated"`
+}
+
+// FileRules lists glob patterns, relative to the main checkout, of untracked files to bring into new worktrees
+type FileRules struct {
+	Copy         []string `json:"copy,omitempty"`
+	Symlink      []string `json:"symlink,omitempty"`
+	CloneOnWrite []string `json:"clone-on-write,omitempty"`
+}
+
+// IsEmpty reports whether no file rules are configured
+func (r *FileRules) IsEmpty() bool {
+	return r == nil || len(r.Copy) == 0 && len(r.Symlink) == 0 && len(r.CloneOnWrite) == 0
 }
 
 var (
@@ -161,6 +205,20 @@ func (s *State) RemoveRepo(alias string) error {
 	return fmt.Errorf("repository with alias '%s' not found", alias)
 }
 
+// ForgetRepo removes a repository from the state without touching its directory or scripts, for repositories whose
+// directory is gone
+func (s *State) ForgetRepo(alias string) error {
+	i := s.findRepoIndex(alias)
+	if i < 0 {
+		return fmt.Errorf("repository with alias '%s' not found", alias)
+	}
+	s.Repos = append(s.Repos[:i], s.Repos[i+1:]...)
+	if s.ActiveRepo == alias {
+		s.ActiveRepo = ""
+	}
+	return s.Save()
+}
+
 // SetActiveRepo sets the active repository
 func (s *State) SetActiveRepo(alias string) error {
 	_, err := s.FindRepoByAlias(alias)
@@ -181,6 +239,68 @@ func (s *State) GetActiveRepo() (*Repo, error) {
 	return s.FindRepoByAlias(s.ActiveRepo)
 }
 
+// GetWorktreeMetadata returns the metadata recorded for a worktree, if any
+func (s *State) GetWorktreeMetadata(alias, branch string) (WorktreeMetadata, bool) {
+	repo, err := s.FindRepoByAlias(alias)
+	if err != nil {
+		return WorktreeMetadata{}, false
+	}
+
+	for _, meta := range repo.Worktrees {
+		if meta.Branch == branch {
+			return meta, true
+		}
+	}
+	return WorktreeMetadata{}, false
+}
+
+// UpdateWorktreeMetadata applies update to a worktree's metadata, creating it if needed, and saves the state
+func (s *State) UpdateWorktreeMetadata(alias, branch string, update func(meta *WorktreeMetadata)) error {
+	i := s.findRepoIndex(alias)
+	if i < 0 {
+		return fmt.Errorf("repository with alias '%s' not found", alias)
+	}
+
+	repo := &s.Repos[i]
+	for j := range repo.Worktrees {
+		if repo.Worktrees[j].Branch == branch {
+			update(&repo.Worktrees[j])
+			return s.Save()
+		}
+	}
+
+	meta := WorktreeMetadata{Branch: branch}
+	update(&meta)
+	repo.Worktrees = append(repo.Worktrees, meta)
+	return s.Save()
+}
+
+// RemoveWorktreeMetadata forgets the metadata of a removed worktree
+func (s *State) RemoveWorktreeMetadata(alias, branch string) error {
+	i := s.findRepoIndex(alias)
+	if i < 0 {
+		return nil
+	}
+
+	repo := &s.Repos[i]
+	for j, meta := range repo.Worktrees {
+		if meta.Branch == branch {
+			repo.Worktrees = append(repo.Worktrees[:j], repo.Worktrees[j+1:]...)
+			return s.Save()
+		}
+	}
+	return nil
+}
+
+func (s *State) findRepoIndex(alias string) int {
+	for i, repo := range s.Repos {
+		if repo.Alias == alias {
+			return i
+		}
+	}
+	return -1
+}
+
 func (s *State) createRepoScript(repoAlias string) error {
 	scriptPath := consts.GetFilePaths().PostWorktreeAddScript(repoAlias)
 	content := consts.GetPostWorktreeAddScriptContent(repoAlias)
@@ -0,0 +1,71 @@
+package terminal
+
+// KeyKind identifies a key decoded from raw terminal input
+type KeyKind int
+
+const (
+	KeyRune KeyKind = iota
+	KeyEnter
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyBackspace
+	KeyTab
+	KeyClear
+	KeyInterrupt
+)
+
+// Key is a single key press; Rune is set for KeyRune
+type Key struct {
+	Kind KeyKind
+	Rune rune
+}
+
+// ParseKeys decodes the bytes read from a terminal in raw mode into key presses
+func ParseKeys(input []byte) []Key {
+	var keys []Key
+	runes := []rune(string(input))
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, Key{Kind: KeyEnter})
+		case 3:
+			keys = append(keys, Key{Kind: KeyInterrupt})
+		case '\t':
+			keys = append(keys, Key{Kind: KeyTab})
+		case 127, 8:
+			keys = append(keys, Key{Kind: KeyBackspace})
+		case 21:
+			keys = append(keys, Key{Kind: KeyClear})
+		case 16, 11:
+			keys = append(keys, Key{Kind: KeyUp})
+		case 14:
+			keys = append(keys, Key{Kind: KeyDown})
+		case 27:
+			// Arrow keys arrive as ESC [ A or ESC O A; a lone ESC is the escape key
+			if i+2 < len(runes) && (runes[i+1] == '[' || runes[i+1] == 'O') {
+				if kind, ok := arrowKeys[runes[i+2]]; ok {
+					keys = append(keys, Key{Kind: kind})
+				}
+				i += 2
+				continue
+			}
+			keys = append(keys, Key{Kind: KeyEscape})
+		default:
+			keys = append(keys, Key{Kind: KeyRune, Rune: r})
+		}
+	}
+
+	return keys
+}
+
+var arrowKeys = map[rune]KeyKind{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+}
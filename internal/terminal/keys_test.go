@@ -0,0 +1,30 @@
+package terminal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	keys := ParseKeys([]byte("a\x1b[A\x1b[B\x1bOC\x1b[D\x7f\t\r\x15\x03\x1b"))
+
+	kinds := make([]KeyKind, len(keys))
+	for i, key := range keys {
+		kinds[i] = key.Kind
+	}
+
+	expected := []KeyKind{KeyRune, KeyUp, KeyDown, KeyRight, KeyLeft, KeyBackspace, KeyTab, KeyEnter, KeyClear, KeyInterrupt, KeyEscape}
+	if !slices.Equal(kinds, expected) {
+		t.Errorf("ParseKeys() = %v, expected %v", kinds, expected)
+	}
+	if keys[0].Rune != 'a' {
+		t.Errorf("Expected rune 'a', got %q", keys[0].Rune)
+	}
+}
+
+func TestParseKeys_Unicode(t *testing.T) {
+	keys := ParseKeys([]byte("é"))
+	if len(keys) != 1 || keys[0].Kind != KeyRune || keys[0].Rune != 'é' {
+		t.Errorf("Expected a single rune key, got %+v", keys)
+	}
+}
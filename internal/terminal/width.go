@@ -0,0 +1,86 @@
+package terminal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ansiSequence matches the colour and cursor escape sequences programs write to terminals
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// wideRanges are the rune ranges terminals draw two cells wide: CJK and the emoji used in output
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x2705, 0x2705},
+	{0x274C, 0x274C},
+	{0x2753, 0x2755},
+	{0x2E80, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F900, 0x1F9FF},
+}
+
+// RuneWidth returns the number of terminal cells a rune occupies
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0xFE0F || r == 0x200D || (r >= 0x0300 && r <= 0x036F):
+		// Variation selectors, joiners and combining marks modify the previous rune
+		return 0
+	case r < 0x20 || r == 0x7F:
+		return 0
+	}
+
+	for _, wide := range wideRanges {
+		if r >= wide[0] && r <= wide[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// Width returns the number of terminal cells s occupies
+func Width(s string) int {
+	width := 0
+	for _, r := range s {
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+// Truncate cuts s so it occupies at most width cells
+func Truncate(s string, width int) string {
+	used := 0
+	for i, r := range s {
+		w := RuneWidth(r)
+		if used+w > width {
+			return s[:i]
+		}
+		used += w
+	}
+	return s
+}
+
+// Fit truncates or pads s with spaces to occupy exactly width cells
+func Fit(s string, width int) string {
+	s = Truncate(s, width)
+	if pad := width - Width(s); pad > 0 {
+		s += strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// StripControl removes escape sequences, carriage returns and tabs that would break a redrawn screen
+func StripControl(s string) string {
+	s = ansiSequence.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "\t", "    ")
+	if i := strings.LastIndex(s, "\r"); i >= 0 {
+		// Progress output rewrites the line; only the last rewrite is visible
+		s = s[i+1:]
+	}
+	return s
+}
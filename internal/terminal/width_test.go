@@ -0,0 +1,58 @@
+package terminal
+
+import "testing"
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"main", 4},
+		{"✅  done", 8},
+		{"⚠️  warn", 7},
+		{"日本", 4},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := Width(tt.input); got != tt.expected {
+			t.Errorf("Width(%q) = %d, expected %d", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		input    string
+		width    int
+		expected string
+	}{
+		{"main", 6, "main  "},
+		{"feature/login", 7, "feature"},
+		{"✅ ok", 2, "✅"},
+		{"✅ ok", 1, " "},
+	}
+
+	for _, tt := range tests {
+		if got := Fit(tt.input, tt.width); got != tt.expected {
+			t.Errorf("Fit(%q, %d) = %q, expected %q", tt.input, tt.width, got, tt.expected)
+		}
+	}
+}
+
+func TestStripControl(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"\x1b[32mgreen\x1b[0m", "green"},
+		{"10%\r50%\r100%", "100%"},
+		{"a\tb", "a    b"},
+	}
+
+	for _, tt := range tests {
+		if got := StripControl(tt.input); got != tt.expected {
+			t.Errorf("StripControl(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package testutil holds the fixtures shared by tests that drive real git repositories
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// RunGit runs a git command in dir and fails the test on error, returning its trimmed output
+func RunGit(t testing.TB, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// SetupHome gives the test a fresh home directory, with worktree-manager in its usual place under it, and a git
+// identity to commit with. Tests are skipped when git is not installed
+func SetupHome(t testing.TB) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("WT_HOME", filepath.Join(home, ".worktree-manager"))
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	return home
+}
+
+// NewOriginRepo creates a bare origin with a README committed on main, and returns it along with the clone the
+// commit was pushed from, for tests to publish more work with
+func NewOriginRepo(t testing.TB) (origin, seed string) {
+	t.Helper()
+	origin = filepath.Join(t.TempDir(), "app.git")
+	seed = filepath.Join(t.TempDir(), "seed")
+	RunGit(t, "", "init", "--quiet", "--bare", "--initial-branch=main", origin)
+	RunGit(t, "", "clone", "--quiet", origin, seed)
+	if err := os.WriteFile(filepath.Join(seed, "README"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	RunGit(t, seed, "add", ".")
+	RunGit(t, seed, "commit", "--quiet", "-m", "Initial commit")
+	RunGit(t, seed, "push", "--quiet", "origin", "HEAD:main")
+	return origin, seed
+}
@@ -0,0 +1,303 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/worktree"
+)
+
+const (
+	// tickInterval drives the spinner and picks up terminal resizes
+	tickInterval = 100 * time.Millisecond
+	// liveRefreshInterval is how often worktree status is reloaded while the UI is idle
+	liveRefreshInterval = 5 * time.Second
+	// loadConcurrency limits how many repositories are loaded at once
+	loadConcurrency = 4
+)
+
+// repoLoadedMsg carries freshly collected worktrees for one repository
+type repoLoadedMsg struct {
+	alias string
+	infos []worktree.WorktreeInfo
+	err   error
+}
+
+// opDoneMsg reports the end of a background operation; then runs only if it succeeded
+type opDoneMsg struct {
+	label string
+	err   error
+	then  func()
+}
+
+type app struct {
+	cfg      *config.Config
+	appState *state.State
+	model    *model
+	log      *logBuffer
+	events   chan interface{}
+	out      io.Writer
+
+	reader        *keyReader
+	termState     *term.State
+	restoreOutput func()
+	lastFrame     string
+	pendingLoads  int
+	reloadQueued  bool
+}
+
+// Run shows the full-screen UI until the user quits
+func Run(cfg *config.Config, appState *state.State) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return errors.New("wt ui needs an interactive terminal")
+	}
+
+	a := &app{
+		cfg:      cfg,
+		appState: appState,
+		model:    newModel(appState),
+		log:      newLogBuffer(),
+		events:   make(chan interface{}, 16),
+		out:      os.Stdout,
+	}
+	return a.run()
+}
+
+func (a *app) run() error {
+	reader, err := newKeyReader()
+	if err != nil {
+		return err
+	}
+	a.reader = reader
+	defer reader.close()
+
+	if err := a.enter(); err != nil {
+		return err
+	}
+	defer a.leave()
+
+	go reader.run()
+	a.refresh()
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	live := time.NewTicker(liveRefreshInterval)
+	defer live.Stop()
+
+	for {
+		a.draw()
+
+		select {
+		case key := <-reader.keys:
+			if a.dispatch(a.model.handleKey(key)) {
+				return nil
+			}
+		case err := <-reader.errs:
+			return fmt.Errorf("failed to read from terminal: %w", err)
+		case event := <-a.events:
+			a.apply(event)
+		case <-a.log.notify:
+			for _, line := range a.log.take() {
+				a.model.appendLog(line)
+			}
+		case <-ticker.C:
+			if a.model.busy != "" {
+				a.model.spinner++
+			}
+		case <-live.C:
+			if a.model.busy == "" {
+				a.refresh()
+			}
+		}
+	}
+}
+
+// enter switches the terminal to raw mode on the alternate screen and captures all output in the log
+func (a *app) enter() error {
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to switch terminal to raw mode: %w", err)
+	}
+	a.termState = state
+
+	io.WriteString(a.out, "\x1b[?1049h\x1b[?25l")
+	a.restoreOutput = output.Redirect(a.log, a.log)
+	a.lastFrame = ""
+	return nil
+}
+
+// leave restores the terminal and output to how they were before enter
+func (a *app) leave() {
+	a.restoreOutput()
+	io.WriteString(a.out, "\x1b[?25h\x1b[?1049l")
+	term.Restore(int(os.Stdin.Fd()), a.termState)
+}
+
+func (a *app) draw() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	if width != a.model.width || height != a.model.height {
+		a.model.width, a.model.height = width, height
+		a.lastFrame = ""
+		io.WriteString(a.out, "\x1b[2J")
+	}
+
+	frame := "\x1b[H" + strings.Join(a.model.view(), "\r\n")
+	if frame != a.lastFrame {
+		io.WriteString(a.out, frame)
+		a.lastFrame = frame
+	}
+}
+
+// dispatch carries out an effect and reports whether the UI should quit
+func (a *app) dispatch(e effect) bool {
+	switch e.kind {
+	case effectQuit:
+		return true
+	case effectRefresh:
+		a.model.message = "Refreshing..."
+		a.refresh()
+	case effectAdd:
+		// The work-on script may need the terminal, so it runs in the foreground once the worktree exists
+		addCfg := *a.cfg
+		addCfg.AutomaticWorkOnAfterAdd = false
+		var then func()
+		if a.cfg.AutomaticWorkOnAfterAdd {
+			then = func() { a.dispatch(effect{kind: effectWorkon, repo: e.repo, branch: e.branch}) }
+		}
+		a.runAsync(fmt.Sprintf("Adding '%s' to %s", e.branch, e.repo), e.repo, func() error {
+			return worktree.AddWorktree(&addCfg, a.appState, e.branch)
+		}, then)
+	case effectRemove:
+		a.runAsync(fmt.Sprintf("Removing '%s' from %s", e.branch, e.repo), e.repo, func() error {
+			return worktree.RemoveWorktree(a.appState, e.branch, worktree.RemoveOptions{})
+		}, nil)
+	case effectSync:
+		a.runAsync(fmt.Sprintf("Syncing %s", e.repo), e.repo, func() error {
+			return worktree.SyncWorktrees(a.cfg, a.appState, worktree.SyncOptions{Repos: []string{e.repo}})
+		}, nil)
+	case effectPrune:
+		a.runAsync(fmt.Sprintf("Pruning %s", e.repo), e.repo, func() error {
+			return worktree.PruneWorktrees(a.appState)
+		}, nil)
+	case effectWorkon:
+		a.useRepo(e.repo)
+		a.runSuspended(fmt.Sprintf("Work on '%s'", e.branch), func() error {
+			return worktree.WorkOnWorktree(a.cfg, a.appState, e.branch)
+		})
+	case effectOpen:
+		a.useRepo(e.repo)
+		a.runSuspended(fmt.Sprintf("Open '%s'", e.branch), func() error {
+			return worktree.OpenWorktree(a.cfg, a.appState, e.branch, "")
+		})
+	}
+	return false
+}
+
+// useRepo makes a repository active, since the worktree operations act on the active repository
+func (a *app) useRepo(alias string) {
+	if a.appState.ActiveRepo != alias {
+		if err := a.appState.SetActiveRepo(alias); err != nil {
+			a.model.appendLog(fmt.Sprintf("❌  Failed to set active repository: %v", err))
+			return
+		}
+	}
+	a.model.activeRepo = alias
+}
+
+// runAsync runs a long operation in the background while the UI keeps responding
+func (a *app) runAsync(label, alias string, fn func() error, then func()) {
+	a.useRepo(alias)
+	a.model.busy = label
+
+	go func() {
+		err := fn()
+		a.events <- opDoneMsg{label: label, err: err, then: then}
+	}()
+}
+
+// runSuspended hands the terminal to an interactive operation such as a work-on script or an editor
+func (a *app) runSuspended(label string, fn func() error) {
+	a.reader.suspend()
+	a.leave()
+
+	err := fn()
+
+	if enterErr := a.enter(); enterErr != nil {
+		// Without raw mode the UI cannot continue; surface the failure in the log once it can
+		err = errors.Join(err, enterErr)
+	}
+	a.reader.resumeReading()
+
+	if err != nil {
+		a.model.appendLog(fmt.Sprintf("❌  %s failed: %v", label, err))
+		a.model.message = fmt.Sprintf("Failed: %s", label)
+	} else {
+		a.model.message = fmt.Sprintf("Done: %s", label)
+	}
+	a.refresh()
+}
+
+func (a *app) apply(event interface{}) {
+	switch msg := event.(type) {
+	case repoLoadedMsg:
+		a.model.setWorktrees(msg.alias, msg.infos, msg.err)
+		a.pendingLoads--
+		if a.pendingLoads == 0 {
+			if a.model.message == "Refreshing..." {
+				a.model.message = ""
+			}
+			if a.reloadQueued {
+				a.reloadQueued = false
+				a.refresh()
+			}
+		}
+	case opDoneMsg:
+		a.model.busy = ""
+		if msg.err != nil {
+			a.model.appendLog(fmt.Sprintf("❌  %v", msg.err))
+			a.model.message = fmt.Sprintf("Failed: %s", msg.label)
+		} else {
+			a.model.message = fmt.Sprintf("Done: %s", msg.label)
+		}
+		a.refresh()
+		if msg.err == nil && msg.then != nil {
+			msg.then()
+		}
+	}
+}
+
+// refresh reloads the worktrees of every repository in the background, or once more after the current reload
+func (a *app) refresh() {
+	if a.pendingLoads > 0 {
+		a.reloadQueued = true
+		return
+	}
+
+	repos := make([]state.Repo, len(a.model.repos))
+	for i, view := range a.model.repos {
+		repos[i] = view.repo
+	}
+	a.pendingLoads = len(repos)
+
+	semaphore := make(chan struct{}, loadConcurrency)
+	for _, repo := range repos {
+		go func(repo state.Repo) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			infos, err := worktree.RepoWorktrees(&repo)
+			a.events <- repoLoadedMsg{alias: repo.Alias, infos: infos, err: err}
+		}(repo)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !unix
+
+package tui
+
+import (
+	"errors"
+
+	"worktree-manager/internal/terminal"
+)
+
+type keyReader struct {
+	keys chan terminal.Key
+	errs chan error
+}
+
+func newKeyReader() (*keyReader, error) {
+	return nil, errors.New("wt ui is not supported on this platform")
+}
+
+func (r *keyReader) run()           {}
+func (r *keyReader) suspend()       {}
+func (r *keyReader) resumeReading() {}
+func (r *keyReader) close()         {}
@@ -0,0 +1,107 @@
+//go:build unix
+
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"worktree-manager/internal/terminal"
+)
+
+// pollInterval bounds how long a read blocks, so the reader notices pause and stop requests
+const pollInterval = 100 * time.Millisecond
+
+// keyReader reads keys from stdin on its own goroutine and can be paused while another program uses the terminal
+type keyReader struct {
+	fd     int
+	file   *os.File
+	keys   chan terminal.Key
+	errs   chan error
+	pause  chan struct{}
+	paused chan struct{}
+	resume chan struct{}
+	stop   chan struct{}
+}
+
+func newKeyReader() (*keyReader, error) {
+	fd := int(os.Stdin.Fd())
+
+	// A non-blocking descriptor lets reads time out instead of swallowing keys meant for a suspended program
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		return nil, fmt.Errorf("failed to configure terminal input: %w", err)
+	}
+
+	return &keyReader{
+		fd:     fd,
+		file:   os.NewFile(uintptr(fd), "stdin"),
+		keys:   make(chan terminal.Key, 64),
+		errs:   make(chan error, 1),
+		pause:  make(chan struct{}),
+		paused: make(chan struct{}),
+		resume: make(chan struct{}),
+		stop:   make(chan struct{}),
+	}, nil
+}
+
+func (r *keyReader) run() {
+	buf := make([]byte, 256)
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-r.pause:
+			r.paused <- struct{}{}
+			select {
+			case <-r.resume:
+			case <-r.stop:
+				return
+			}
+			continue
+		default:
+		}
+
+		r.file.SetReadDeadline(time.Now().Add(pollInterval))
+		n, err := r.file.Read(buf)
+
+		for _, key := range terminal.ParseKeys(buf[:n]) {
+			select {
+			case r.keys <- key:
+			case <-r.stop:
+				return
+			}
+		}
+
+		if err != nil && !errors.Is(err, os.ErrDeadlineExceeded) && !errors.Is(err, syscall.EAGAIN) {
+			r.errs <- err
+			return
+		}
+	}
+}
+
+// suspend stops reading and restores blocking input so a child process can use the terminal
+func (r *keyReader) suspend() {
+	for {
+		select {
+		case r.pause <- struct{}{}:
+			<-r.paused
+			syscall.SetNonblock(r.fd, false)
+			return
+		case <-r.keys:
+			// Keys typed while suspending are dropped rather than replayed into the UI
+		}
+	}
+}
+
+func (r *keyReader) resumeReading() {
+	syscall.SetNonblock(r.fd, true)
+	r.resume <- struct{}{}
+}
+
+func (r *keyReader) close() {
+	close(r.stop)
+	syscall.SetNonblock(r.fd, false)
+}
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"bytes"
+	"sync"
+
+	"worktree-manager/internal/output"
+)
+
+// logBuffer collects output written while the UI is running and hands complete lines to the UI loop
+type logBuffer struct {
+	mu     sync.Mutex
+	writer *output.LineWriter
+	lines  []string
+	notify chan struct{}
+}
+
+func newLogBuffer() *logBuffer {
+	l := &logBuffer{notify: make(chan struct{}, 1)}
+	l.writer = output.NewLineWriter(func(line []byte) error {
+		l.lines = append(l.lines, string(bytes.TrimRight(line, "\n")))
+		return nil
+	})
+	return l
+}
+
+func (l *logBuffer) Write(data []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.writer.Write(data)
+
+	// Wake the UI loop without blocking the writer
+	select {
+	case l.notify <- struct{}{}:
+	default:
+	}
+	return len(data), nil
+}
+
+// take returns the lines written since the last call
+func (l *logBuffer) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lines := l.lines
+	l.lines = nil
+	return lines
+}
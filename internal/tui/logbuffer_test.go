@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLogBuffer(t *testing.T) {
+	buf := newLogBuffer()
+
+	buf.Write([]byte("first\nsec"))
+	if lines := buf.take(); !slices.Equal(lines, []string{"first"}) {
+		t.Errorf("Expected only the complete line, got %q", lines)
+	}
+
+	select {
+	case <-buf.notify:
+	default:
+		t.Error("Expected a write to notify the UI")
+	}
+
+	buf.Write([]byte("ond\nthird\n"))
+	if lines := buf.take(); !slices.Equal(lines, []string{"second", "third"}) {
+		t.Errorf("Expected the buffered line to be completed, got %q", lines)
+	}
+	if lines := buf.take(); len(lines) != 0 {
+		t.Errorf("Expected nothing after take, got %q", lines)
+	}
+}
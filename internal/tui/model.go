@@ -0,0 +1,501 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/terminal"
+	"worktree-manager/internal/worktree"
+)
+
+// maxLogLines caps how much output the log pane keeps
+const maxLogLines = 500
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+type pane int
+
+const (
+	paneRepos pane = iota
+	paneWorktrees
+)
+
+type mode int
+
+const (
+	modeNormal mode = iota
+	modeInput
+	modeConfirm
+)
+
+type effectKind int
+
+const (
+	effectNone effectKind = iota
+	effectQuit
+	effectRefresh
+	effectAdd
+	effectRemove
+	effectWorkon
+	effectOpen
+	effectSync
+	effectPrune
+)
+
+// effect is an operation requested by a key press, carried out by the app outside the model
+type effect struct {
+	kind   effectKind
+	repo   string
+	branch string
+	path   string
+}
+
+// repoView is a repository with the worktrees last loaded for it
+type repoView struct {
+	repo      state.Repo
+	worktrees []worktree.WorktreeInfo
+	err       error
+	loaded    bool
+}
+
+// model holds everything the UI shows and changes only in response to keys and app messages
+type model struct {
+	repos      []repoView
+	activeRepo string
+	focus      pane
+	repoCursor int
+	treeCursor int
+
+	mode    mode
+	prompt  string
+	input   []rune
+	pending effect
+
+	busy    string
+	spinner int
+	message string
+	log     []string
+
+	width  int
+	height int
+}
+
+func newModel(appState *state.State) *model {
+	m := &model{activeRepo: appState.ActiveRepo}
+	for i, repo := range appState.Repos {
+		m.repos = append(m.repos, repoView{repo: repo})
+		if repo.Alias == appState.ActiveRepo {
+			m.repoCursor = i
+		}
+	}
+	return m
+}
+
+func (m *model) selectedRepo() *repoView {
+	if len(m.repos) == 0 {
+		return nil
+	}
+	return &m.repos[m.repoCursor]
+}
+
+func (m *model) selectedWorktree() *worktree.WorktreeInfo {
+	repo := m.selectedRepo()
+	if repo == nil || m.treeCursor >= len(repo.worktrees) {
+		return nil
+	}
+	return &repo.worktrees[m.treeCursor]
+}
+
+// setWorktrees replaces a repo's worktrees, keeping the cursor on the same branch where possible
+func (m *model) setWorktrees(alias string, infos []worktree.WorktreeInfo, err error) {
+	for i := range m.repos {
+		if m.repos[i].repo.Alias != alias {
+			continue
+		}
+
+		var selectedBranch string
+		if i == m.repoCursor {
+			if wt := m.selectedWorktree(); wt != nil {
+				selectedBranch = wt.Branch
+			}
+		}
+
+		m.repos[i].worktrees = infos
+		m.repos[i].err = err
+		m.repos[i].loaded = true
+
+		if i == m.repoCursor {
+			m.treeCursor = min(m.treeCursor, max(len(infos)-1, 0))
+			for j, info := range infos {
+				if selectedBranch != "" && info.Branch == selectedBranch {
+					m.treeCursor = j
+				}
+			}
+		}
+	}
+}
+
+func (m *model) appendLog(text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		m.log = append(m.log, terminal.StripControl(line))
+	}
+	if len(m.log) > maxLogLines {
+		m.log = m.log[len(m.log)-maxLogLines:]
+	}
+}
+
+func (m *model) moveCursor(delta int) {
+	switch m.focus {
+	case paneRepos:
+		if len(m.repos) == 0 {
+			return
+		}
+		previous := m.repoCursor
+		m.repoCursor = clamp(m.repoCursor+delta, 0, len(m.repos)-1)
+		if m.repoCursor != previous {
+			m.treeCursor = 0
+		}
+	case paneWorktrees:
+		repo := m.selectedRepo()
+		if repo == nil || len(repo.worktrees) == 0 {
+			return
+		}
+		m.treeCursor = clamp(m.treeCursor+delta, 0, len(repo.worktrees)-1)
+	}
+}
+
+// handleKey applies a key press and returns the operation it asks for, if any
+func (m *model) handleKey(key terminal.Key) effect {
+	switch m.mode {
+	case modeInput:
+		return m.handleInputKey(key)
+	case modeConfirm:
+		return m.handleConfirmKey(key)
+	}
+
+	m.message = ""
+
+	if key.Kind == terminal.KeyInterrupt {
+		return effect{kind: effectQuit}
+	}
+
+	switch key.Kind {
+	case terminal.KeyUp:
+		m.moveCursor(-1)
+		return effect{}
+	case terminal.KeyDown:
+		m.moveCursor(1)
+		return effect{}
+	case terminal.KeyLeft:
+		m.focus = paneRepos
+		return effect{}
+	case terminal.KeyRight:
+		m.focus = paneWorktrees
+		return effect{}
+	case terminal.KeyTab:
+		m.focus = 1 - m.focus
+		return effect{}
+	case terminal.KeyEnter:
+		if m.focus == paneRepos {
+			m.focus = paneWorktrees
+			return effect{}
+		}
+		return m.worktreeEffect(effectWorkon)
+	case terminal.KeyRune:
+	default:
+		return effect{}
+	}
+
+	switch key.Rune {
+	case 'k':
+		m.moveCursor(-1)
+	case 'j':
+		m.moveCursor(1)
+	case 'h':
+		m.focus = paneRepos
+	case 'l':
+		m.focus = paneWorktrees
+	case 'q':
+		if m.busy != "" {
+			m.message = fmt.Sprintf("Still busy: %s (ctrl-c to quit anyway)", m.busy)
+			return effect{}
+		}
+		return effect{kind: effectQuit}
+	case 'r':
+		return effect{kind: effectRefresh}
+	case 'a':
+		if repo := m.selectedRepo(); repo != nil && m.checkIdle() {
+			m.startInput("New worktree branch: ", effect{kind: effectAdd, repo: repo.repo.Alias})
+		}
+	case 'd':
+		if wt := m.selectedWorktree(); wt != nil && m.checkIdle() {
+			m.startConfirm(fmt.Sprintf("Remove worktree '%s'? (y/n)", wt.ShortBranch()), m.worktreeEffect(effectRemove))
+		}
+	case 'w':
+		return m.worktreeEffect(effectWorkon)
+	case 'o':
+		return m.worktreeEffect(effectOpen)
+	case 's':
+		if repo := m.selectedRepo(); repo != nil {
+			return m.idleEffect(effect{kind: effectSync, repo: repo.repo.Alias})
+		}
+	case 'p':
+		if repo := m.selectedRepo(); repo != nil {
+			return m.idleEffect(effect{kind: effectPrune, repo: repo.repo.Alias})
+		}
+	}
+
+	return effect{}
+}
+
+// worktreeEffect builds an effect for the selected worktree, or nothing if no worktree is selected
+func (m *model) worktreeEffect(kind effectKind) effect {
+	repo := m.selectedRepo()
+	wt := m.selectedWorktree()
+	if repo == nil || wt == nil {
+		m.message = "No worktree selected"
+		return effect{}
+	}
+	if wt.Branch == "" {
+		m.message = "Detached worktrees can only be managed with git directly"
+		return effect{}
+	}
+	return m.idleEffect(effect{kind: kind, repo: repo.repo.Alias, branch: wt.ShortBranch(), path: wt.Path})
+}
+
+// idleEffect passes the effect through unless another operation is still running
+func (m *model) idleEffect(e effect) effect {
+	if e.kind == effectNone || !m.checkIdle() {
+		return effect{}
+	}
+	return e
+}
+
+func (m *model) checkIdle() bool {
+	if m.busy != "" {
+		m.message = fmt.Sprintf("Busy: %s", m.busy)
+		return false
+	}
+	return true
+}
+
+func (m *model) startInput(prompt string, pending effect) {
+	m.mode = modeInput
+	m.prompt = prompt
+	m.input = nil
+	m.pending = pending
+}
+
+func (m *model) startConfirm(prompt string, pending effect) {
+	if pending.kind == effectNone {
+		return
+	}
+	m.mode = modeConfirm
+	m.prompt = prompt
+	m.pending = pending
+}
+
+func (m *model) handleInputKey(key terminal.Key) effect {
+	switch key.Kind {
+	case terminal.KeyEnter:
+		m.mode = modeNormal
+		branch := strings.TrimSpace(string(m.input))
+		if branch == "" {
+			return effect{}
+		}
+		e := m.pending
+		e.branch = branch
+		return e
+	case terminal.KeyEscape, terminal.KeyInterrupt:
+		m.mode = modeNormal
+		m.message = "Cancelled"
+	case terminal.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case terminal.KeyClear:
+		m.input = nil
+	case terminal.KeyRune:
+		if unicode.IsPrint(key.Rune) {
+			m.input = append(m.input, key.Rune)
+		}
+	}
+	return effect{}
+}
+
+func (m *model) handleConfirmKey(key terminal.Key) effect {
+	m.mode = modeNormal
+	if key.Kind == terminal.KeyRune && (key.Rune == 'y' || key.Rune == 'Y') {
+		return m.pending
+	}
+	m.message = "Cancelled"
+	return effect{}
+}
+
+// view renders the whole screen as exactly m.height lines of m.width cells
+func (m *model) view() []string {
+	width, height := max(m.width, 20), max(m.height, 8)
+
+	logHeight := 2
+	if height >= 20 {
+		logHeight = 6
+	}
+	bodyHeight := height - logHeight - 4
+
+	lines := []string{m.headerLine(width), strings.Repeat("─", width)}
+
+	leftWidth := clamp(m.reposWidth(), 12, width/3)
+	left := m.reposPane(bodyHeight)
+	right := m.worktreesPane(bodyHeight)
+	for i := 0; i < bodyHeight; i++ {
+		lines = append(lines, terminal.Fit(left[i], leftWidth)+"│"+terminal.Fit(right[i], width-leftWidth-1))
+	}
+
+	logTitle := "─ log "
+	lines = append(lines, logTitle+strings.Repeat("─", max(width-terminal.Width(logTitle), 0)))
+
+	start := max(len(m.log)-logHeight, 0)
+	for i := 0; i < logHeight; i++ {
+		line := ""
+		if start+i < len(m.log) {
+			line = " " + m.log[start+i]
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, m.footerLine())
+
+	for i := range lines {
+		lines[i] = terminal.Fit(lines[i], width)
+	}
+	return lines
+}
+
+func (m *model) headerLine(width int) string {
+	title := " wt ui"
+	if m.activeRepo != "" {
+		title += fmt.Sprintf("  (active: %s)", m.activeRepo)
+	}
+
+	status := m.message
+	if m.busy != "" {
+		status = fmt.Sprintf("%s %s", spinnerFrames[m.spinner%len(spinnerFrames)], m.busy)
+	}
+	if status == "" {
+		return title
+	}
+
+	gap := width - terminal.Width(title) - terminal.Width(status) - 1
+	if gap < 2 {
+		return title + "  " + status
+	}
+	return title + strings.Repeat(" ", gap) + status
+}
+
+func (m *model) footerLine() string {
+	switch m.mode {
+	case modeInput:
+		return " " + m.prompt + string(m.input) + "█"
+	case modeConfirm:
+		return " " + m.prompt
+	}
+	return " a add  d remove  w/enter workon  o open  s sync  p prune  r refresh  tab switch  q quit"
+}
+
+func (m *model) reposWidth() int {
+	width := len(" REPOS")
+	for _, repo := range m.repos {
+		width = max(width, terminal.Width(repo.repo.Alias)+6)
+	}
+	return width
+}
+
+func (m *model) reposPane(height int) []string {
+	lines := []string{" REPOS"}
+
+	if len(m.repos) == 0 {
+		lines = append(lines, " (none)", " wt repo clone", " to add one")
+	}
+
+	visible := height - 1
+	offset := scrollOffset(m.repoCursor, visible)
+	for i := offset; i < len(m.repos) && i < offset+visible; i++ {
+		cursor := "  "
+		if i == m.repoCursor {
+			cursor = cursorMarker(m.focus == paneRepos)
+		}
+		active := " "
+		if m.repos[i].repo.Alias == m.activeRepo {
+			active = "*"
+		}
+		lines = append(lines, cursor+active+" "+m.repos[i].repo.Alias)
+	}
+
+	return padLines(lines, height)
+}
+
+func (m *model) worktreesPane(height int) []string {
+	repo := m.selectedRepo()
+	switch {
+	case repo == nil:
+		return padLines(nil, height)
+	case !repo.loaded:
+		return padLines([]string{" Loading worktrees..."}, height)
+	case repo.err != nil:
+		return padLines([]string{" " + repo.err.Error()}, height)
+	case len(repo.worktrees) == 0:
+		return padLines([]string{" No worktrees. Press 'a' to add one."}, height)
+	}
+
+	rows := [][]string{{"", "BRANCH", "HEAD", "STATE", "UPSTREAM", "AGE", "SUBJECT", "FLAGS"}}
+	for _, info := range repo.worktrees {
+		rows = append(rows, worktree.FormatWorktreeRow(info, false))
+	}
+	table := output.AlignColumns(rows)
+
+	lines := []string{"   " + table[0]}
+
+	visible := height - 1
+	offset := scrollOffset(m.treeCursor, visible)
+	for i := offset; i < len(repo.worktrees) && i < offset+visible; i++ {
+		cursor := "  "
+		if i == m.treeCursor {
+			cursor = cursorMarker(m.focus == paneWorktrees)
+		}
+		lines = append(lines, " "+cursor+table[i+1])
+	}
+
+	return padLines(lines, height)
+}
+
+func cursorMarker(focused bool) string {
+	if focused {
+		return "▶ "
+	}
+	return "› "
+}
+
+// scrollOffset keeps the cursor within the visible window
+func scrollOffset(cursor, visible int) int {
+	if visible < 1 || cursor < visible {
+		return 0
+	}
+	return cursor - visible + 1
+}
+
+func padLines(lines []string, height int) []string {
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines[:height]
+}
+
+func clamp(v, low, high int) int {
+	if high < low {
+		return low
+	}
+	return min(max(v, low), high)
+}
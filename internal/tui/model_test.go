@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/terminal"
+	"worktree-manager/internal/worktree"
+)
+
+func testModel() *model {
+	appState := &state.State{
+		ActiveRepo: "web",
+		Repos: []state.Repo{
+			{Alias: "api", Dir: "/repos/api"},
+			{Alias: "web", Dir: "/repos/web"},
+		},
+	}
+
+	m := newModel(appState)
+	m.width, m.height = 100, 24
+	m.setWorktrees("api", nil, nil)
+	m.setWorktrees("web", []worktree.WorktreeInfo{
+		{Worktree: git.Worktree{Path: "/wt/web/feature-a", Branch: "refs/heads/feature-a"}},
+		{Worktree: git.Worktree{Path: "/wt/web/feature-b", Branch: "refs/heads/feature-b"}},
+	}, nil)
+	return m
+}
+
+func press(m *model, input string) effect {
+	var last effect
+	for _, key := range terminal.ParseKeys([]byte(input)) {
+		last = m.handleKey(key)
+	}
+	return last
+}
+
+func TestNewModel_StartsOnActiveRepo(t *testing.T) {
+	m := testModel()
+	if repo := m.selectedRepo(); repo == nil || repo.repo.Alias != "web" {
+		t.Fatalf("Expected the active repo to be selected, got %+v", repo)
+	}
+}
+
+func TestHandleKey_Navigation(t *testing.T) {
+	m := testModel()
+
+	press(m, "l")
+	press(m, "j")
+	if wt := m.selectedWorktree(); wt == nil || wt.ShortBranch() != "feature-b" {
+		t.Fatalf("Expected feature-b to be selected, got %+v", wt)
+	}
+
+	// Moving past the end stays on the last worktree
+	press(m, "jj")
+	if m.treeCursor != 1 {
+		t.Errorf("Expected cursor to stay on the last worktree, got %d", m.treeCursor)
+	}
+
+	// Switching repos resets the worktree cursor
+	press(m, "\t")
+	press(m, "k")
+	if m.selectedRepo().repo.Alias != "api" || m.treeCursor != 0 {
+		t.Errorf("Expected api with cursor reset, got %s at %d", m.selectedRepo().repo.Alias, m.treeCursor)
+	}
+	if m.selectedWorktree() != nil {
+		t.Error("Expected no worktree selected in a repo without worktrees")
+	}
+}
+
+func TestHandleKey_Effects(t *testing.T) {
+	m := testModel()
+	press(m, "l")
+
+	tests := []struct {
+		keys     string
+		expected effect
+	}{
+		{"w", effect{kind: effectWorkon, repo: "web", branch: "feature-a", path: "/wt/web/feature-a"}},
+		{"\r", effect{kind: effectWorkon, repo: "web", branch: "feature-a", path: "/wt/web/feature-a"}},
+		{"o", effect{kind: effectOpen, repo: "web", branch: "feature-a", path: "/wt/web/feature-a"}},
+		{"s", effect{kind: effectSync, repo: "web"}},
+		{"p", effect{kind: effectPrune, repo: "web"}},
+		{"r", effect{kind: effectRefresh}},
+		{"q", effect{kind: effectQuit}},
+	}
+
+	for _, tt := range tests {
+		if got := press(m, tt.keys); got != tt.expected {
+			t.Errorf("Keys %q gave %+v, expected %+v", tt.keys, got, tt.expected)
+		}
+	}
+}
+
+func TestHandleKey_AddPromptsForBranch(t *testing.T) {
+	m := testModel()
+
+	if e := press(m, "a"); e.kind != effectNone || m.mode != modeInput {
+		t.Fatalf("Expected 'a' to open the branch prompt, got %+v in mode %v", e, m.mode)
+	}
+
+	// The prompt takes keys that are bindings in normal mode
+	press(m, "fix-qx")
+	press(m, "\x7f")
+	e := press(m, "\r")
+
+	expected := effect{kind: effectAdd, repo: "web", branch: "fix-q"}
+	if e != expected || m.mode != modeNormal {
+		t.Errorf("Expected %+v back in normal mode, got %+v in mode %v", expected, e, m.mode)
+	}
+
+	press(m, "a")
+	if e := press(m, "abc\x1b"); e.kind != effectNone || m.mode != modeNormal {
+		t.Errorf("Expected escape to cancel the prompt, got %+v", e)
+	}
+}
+
+func TestHandleKey_RemoveNeedsConfirmation(t *testing.T) {
+	m := testModel()
+	press(m, "lj")
+
+	press(m, "d")
+	if m.mode != modeConfirm || !strings.Contains(m.prompt, "feature-b") {
+		t.Fatalf("Expected a confirmation prompt for feature-b, got %q", m.prompt)
+	}
+	if e := press(m, "n"); e.kind != effectNone {
+		t.Errorf("Expected 'n' to cancel, got %+v", e)
+	}
+
+	press(m, "d")
+	if e := press(m, "y"); e.kind != effectRemove || e.branch != "feature-b" {
+		t.Errorf("Expected removal of feature-b, got %+v", e)
+	}
+}
+
+func TestHandleKey_BusyBlocksOperations(t *testing.T) {
+	m := testModel()
+	press(m, "l")
+	m.busy = "Syncing web"
+
+	for _, keys := range []string{"s", "p", "w", "o", "d", "q"} {
+		if e := press(m, keys); e.kind != effectNone {
+			t.Errorf("Expected %q to be blocked while busy, got %+v", keys, e)
+		}
+	}
+	if m.mode != modeNormal {
+		t.Errorf("Expected no prompt while busy, got mode %v", m.mode)
+	}
+
+	if e := press(m, "\x03"); e.kind != effectQuit {
+		t.Errorf("Expected ctrl-c to quit even while busy, got %+v", e)
+	}
+}
+
+func TestSetWorktrees_KeepsSelectedBranch(t *testing.T) {
+	m := testModel()
+	press(m, "lj")
+
+	m.setWorktrees("web", []worktree.WorktreeInfo{
+		{Worktree: git.Worktree{Path: "/wt/web/aaa", Branch: "refs/heads/aaa"}},
+		{Worktree: git.Worktree{Path: "/wt/web/feature-a", Branch: "refs/heads/feature-a"}},
+		{Worktree: git.Worktree{Path: "/wt/web/feature-b", Branch: "refs/heads/feature-b"}},
+	}, nil)
+	if wt := m.selectedWorktree(); wt == nil || wt.ShortBranch() != "feature-b" {
+		t.Errorf("Expected feature-b to stay selected, got %+v", wt)
+	}
+
+	m.setWorktrees("web", nil, errors.New("not a git repository"))
+	if m.treeCursor != 0 || m.selectedWorktree() != nil {
+		t.Errorf("Expected the cursor to reset when the worktrees disappear, got %d", m.treeCursor)
+	}
+}
+
+func TestView(t *testing.T) {
+	m := testModel()
+	m.appendLog("✅  Worktree 'old' removed\n\x1b[32mcoloured\x1b[0m")
+
+	lines := m.view()
+	if len(lines) != m.height {
+		t.Fatalf("Expected %d lines, got %d", m.height, len(lines))
+	}
+	for i, line := range lines {
+		if w := terminal.Width(line); w != m.width {
+			t.Errorf("Line %d is %d cells wide, expected %d: %q", i, w, m.width, line)
+		}
+	}
+
+	screen := strings.Join(lines, "\n")
+	for _, expected := range []string{"* web", "feature-a", "feature-b", "Worktree 'old' removed", "coloured", "q quit"} {
+		if !strings.Contains(screen, expected) {
+			t.Errorf("Expected the screen to contain %q:\n%s", expected, screen)
+		}
+	}
+	if strings.Contains(screen, "\x1b") {
+		t.Error("Expected escape sequences to be stripped from the log")
+	}
+
+	m.busy = "Syncing web"
+	if header := m.view()[0]; !strings.Contains(header, "Syncing web") {
+		t.Errorf("Expected the busy operation in the header, got %q", header)
+	}
+}
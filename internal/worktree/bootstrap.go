@@ -0,0 +1,197 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/executors"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// States of a worktree's post-worktree-add hook, as shown by 'wt tree list'
+const (
+	BootstrapRunning = "bootstrapping"
+	BootstrapReady   = "ready"
+	BootstrapFailed  = "failed"
+)
+
+// bootstrapStartGrace is how long a background run may take to record its PID before it is considered failed
+const bootstrapStartGrace = time.Minute
+
+// bootstrapPollInterval is how often 'wt tree wait' checks on a running post-worktree-add hook
+const bootstrapPollInterval = 500 * time.Millisecond
+
+// BootstrapStatus records the run of a worktree's post-worktree-add hook, so that other wt processes can follow a run in the background
+type BootstrapStatus struct {
+	State    string     `json:"state"`
+	PID      int        `json:"pid,omitempty"`
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Log      string     `json:"log,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+func bootstrapStatusPath(repo *state.Repo, branch string) string {
+	return filepath.Join(consts.GetFilePaths().HookLogsDir(repo.Alias, branch), "bootstrap.json")
+}
+
+// ReadBootstrapStatus returns the post-worktree-add status of a worktree, if it was created since statuses are recorded.
+// A run whose process is gone without recording a result is reported as failed
+func ReadBootstrapStatus(repo *state.Repo, branch string) (BootstrapStatus, bool) {
+	var status BootstrapStatus
+	if err := fileops.ReadJSONFile(bootstrapStatusPath(repo, branch), &status); err != nil {
+		return BootstrapStatus{}, false
+	}
+
+	if status.State == BootstrapRunning {
+		switch {
+		case status.PID > 0 && !executors.ProcessAlive(status.PID):
+			status.State = BootstrapFailed
+			status.Error = "the post-worktree-add script stopped without recording a result"
+		case status.PID == 0 && time.Since(status.Started) > bootstrapStartGrace:
+			status.State = BootstrapFailed
+			status.Error = "the post-worktree-add script did not start in the background"
+		}
+	}
+	return status, true
+}
+
+// writeBootstrapStatus replaces the status file in one step, so readers never see it half written
+func writeBootstrapStatus(repo *state.Repo, branch string, status BootstrapStatus) error {
+	path := bootstrapStatusPath(repo, branch)
+	if err := fileops.EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := fileops.WriteJSONFile(tmp, status); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RunBootstrap runs the post-worktree-add hook of a worktree and records its progress and result
+func RunBootstrap(cfg *config.Config, appState *state.State, repo *state.Repo, branch, operation string) error {
+	status := BootstrapStatus{State: BootstrapRunning, PID: os.Getpid(), Started: time.Now()}
+	if err := writeBootstrapStatus(repo, branch, status); err != nil {
+		output.Warning("Failed to record the post-worktree-add status: %v", err)
+	}
+
+	logPath, err := runHook(cfg, appState, repo, branch, hookRun{
+		hook:        consts.HookPostWorktreeAdd,
+		scriptPath:  consts.GetFilePaths().PostWorktreeAddScript(repo.Alias),
+		workingDir:  consts.GetDirectoryPaths().RepoScriptsDir(repo.Alias),
+		progressMsg: "Executing post-worktree-add script: %s",
+		operation:   operation,
+		newWorktree: true,
+	})
+
+	finished := time.Now()
+	status.PID = 0
+	status.Finished = &finished
+	status.State = BootstrapReady
+	if fileops.FileExists(logPath) {
+		status.Log = logPath
+	}
+	if err != nil {
+		status.State = BootstrapFailed
+		status.Error = err.Error()
+	}
+	if writeErr := writeBootstrapStatus(repo, branch, status); writeErr != nil {
+		output.Warning("Failed to record the post-worktree-add status: %v", writeErr)
+	}
+	return err
+}
+
+// startBootstrap runs the post-worktree-add hook in a wt process of its own that outlives this one
+func startBootstrap(repo *state.Repo, branch, operation string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(executable, "tree", "bootstrap", branch, "--repo", repo.Alias, "--operation", operation)
+	executors.Detach(cmd)
+
+	// The background process records its own PID once it runs; until then the run counts as starting
+	if err := writeBootstrapStatus(repo, branch, BootstrapStatus{State: BootstrapRunning, Started: time.Now()}); err != nil {
+		return fmt.Errorf("failed to record the post-worktree-add status: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		_ = os.Remove(bootstrapStatusPath(repo, branch))
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// stopBootstrap stops a post-worktree-add hook still running for a worktree that is being removed, and forgets its status
+func stopBootstrap(repo *state.Repo, branch string) {
+	if status, ok := ReadBootstrapStatus(repo, branch); ok && status.State == BootstrapRunning && status.PID > 0 {
+		output.Warning("Stopping the post-worktree-add script still running for '%s'", branch)
+		if err := executors.TerminateProcess(status.PID); err != nil {
+			output.Warning("Failed to stop process %d: %v", status.PID, err)
+		}
+	}
+	_ = os.Remove(bootstrapStatusPath(repo, branch))
+}
+
+// WaitForBootstrap blocks until the post-worktree-add hook of a worktree in the active repository has finished, or
+// timeout has passed when it is not zero. It fails if the hook did
+func WaitForBootstrap(appState *state.State, branch string, timeout time.Duration) error {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	if err := validateWorktreeExists(getWorktreePath(activeRepo, branch), branch); err != nil {
+		return err
+	}
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		deadline = time.After(timeout)
+	}
+
+	announced := false
+	for {
+		status, ok := ReadBootstrapStatus(activeRepo, branch)
+		if !ok {
+			output.Info("No post-worktree-add script run recorded for '%s'", branch)
+			return nil
+		}
+
+		switch status.State {
+		case BootstrapReady:
+			output.Success("Worktree '%s' is ready", branch)
+			return nil
+		case BootstrapFailed:
+			return fmt.Errorf("post-worktree-add script for '%s' failed: %s\n\n💡 Use 'wt logs %s' to see its output", branch, status.Error, branch)
+		}
+
+		if !announced {
+			output.Progress("Waiting for the post-worktree-add script of '%s'...", branch)
+			announced = true
+		}
+
+		select {
+		case <-deadline:
+			return fmt.Errorf("timed out waiting for the post-worktree-add script of '%s'\n\n💡 It keeps running in the background; use 'wt logs %s' to see its output so far", branch, branch)
+		case <-time.After(bootstrapPollInterval):
+		}
+	}
+}
+
+// collectBootstrapStates fills in the post-worktree-add state of listed worktrees that have one recorded
+func collectBootstrapStates(repo *state.Repo, infos []WorktreeInfo) {
+	for i := range infos {
+		if status, ok := ReadBootstrapStatus(repo, infos[i].ShortBranch()); ok {
+			infos[i].Bootstrap = status.State
+		}
+	}
+}
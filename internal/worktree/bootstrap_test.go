@@ -0,0 +1,88 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/state"
+)
+
+func TestAddWorktree_RecordsBootstrapStatus(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	repo := &appState.Repos[0]
+
+	script := consts.GetFilePaths().PostWorktreeAddScript(repo.Alias)
+	os.MkdirAll(filepath.Dir(script), 0755)
+	os.WriteFile(script, []byte("[ \"$WT_BRANCH\" != broken ]\n"), 0755)
+
+	for branch, expected := range map[string]string{"feature": BootstrapReady, "broken": BootstrapFailed} {
+		if err := AddWorktree(&config.Config{}, appState, branch); err != nil {
+			t.Fatalf("AddWorktree(%s) failed: %v", branch, err)
+		}
+		status, ok := ReadBootstrapStatus(repo, branch)
+		if !ok || status.State != expected || status.Finished == nil {
+			t.Errorf("Expected %s to be %s, got %+v (found: %v)", branch, expected, status, ok)
+		}
+	}
+
+	if err := WaitForBootstrap(appState, "feature", 0); err != nil {
+		t.Errorf("Expected a finished bootstrap not to block, got %v", err)
+	}
+	if err := WaitForBootstrap(appState, "broken", 0); err == nil || !strings.Contains(err.Error(), "wt logs broken") {
+		t.Errorf("Expected the failure to point at the logs, got %v", err)
+	}
+
+	if err := RemoveWorktree(appState, "feature", RemoveOptions{}); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	if _, ok := ReadBootstrapStatus(repo, "feature"); ok {
+		t.Error("Expected the bootstrap status to be removed with the worktree")
+	}
+}
+
+func TestReadBootstrapStatus_LostRun(t *testing.T) {
+	t.Setenv("WT_HOME", t.TempDir())
+	repo := &state.Repo{Alias: "app"}
+
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Skip("true is not available")
+	}
+	writeBootstrapStatus(repo, "gone", BootstrapStatus{State: BootstrapRunning, PID: exited.Process.Pid, Started: time.Now()})
+	writeBootstrapStatus(repo, "never-started", BootstrapStatus{State: BootstrapRunning, Started: time.Now().Add(-2 * bootstrapStartGrace)})
+	writeBootstrapStatus(repo, "starting", BootstrapStatus{State: BootstrapRunning, Started: time.Now()})
+
+	for branch, expected := range map[string]string{"gone": BootstrapFailed, "never-started": BootstrapFailed, "starting": BootstrapRunning} {
+		if status, _ := ReadBootstrapStatus(repo, branch); status.State != expected {
+			t.Errorf("Expected %s to be %s, got %+v", branch, expected, status)
+		}
+	}
+}
+
+func TestWaitForBootstrap_BlocksUntilFinished(t *testing.T) {
+	t.Setenv("WT_HOME", t.TempDir())
+	repo := state.Repo{Alias: "app"}
+	appState := &state.State{ActiveRepo: "app", Repos: []state.Repo{repo}}
+	os.MkdirAll(getWorktreePath(&repo, "feature"), 0755)
+
+	writeBootstrapStatus(&repo, "feature", BootstrapStatus{State: BootstrapRunning, PID: os.Getpid(), Started: time.Now()})
+
+	if err := WaitForBootstrap(appState, "feature", 200*time.Millisecond); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Expected a timeout while the hook runs, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		finished := time.Now()
+		writeBootstrapStatus(&repo, "feature", BootstrapStatus{State: BootstrapReady, Started: time.Now(), Finished: &finished})
+	}()
+	if err := WaitForBootstrap(appState, "feature", 10*time.Second); err != nil {
+		t.Errorf("Expected the wait to end once the hook finished, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package worktree
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"worktree-manager/internal/compose"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// ComposeUp brings up the docker compose stack of a worktree of the active repository under the worktree's own
+// project name
+func ComposeUp(appState *state.State, branch string) error {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	worktreePath := getWorktreePath(activeRepo, branch)
+	if err := validateWorktreeExists(worktreePath, branch); err != nil {
+		return err
+	}
+	if compose.FindFile(worktreePath) == "" {
+		return fmt.Errorf("no compose file in %s (looked for %s)", worktreePath, strings.Join(compose.Files, ", "))
+	}
+	return composeUp(appState, activeRepo, branch, worktreePath)
+}
+
+// ComposeDown stops and removes the docker compose stack of a worktree of the active repository, and its volumes if
+// removeVolumes is set
+func ComposeDown(appState *state.State, branch string, removeVolumes bool) error {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	worktreePath := getWorktreePath(activeRepo, branch)
+	if err := validateWorktreeExists(worktreePath, branch); err != nil {
+		return err
+	}
+	return composeDown(appState, activeRepo, branch, worktreePath, removeVolumes)
+}
+
+func composeUp(appState *state.State, repo *state.Repo, branch, worktreePath string) error {
+	project := state.ComposeProjectName(repo.Alias, branch)
+	if err := compose.Up(worktreePath, project, worktreeVariables(appState, repo, branch, worktreePath)); err != nil {
+		return fmt.Errorf("failed to start compose project %s: %w", project, err)
+	}
+	recordComposeState(appState, repo, branch, project, state.ComposeUp)
+	output.Success("Compose project %s is up", project)
+	return nil
+}
+
+func composeDown(appState *state.State, repo *state.Repo, branch, worktreePath string, removeVolumes bool) error {
+	// The stack is stopped under the name it was started with, which may predate a rename of the repository
+	project := state.ComposeProjectName(repo.Alias, branch)
+	if meta, ok := appState.GetWorktreeMetadata(repo.Alias, branch); ok && meta.Compose != nil {
+		project = meta.Compose.Project
+	}
+
+	if err := compose.Down(worktreePath, project, worktreeVariables(appState, repo, branch, worktreePath), removeVolumes); err != nil {
+		return fmt.Errorf("failed to stop compose project %s: %w", project, err)
+	}
+	recordComposeState(appState, repo, branch, project, state.ComposeDown)
+	output.Success("Compose project %s is down", project)
+	return nil
+}
+
+func recordComposeState(appState *state.State, repo *state.Repo, branch, project, composeState string) {
+	if err := appState.UpdateWorktreeMetadata(repo.Alias, branch, func(meta *state.WorktreeMetadata) {
+		meta.Compose = &state.ComposeStatus{Project: project, State: composeState, Updated: time.Now()}
+	}); err != nil {
+		output.Warning("Failed to save the compose state: %v", err)
+	}
+}
+
+// autoComposeUp brings up a worktree's compose stack when compose-up is set in the config and it has a compose file
+func autoComposeUp(cfg *config.Config, appState *state.State, repo *state.Repo, branch string) {
+	worktreePath := getWorktreePath(repo, branch)
+	if !cfg.ComposeUp || compose.FindFile(worktreePath) == "" {
+		return
+	}
+	if err := composeUp(appState, repo, branch, worktreePath); err != nil {
+		output.Warning("%v", err)
+		output.Hint("Use 'wt tree up %s' to try again", branch)
+	}
+}
+
+// composeDownOnRemove tears down the compose stack of a worktree about to be removed if wt left it up. Its volumes
+// are kept unless removeVolumes is set
+func composeDownOnRemove(appState *state.State, repo *state.Repo, branch, worktreePath string, removeVolumes bool) {
+	meta, ok := appState.GetWorktreeMetadata(repo.Alias, branch)
+	if !ok || meta.Compose == nil || meta.Compose.State != state.ComposeUp {
+		return
+	}
+	if err := composeDown(appState, repo, branch, worktreePath, removeVolumes); err != nil {
+		output.Warning("%v", err)
+		output.Hint("Use 'docker compose --project-name %s down' to remove it by hand", meta.Compose.Project)
+	}
+}
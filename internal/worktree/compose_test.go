@@ -0,0 +1,124 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"worktree-manager/internal/compose"
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/executors"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/testutil"
+)
+
+func TestComposeStackFollowsWorktree(t *testing.T) {
+	appState, seed := setupTestRepo(t)
+	os.WriteFile(filepath.Join(seed, "compose.yaml"), []byte("services: {}\n"), 0644)
+	testutil.RunGit(t, seed, "add", ".")
+	testutil.RunGit(t, seed, "commit", "--quiet", "-m", "Add compose file")
+	testutil.RunGit(t, seed, "push", "--quiet", "origin", "HEAD:main")
+	testutil.RunGit(t, appState.Repos[0].Dir, "pull", "--quiet")
+
+	const branch, project = "feature/login", "app-feature-login"
+	upArgs := []string{"compose", "--project-name", project, "up", "--detach"}
+	downArgs := []string{"compose", "--project-name", project, "down", "--remove-orphans"}
+	replay := executors.NewReplayExecutor(
+		executors.Interaction{Command: "docker", Args: upArgs},
+		executors.Interaction{Command: "docker", Args: downArgs},
+	)
+	defer compose.SetDefaultExecutor(replay)()
+
+	cfg := &config.Config{ComposeUp: true}
+	if err := AddWorktree(cfg, appState, branch); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	repo, _ := appState.FindRepoByAlias("app")
+	worktreePath := getWorktreePath(repo, branch)
+
+	assertComposeState := func(expected string) {
+		t.Helper()
+		meta, _ := appState.GetWorktreeMetadata("app", branch)
+		if meta.Compose == nil || meta.Compose.Project != project || meta.Compose.State != expected {
+			t.Fatalf("Expected compose project %s to be %s, got %+v", project, expected, meta.Compose)
+		}
+	}
+	assertComposeState(state.ComposeUp)
+
+	if err := ComposeDown(appState, branch, false); err != nil {
+		t.Fatalf("ComposeDown failed: %v", err)
+	}
+	assertComposeState(state.ComposeDown)
+
+	// Working on the worktree brings the stack back up, and removing it tears it down, keeping its volumes
+	if err := WorkOnWorktree(cfg, appState, branch); err != nil {
+		t.Fatalf("WorkOnWorktree failed: %v", err)
+	}
+	assertComposeState(state.ComposeUp)
+	if err := RemoveWorktree(appState, branch, RemoveOptions{}); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	calls := replay.Calls()
+	if len(calls) != 4 || !slices.Equal(calls[3].Args, downArgs) {
+		t.Errorf("Expected up, down, up and down, got %v", calls)
+	}
+	for _, call := range calls {
+		if call.WorkingDir != worktreePath {
+			t.Errorf("Expected docker to run in %s, got %s", worktreePath, call.WorkingDir)
+		}
+	}
+}
+
+func TestRemoveWorktree_RemovesVolumesWhenAsked(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	replay := executors.NewReplayExecutor(executors.Interaction{
+		Command: "docker",
+		Args:    []string{"compose", "--project-name", "app-data", "down", "--remove-orphans", "--volumes"},
+	})
+	defer compose.SetDefaultExecutor(replay)()
+
+	if err := AddWorktree(&config.Config{}, appState, "data"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	recordComposeState(appState, &appState.Repos[0], "data", "app-data", state.ComposeUp)
+	if err := RemoveWorktree(appState, "data", RemoveOptions{Volumes: true}); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	if unused := replay.Unused(); len(unused) > 0 {
+		t.Errorf("Expected docker compose down --volumes, unused: %v", unused)
+	}
+}
+
+func TestComposeUp_RequiresComposeFile(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	replay := executors.NewReplayExecutor()
+	defer compose.SetDefaultExecutor(replay)()
+
+	if err := AddWorktree(&config.Config{ComposeUp: true}, appState, "plain"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	if err := ComposeUp(appState, "plain"); err == nil {
+		t.Error("Expected an error for a worktree without a compose file, got nil")
+	}
+	if calls := replay.Calls(); len(calls) != 0 {
+		t.Errorf("Expected docker not to run, got %v", calls)
+	}
+}
+
+func TestRemoveWorktree_LeavesStackThatIsDown(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	replay := executors.NewReplayExecutor()
+	defer compose.SetDefaultExecutor(replay)()
+
+	if err := AddWorktree(&config.Config{}, appState, "down"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	recordComposeState(appState, &appState.Repos[0], "down", "app-down", state.ComposeDown)
+	if err := RemoveWorktree(appState, "down", RemoveOptions{}); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	if calls := replay.Calls(); len(calls) != 0 {
+		t.Errorf("Expected docker not to run, got %v", calls)
+	}
+}
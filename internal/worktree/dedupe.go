@@ -0,0 +1,322 @@
+package worktree
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// How 'wt dedupe' shares the data of identical files: copy-on-write clones stay independent copies that can be
+// changed safely, hard links are one file that changes everywhere at once
+const (
+	DedupeReflink  = "reflink"
+	DedupeHardlink = "hardlink"
+)
+
+// DedupeModes lists the accepted values for the --mode flag
+var DedupeModes = []string{DedupeReflink, DedupeHardlink}
+
+// dedupeMinSize skips files that take up a block or so, too little to be worth linking
+const dedupeMinSize = 4096
+
+// hashConcurrency limits how many files are hashed at once
+const hashConcurrency = 8
+
+// dedupeTempPrefix names the link made next to a file before it replaces the file
+const dedupeTempPrefix = ".wt-dedupe-"
+
+// DedupeOptions selects the repositories 'wt dedupe' works on and how it links files
+type DedupeOptions struct {
+	Repos  []string
+	All    bool
+	Mode   string
+	DryRun bool
+}
+
+// DedupeResult sums up what deduplicating a repository did, or would do in a dry run
+type DedupeResult struct {
+	Files int
+	// Bytes is the space freed, or that would be freed
+	Bytes  int64
+	Failed []string
+}
+
+type dedupeFile struct {
+	path  string
+	info  fs.FileInfo
+	size  int64
+	id    fileops.FileID
+	links uint64
+	hash  string
+}
+
+// DedupeWorktrees links identical files in the dedupe-dirs of the worktrees, and the main checkout, of each selected
+// repository
+func DedupeWorktrees(cfg *config.Config, appState *state.State, opts DedupeOptions) error {
+	if opts.Mode == "" {
+		opts.Mode = DedupeReflink
+	}
+	if !slices.Contains(DedupeModes, opts.Mode) {
+		return fmt.Errorf("invalid mode '%s' (available: %s)", opts.Mode, strings.Join(DedupeModes, ", "))
+	}
+
+	dirs, err := cfg.GetDedupeDirs()
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("no directories to deduplicate\n\n💡 List them under 'dedupe-dirs' in the config, e.g. [\"node_modules\", \".venv\"], with 'wt config edit'")
+	}
+
+	repos, err := SelectRepos(appState, opts.Repos, opts.All)
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	failed := 0
+	for i := range repos {
+		repo := &repos[i]
+		output.Progress("Looking for identical files in %s of '%s'...", strings.Join(dirs, ", "), repo.Alias)
+
+		result, err := DedupeRepo(repo, dirs, opts.Mode, opts.DryRun)
+		if err != nil {
+			return fmt.Errorf("failed to deduplicate '%s': %w", repo.Alias, err)
+		}
+
+		switch {
+		case result.Files == 0 && len(result.Failed) == 0:
+			output.Info("No identical files left to link in '%s'", repo.Alias)
+		case opts.DryRun:
+			output.Info("Would %s %d files in '%s', freeing %s", linkVerb(opts.Mode), result.Files, repo.Alias, FormatBytes(result.Bytes))
+		case result.Files > 0:
+			output.Success("Linked %d files in '%s' with %ss, freeing %s", result.Files, repo.Alias, opts.Mode, FormatBytes(result.Bytes))
+		}
+		for _, failure := range result.Failed {
+			output.Warning("%s", failure)
+		}
+		if opts.Mode == DedupeReflink && result.Files == 0 && len(result.Failed) > 0 {
+			output.Hint("If the filesystem does not support copy-on-write clones, use --mode hardlink")
+		}
+		failed += len(result.Failed)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d files could not be deduplicated; they were left as they were", failed)
+	}
+	return nil
+}
+
+func linkVerb(mode string) string {
+	if mode == DedupeHardlink {
+		return "hard link"
+	}
+	return "clone"
+}
+
+// DedupeRepo replaces files in dirs of the worktrees of a repository with links to an identical file, preferring the
+// copy in the main checkout. Each link is checked against the hash of the file it replaces before taking its place
+func DedupeRepo(repo *state.Repo, dirs []string, mode string, dryRun bool) (DedupeResult, error) {
+	roots := []string{repo.Dir}
+	worktrees, err := listManagedWorktrees(repo)
+	if err != nil {
+		return DedupeResult{}, err
+	}
+	for _, wt := range worktrees {
+		if !wt.Prunable {
+			roots = append(roots, wt.Path)
+		}
+	}
+
+	var result DedupeResult
+	for _, group := range identicalFiles(collectDedupeFiles(roots, dirs)) {
+		canonical := group[0]
+		for _, file := range group[1:] {
+			if file.id == canonical.id {
+				continue
+			}
+			// Hard links share their permissions, so only files that already agree on them are linked
+			if mode == DedupeHardlink && file.info.Mode().Perm() != canonical.info.Mode().Perm() {
+				continue
+			}
+
+			if !dryRun {
+				if err := linkIdentical(canonical, file, mode); err != nil {
+					result.Failed = append(result.Failed, fmt.Sprintf("%s: %v", file.path, err))
+					continue
+				}
+			}
+			result.Files++
+			// Data still linked from elsewhere, such as a package manager's store, is not freed
+			if file.links == 1 {
+				result.Bytes += file.size
+			}
+		}
+	}
+	return result, nil
+}
+
+// collectDedupeFiles lists the regular files worth linking in dirs under each root, in order
+func collectDedupeFiles(roots, dirs []string) []*dedupeFile {
+	var files []*dedupeFile
+	for _, root := range roots {
+		for _, dir := range dirs {
+			filepath.WalkDir(filepath.Join(root, dir), func(path string, d fs.DirEntry, err error) error {
+				if err != nil || !d.Type().IsRegular() || strings.HasPrefix(d.Name(), dedupeTempPrefix) {
+					return nil
+				}
+				info, err := d.Info()
+				if err != nil || info.Size() < dedupeMinSize {
+					return nil
+				}
+				size, id, links := fileops.DiskUsage(info)
+				files = append(files, &dedupeFile{path: path, info: info, size: size, id: id, links: links})
+				return nil
+			})
+		}
+	}
+	return files
+}
+
+// identicalFiles groups files with the same content on the same device, keeping the order they were found in. Only
+// files that share their size with a file stored elsewhere are hashed
+func identicalFiles(files []*dedupeFile) [][]*dedupeFile {
+	type sizeKey struct {
+		dev  uint64
+		size int64
+	}
+	bySize := make(map[sizeKey][]*dedupeFile)
+	for _, file := range files {
+		key := sizeKey{file.id.Dev, file.info.Size()}
+		bySize[key] = append(bySize[key], file)
+	}
+
+	var toHash []*dedupeFile
+	for _, file := range files {
+		sameSize := bySize[sizeKey{file.id.Dev, file.info.Size()}]
+		if slices.ContainsFunc(sameSize, func(other *dedupeFile) bool { return other.id != file.id }) {
+			toHash = append(toHash, file)
+		}
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, hashConcurrency)
+	for _, file := range toHash {
+		wg.Add(1)
+		go func(file *dedupeFile) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			// Files that cannot be read keep an empty hash and are left alone
+			file.hash, _ = hashFile(file.path)
+		}(file)
+	}
+	wg.Wait()
+
+	type contentKey struct {
+		dev  uint64
+		size int64
+		hash string
+	}
+	var groups [][]*dedupeFile
+	index := make(map[contentKey]int)
+	for _, file := range toHash {
+		if file.hash == "" {
+			continue
+		}
+		key := contentKey{file.id.Dev, file.info.Size(), file.hash}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], file)
+	}
+	return slices.DeleteFunc(groups, func(group []*dedupeFile) bool { return len(group) < 2 })
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// linkIdentical replaces target with a link to canonical. The link is made next to target and only renamed over it
+// once neither file has changed since they were hashed and the link reads back with target's hash
+func linkIdentical(canonical, target *dedupeFile, mode string) error {
+	temp := filepath.Join(filepath.Dir(target.path), dedupeTempPrefix+filepath.Base(target.path))
+	os.Remove(temp)
+
+	var err error
+	if mode == DedupeHardlink {
+		err = os.Link(canonical.path, temp)
+	} else {
+		err = fileops.Reflink(canonical.path, temp)
+	}
+	if err != nil {
+		os.Remove(temp)
+		return err
+	}
+
+	if err := verifyLink(canonical, target, temp, mode); err != nil {
+		os.Remove(temp)
+		return err
+	}
+	if err := os.Rename(temp, target.path); err != nil {
+		os.Remove(temp)
+		return err
+	}
+	return nil
+}
+
+func verifyLink(canonical, target *dedupeFile, temp, mode string) error {
+	for _, file := range []*dedupeFile{canonical, target} {
+		info, err := os.Lstat(file.path)
+		if err != nil {
+			return err
+		}
+		if info.Size() != file.info.Size() || !info.ModTime().Equal(file.info.ModTime()) {
+			return fmt.Errorf("%s changed while deduplicating", file.path)
+		}
+	}
+
+	if mode == DedupeReflink {
+		// A clone is a file of its own, which keeps the permissions and times of the file it replaces
+		if err := os.Chmod(temp, target.info.Mode().Perm()); err != nil {
+			return err
+		}
+		if err := os.Chtimes(temp, target.info.ModTime(), target.info.ModTime()); err != nil {
+			return err
+		}
+	}
+
+	hash, err := hashFile(temp)
+	if err != nil {
+		return err
+	}
+	if hash != target.hash {
+		return fmt.Errorf("the linked copy does not match the original")
+	}
+	return nil
+}
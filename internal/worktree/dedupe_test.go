@@ -0,0 +1,115 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"worktree-manager/internal/config"
+)
+
+func TestDedupeRepo_Hardlink(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	cfg := &config.Config{}
+	for _, branch := range []string{"one", "two"} {
+		if err := AddWorktree(cfg, appState, branch); err != nil {
+			t.Fatalf("AddWorktree(%s) failed: %v", branch, err)
+		}
+	}
+	repo := &appState.Repos[0]
+
+	first := writeDeps(t, getWorktreePath(repo, "one"), "pkg/index.js", 64*1024, 'a')
+	second := writeDeps(t, getWorktreePath(repo, "two"), "pkg/index.js", 64*1024, 'a')
+	different := writeDeps(t, getWorktreePath(repo, "two"), "pkg/other.js", 64*1024, 'b')
+	writeDeps(t, getWorktreePath(repo, "two"), "pkg/small.js", 100, 'a')
+
+	dryRun, err := DedupeRepo(repo, []string{"node_modules"}, DedupeHardlink, true)
+	if err != nil {
+		t.Fatalf("DedupeRepo failed: %v", err)
+	}
+	if dryRun.Files != 1 || dryRun.Bytes < 64*1024 {
+		t.Errorf("Expected a dry run to find one file to link, got %+v", dryRun)
+	}
+	if sameFile(t, first, second) {
+		t.Fatal("Expected a dry run to leave the files alone")
+	}
+
+	result, err := DedupeRepo(repo, []string{"node_modules"}, DedupeHardlink, false)
+	if err != nil {
+		t.Fatalf("DedupeRepo failed: %v", err)
+	}
+	if result.Files != 1 || len(result.Failed) != 0 {
+		t.Errorf("Expected one file to be linked, got %+v", result)
+	}
+	if !sameFile(t, first, second) {
+		t.Error("Expected the identical files to be hard linked")
+	}
+	if sameFile(t, first, different) {
+		t.Error("Expected a file with other content to be left alone")
+	}
+
+	// Linked files are not linked again, and count once in the repository's disk usage
+	again, err := DedupeRepo(repo, []string{"node_modules"}, DedupeHardlink, false)
+	if err != nil || again.Files != 0 {
+		t.Errorf("Expected nothing left to link, got %+v, %v", again, err)
+	}
+	usage, err := CollectDiskUsage(appState, repo, DefaultStaleAfter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := usage.Worktrees[0].Bytes + usage.Worktrees[1].Bytes; usage.Bytes >= sum {
+		t.Errorf("Expected the total %d to count the linked file once, below %d", usage.Bytes, sum)
+	}
+}
+
+func TestDedupeRepo_Reflink(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	if err := AddWorktree(&config.Config{}, appState, "one"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	repo := &appState.Repos[0]
+	writeDeps(t, repo.Dir, "lib.js", 64*1024, 'a')
+	clone := writeDeps(t, getWorktreePath(repo, "one"), "lib.js", 64*1024, 'a')
+
+	// Whether or not the filesystem supports clones, the file keeps its content and no temporary link is left behind
+	result, err := DedupeRepo(repo, []string{"node_modules"}, DedupeReflink, false)
+	if err != nil {
+		t.Fatalf("DedupeRepo failed: %v", err)
+	}
+	if result.Files+len(result.Failed) != 1 {
+		t.Errorf("Expected one file to be cloned or to fail, got %+v", result)
+	}
+	if data, err := os.ReadFile(clone); err != nil || len(data) != 64*1024 || data[0] != 'a' {
+		t.Errorf("Expected the file to keep its content, got %d bytes, %v", len(data), err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(clone))
+	if len(entries) != 1 {
+		t.Errorf("Expected only lib.js to be left, got %v", entries)
+	}
+}
+
+func TestDedupeWorktrees_RequiresDirs(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	if err := DedupeWorktrees(&config.Config{}, appState, DedupeOptions{}); err == nil {
+		t.Error("Expected an error without dedupe-dirs, got nil")
+	}
+	if err := DedupeWorktrees(&config.Config{DedupeDirs: []string{"../shared"}}, appState, DedupeOptions{}); err == nil {
+		t.Error("Expected an error for a directory outside the worktree, got nil")
+	}
+	if err := DedupeWorktrees(&config.Config{DedupeDirs: []string{"node_modules"}}, appState, DedupeOptions{Mode: "copy"}); err == nil {
+		t.Error("Expected an error for an unknown mode, got nil")
+	}
+}
+
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+	infoA, err := os.Stat(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,282 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// duConcurrency limits how many worktrees are measured at once
+const duConcurrency = 4
+
+// largestDirsShown is how many of a worktree's top-level directories the breakdown lists
+const largestDirsShown = 3
+
+// staleWorktreesShown is how many stale worktrees are pointed out after the table
+const staleWorktreesShown = 3
+
+// DefaultStaleAfter is how long a worktree goes without being worked on before 'wt du' considers it stale
+const DefaultStaleAfter = 14 * 24 * time.Hour
+
+// DiskUsageOptions selects the repositories 'wt du' measures and how it reports them
+type DiskUsageOptions struct {
+	Repos      []string
+	All        bool
+	JSON       bool
+	StaleAfter time.Duration
+}
+
+// DirUsage is the disk usage of a top-level directory of a worktree
+type DirUsage struct {
+	Name  string `json:"name"`
+	Bytes int64  `json:"bytes"`
+}
+
+// WorktreeUsage is the disk usage of a worktree
+type WorktreeUsage struct {
+	Branch  string     `json:"branch"`
+	Path    string     `json:"path"`
+	Bytes   int64      `json:"bytes"`
+	Largest []DirUsage `json:"largest"`
+	// LastUsed is when the worktree was last worked on, or failing that when its directory last changed
+	LastUsed time.Time `json:"last-used"`
+	Stale    bool      `json:"stale"`
+	Error    string    `json:"error,omitempty"`
+
+	// linked holds the sizes of files with several hard links, so that the repository total counts them once
+	linked map[fileops.FileID]int64
+}
+
+// RepoUsage is the disk usage of the worktrees of a repository, largest first
+type RepoUsage struct {
+	Alias     string          `json:"alias"`
+	Worktrees []WorktreeUsage `json:"worktrees"`
+	// Bytes counts files hard linked between worktrees once
+	Bytes int64 `json:"bytes"`
+}
+
+// ShowDiskUsage prints the disk usage of the worktrees of the selected repositories
+func ShowDiskUsage(appState *state.State, opts DiskUsageOptions) error {
+	repos, err := SelectRepos(appState, opts.Repos, opts.All)
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+	if opts.StaleAfter <= 0 {
+		opts.StaleAfter = DefaultStaleAfter
+	}
+
+	usages := make([]RepoUsage, 0, len(repos))
+	for i := range repos {
+		usage, err := CollectDiskUsage(appState, &repos[i], opts.StaleAfter)
+		if err != nil {
+			return fmt.Errorf("failed to measure '%s': %w", repos[i].Alias, err)
+		}
+		usages = append(usages, usage)
+	}
+
+	if opts.JSON {
+		jsonOutput, err := json.MarshalIndent(usages, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonOutput))
+		return nil
+	}
+
+	for _, usage := range usages {
+		printDiskUsage(usage)
+	}
+	return nil
+}
+
+// CollectDiskUsage measures the managed worktrees of a repository concurrently
+func CollectDiskUsage(appState *state.State, repo *state.Repo, staleAfter time.Duration) (RepoUsage, error) {
+	worktrees, err := listManagedWorktrees(repo)
+	if err != nil {
+		return RepoUsage{}, err
+	}
+
+	usage := RepoUsage{Alias: repo.Alias, Worktrees: make([]WorktreeUsage, len(worktrees))}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, duConcurrency)
+
+	for i, wt := range worktrees {
+		usage.Worktrees[i] = WorktreeUsage{Branch: formatBranch(WorktreeInfo{Worktree: wt}), Path: wt.Path, Largest: []DirUsage{}}
+		if wt.Prunable {
+			usage.Worktrees[i].Error = "directory missing"
+			continue
+		}
+
+		wg.Add(1)
+		go func(wtUsage *WorktreeUsage) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			measureWorktree(wtUsage)
+		}(&usage.Worktrees[i])
+	}
+	wg.Wait()
+
+	linked := make(map[fileops.FileID]int64)
+	for i := range usage.Worktrees {
+		wtUsage := &usage.Worktrees[i]
+		if meta, ok := appState.GetWorktreeMetadata(repo.Alias, wtUsage.Branch); ok && meta.LastUsed != nil {
+			wtUsage.LastUsed = *meta.LastUsed
+		}
+		wtUsage.Stale = wtUsage.Error == "" && !wtUsage.LastUsed.IsZero() && time.Since(wtUsage.LastUsed) > staleAfter
+
+		usage.Bytes += wtUsage.Bytes
+		for id, size := range wtUsage.linked {
+			usage.Bytes -= size
+			linked[id] = size
+		}
+	}
+	for _, size := range linked {
+		usage.Bytes += size
+	}
+
+	slices.SortStableFunc(usage.Worktrees, func(a, b WorktreeUsage) int {
+		return compareBytes(b.Bytes, a.Bytes)
+	})
+	return usage, nil
+}
+
+// measureWorktree adds up the disk usage of a worktree and of each of its top-level entries. Files hard linked
+// within the worktree count once, and unreadable directories are skipped
+func measureWorktree(wtUsage *WorktreeUsage) {
+	root, err := os.Stat(wtUsage.Path)
+	if err != nil {
+		wtUsage.Error = err.Error()
+		return
+	}
+	wtUsage.LastUsed = root.ModTime()
+
+	topLevel := make(map[string]int64)
+	wtUsage.linked = make(map[fileops.FileID]int64)
+
+	filepath.WalkDir(wtUsage.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != wtUsage.Path {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		size, id, links := fileops.DiskUsage(info)
+		if links > 1 && !d.IsDir() {
+			if _, seen := wtUsage.linked[id]; seen {
+				return nil
+			}
+			wtUsage.linked[id] = size
+		}
+
+		wtUsage.Bytes += size
+		if rel, err := filepath.Rel(wtUsage.Path, path); err == nil && rel != "." {
+			name, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+			if d.IsDir() || name != rel {
+				topLevel[name] += size
+			}
+		}
+		return nil
+	})
+
+	for name, size := range topLevel {
+		wtUsage.Largest = append(wtUsage.Largest, DirUsage{Name: name, Bytes: size})
+	}
+	slices.SortFunc(wtUsage.Largest, func(a, b DirUsage) int {
+		if c := compareBytes(b.Bytes, a.Bytes); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	if len(wtUsage.Largest) > largestDirsShown {
+		wtUsage.Largest = wtUsage.Largest[:largestDirsShown]
+	}
+}
+
+func compareBytes(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// printDiskUsage displays one row per worktree, largest first, followed by the largest stale worktrees
+func printDiskUsage(usage RepoUsage) {
+	if len(usage.Worktrees) == 0 {
+		output.Info("No worktrees in '%s'", usage.Alias)
+		return
+	}
+	output.Info("Disk usage of %d worktrees of '%s': %s", len(usage.Worktrees), usage.Alias, FormatBytes(usage.Bytes))
+
+	rows := make([][]string, 0, len(usage.Worktrees))
+	var stale []WorktreeUsage
+	for _, wtUsage := range usage.Worktrees {
+		lastUsed := formatAge(wtUsage.LastUsed)
+		if wtUsage.Stale {
+			lastUsed += " (stale)"
+			stale = append(stale, wtUsage)
+		}
+
+		largest := make([]string, 0, len(wtUsage.Largest))
+		for _, dir := range wtUsage.Largest {
+			largest = append(largest, fmt.Sprintf("%s %s", dir.Name, FormatBytes(dir.Bytes)))
+		}
+		details := strings.Join(largest, ", ")
+		if wtUsage.Error != "" {
+			details = wtUsage.Error
+		}
+		if details == "" {
+			details = "-"
+		}
+
+		rows = append(rows, []string{wtUsage.Branch, FormatBytes(wtUsage.Bytes), lastUsed, details})
+	}
+	output.Table([]string{"BRANCH", "SIZE", "LAST USED", "LARGEST"}, rows)
+
+	if len(stale) == 0 {
+		return
+	}
+	fmt.Println()
+	output.Warning("Largest stale worktrees:")
+	for _, wtUsage := range stale[:min(len(stale), staleWorktreesShown)] {
+		output.Item("%s: %s, last used %s", wtUsage.Branch, FormatBytes(wtUsage.Bytes), formatAge(wtUsage.LastUsed))
+	}
+	output.Hint("Use 'wt tree remove <branch>' to reclaim their space")
+}
+
+// FormatBytes renders a size the way du -h does, e.g. 1.5G
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	value, suffix := float64(bytes)/unit, 0
+	for value >= unit && suffix < 4 {
+		value /= unit
+		suffix++
+	}
+	if value < 10 {
+		return fmt.Sprintf("%.1f%c", value, "KMGTP"[suffix])
+	}
+	return fmt.Sprintf("%.0f%c", value, "KMGTP"[suffix])
+}
@@ -0,0 +1,67 @@
+package worktree
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/state"
+)
+
+// writeDeps writes a file of size bytes under node_modules in a worktree
+func writeDeps(t *testing.T, worktreePath, name string, size int, fill byte) string {
+	t.Helper()
+	path := filepath.Join(worktreePath, "node_modules", name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes.Repeat([]byte{fill}, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCollectDiskUsage(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	cfg := &config.Config{}
+	for _, branch := range []string{"small", "big"} {
+		if err := AddWorktree(cfg, appState, branch); err != nil {
+			t.Fatalf("AddWorktree(%s) failed: %v", branch, err)
+		}
+	}
+	repo := &appState.Repos[0]
+	writeDeps(t, getWorktreePath(repo, "big"), "lib.js", 256*1024, 'a')
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	appState.UpdateWorktreeMetadata(repo.Alias, "big", func(meta *state.WorktreeMetadata) { meta.LastUsed = &old })
+
+	usage, err := CollectDiskUsage(appState, repo, DefaultStaleAfter)
+	if err != nil {
+		t.Fatalf("CollectDiskUsage failed: %v", err)
+	}
+	if len(usage.Worktrees) != 2 || usage.Worktrees[0].Branch != "big" {
+		t.Fatalf("Expected big to be listed first, got %+v", usage.Worktrees)
+	}
+
+	big, small := usage.Worktrees[0], usage.Worktrees[1]
+	if !big.Stale || small.Stale {
+		t.Errorf("Expected only big to be stale, got big=%v small=%v", big.Stale, small.Stale)
+	}
+	if len(big.Largest) == 0 || big.Largest[0].Name != "node_modules" || big.Largest[0].Bytes < 256*1024 {
+		t.Errorf("Expected node_modules to be the largest directory of big, got %+v", big.Largest)
+	}
+	if usage.Bytes != big.Bytes+small.Bytes {
+		t.Errorf("Expected the total %d to add up the worktrees, got %d", big.Bytes+small.Bytes, usage.Bytes)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for bytes, expected := range map[int64]string{512: "512B", 1536: "1.5K", 20 * 1024 * 1024: "20M", 3 << 30: "3.0G"} {
+		if got := FormatBytes(bytes); got != expected {
+			t.Errorf("FormatBytes(%d) = %s, expected %s", bytes, got, expected)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package worktree
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/executors"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/issues"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// Files wt writes into worktrees so that shells, direnv and tools such as docker compose see the worktree's environment
+const (
+	envFileName   = ".wt.env"
+	envrcFileName = ".envrc"
+)
+
+// generatedHeader starts the files wt writes into worktrees, telling them apart from files of the project's own
+const generatedHeader = "# Generated by wt; changes are overwritten."
+
+// envrcContent loads the environment file with direnv
+const envrcContent = generatedHeader + "\ndotenv_if_exists " + envFileName + "\n"
+
+// envTemplateData is what the values of a repository's env are rendered from
+type envTemplateData struct {
+	Alias    string
+	Branch   string
+	IssueKey string
+	Index    int
+	Ports    map[string]int
+}
+
+var envTemplateFuncs = template.FuncMap{
+	"slug":  issues.Slug,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// ValidateRepoEnv checks the names and templates of a repository's env
+func ValidateRepoEnv(repo *state.Repo) error {
+	if err := repo.Env.Validate(); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(repo.Env)) {
+		if _, err := parseEnvTemplate(name, repo.Env[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseEnvTemplate(name, value string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(envTemplateFuncs).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template for %s in env: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// repoEnv renders a repository's env for one of its worktrees; variables that fail to render are left out
+func repoEnv(repo *state.Repo, data envTemplateData) []string {
+	var env []string
+	for _, name := range slices.Sorted(maps.Keys(repo.Env)) {
+		tmpl, err := parseEnvTemplate(name, repo.Env[name])
+		if err != nil {
+			output.Warning("%v", err)
+			continue
+		}
+		var value strings.Builder
+		if err := tmpl.Execute(&value, data); err != nil {
+			output.Warning("Failed to render %s in env: %v", name, err)
+			continue
+		}
+		env = append(env, name+"="+value.String())
+	}
+	return env
+}
+
+// WorktreeEnvironment returns the variables of a worktree of the active repository as NAME=value pairs: the WT_*
+// variables hooks receive, without those describing a hook run, and the repository's own env
+func WorktreeEnvironment(appState *state.State, branch string) ([]string, error) {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return nil, fmt.Errorf("❌ %v", err)
+	}
+
+	worktreePath := getWorktreePath(activeRepo, branch)
+	if err := validateWorktreeExists(worktreePath, branch); err != nil {
+		return nil, err
+	}
+	return worktreeVariables(appState, activeRepo, branch, worktreePath), nil
+}
+
+func worktreeVariables(appState *state.State, repo *state.Repo, branch, worktreePath string) []string {
+	return append(executors.ScriptVariables(repo, worktreePath), worktreeEnv(appState, repo, branch)...)
+}
+
+// WriteEnvFiles writes the environment file of a worktree of the active repository, and the .envrc loading it when
+// direnv is set in the config
+func WriteEnvFiles(cfg *config.Config, appState *state.State, branch string) error {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	worktreePath := getWorktreePath(activeRepo, branch)
+	if err := validateWorktreeExists(worktreePath, branch); err != nil {
+		return err
+	}
+	return writeEnvFiles(appState, activeRepo, branch, worktreePath, cfg.Direnv)
+}
+
+func writeEnvFiles(appState *state.State, repo *state.Repo, branch, worktreePath string, direnv bool) error {
+	if err := writeGeneratedFile(filepath.Join(worktreePath, envFileName), formatEnvFile(worktreeVariables(appState, repo, branch, worktreePath))); err != nil {
+		return err
+	}
+	excluded := []string{envFileName}
+
+	if direnv {
+		envrcPath := filepath.Join(worktreePath, envrcFileName)
+		if fileops.FileExists(envrcPath) && !isGenerated(envrcPath) {
+			output.Warning("Leaving %s alone, it was not written by wt", envrcPath)
+			output.Hint("Add 'dotenv_if_exists %s' to it to load the worktree's environment", envFileName)
+		} else {
+			if err := writeGeneratedFile(envrcPath, envrcContent); err != nil {
+				return err
+			}
+			excluded = append(excluded, envrcFileName)
+		}
+	}
+
+	// Keep the files out of git status; info/exclude is shared by all worktrees of the repository
+	if err := excludeFromGit(repo, excluded); err != nil {
+		output.Warning("Failed to exclude %s from git: %v", strings.Join(excluded, " and "), err)
+	}
+	return nil
+}
+
+// refreshEnvFiles rewrites the files wt wrote into a worktree, after something they contain has changed
+func refreshEnvFiles(appState *state.State, repo *state.Repo, branch, worktreePath string) {
+	if !fileops.FileExists(filepath.Join(worktreePath, envFileName)) {
+		return
+	}
+	envrc := filepath.Join(worktreePath, envrcFileName)
+	if err := writeEnvFiles(appState, repo, branch, worktreePath, fileops.FileExists(envrc) && isGenerated(envrc)); err != nil {
+		output.Warning("Failed to update the environment file of '%s': %v", branch, err)
+	}
+}
+
+// RefreshRepoEnvFiles rewrites the environment files of every worktree of a repository that has one, such as after
+// its ports or env change or its worktrees move
+func RefreshRepoEnvFiles(appState *state.State, repo *state.Repo) {
+	infos, err := RepoWorktrees(repo)
+	if err != nil {
+		output.Warning("Failed to list the worktrees of %s: %v", repo.Alias, err)
+		return
+	}
+	for _, info := range infos {
+		refreshEnvFiles(appState, repo, info.ShortBranch(), info.Path)
+	}
+}
+
+func writeGeneratedFile(path, content string) error {
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func isGenerated(path string) bool {
+	content, err := os.ReadFile(path)
+	return err == nil && strings.HasPrefix(string(content), generatedHeader)
+}
+
+// formatEnvFile renders variables as NAME=value lines that shells, direnv's dotenv and docker compose all read
+func formatEnvFile(env []string) string {
+	var content strings.Builder
+	content.WriteString(generatedHeader + " Use 'wt env <branch> --write' to update it.\n")
+	for _, variable := range env {
+		name, value, _ := strings.Cut(variable, "=")
+		content.WriteString(name + "=" + ShellQuote(value) + "\n")
+	}
+	return content.String()
+}
+
+// FormatExports renders variables as export statements for a shell to eval
+func FormatExports(env []string) string {
+	var exports strings.Builder
+	for _, variable := range env {
+		name, value, _ := strings.Cut(variable, "=")
+		exports.WriteString("export " + name + "=" + ShellQuote(value) + "\n")
+	}
+	return exports.String()
+}
+
+// shellSafe matches values that need no quoting
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]+$`)
+
+// ShellQuote quotes a value for the shell with single quotes, unless it needs none
+func ShellQuote(value string) string {
+	if shellSafe.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// excludeFromGit adds root-level patterns for files to the repository's info/exclude, unless already there
+func excludeFromGit(repo *state.Repo, files []string) error {
+	excludePath, err := git.ExcludeFilePath(repo.Dir)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	var missing []string
+	for _, file := range files {
+		if pattern := "/" + file; !slices.Contains(lines, pattern) {
+			missing = append(missing, pattern)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	content = append(content, strings.Join(missing, "\n")+"\n"...)
+	if err := fileops.EnsureDir(filepath.Dir(excludePath)); err != nil {
+		return err
+	}
+	return os.WriteFile(excludePath, content, 0644)
+}
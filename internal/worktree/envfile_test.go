@@ -0,0 +1,99 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/testutil"
+)
+
+func TestAddWorktree_WritesEnvFiles(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	repo := &appState.Repos[0]
+	repo.Ports = state.PortSlots{"web": 3000}
+	repo.Env = state.EnvTemplates{
+		"DATABASE_URL": "postgres://localhost/app_{{.Branch | slug}}",
+		"APP_URL":      "http://localhost:{{.Ports.web}}",
+	}
+	cfg := &config.Config{EnvFile: true, Direnv: true}
+
+	if err := AddWorktree(cfg, appState, "feature/Login"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	worktreePath := getWorktreePath(repo, "feature/Login")
+
+	data, err := os.ReadFile(filepath.Join(worktreePath, envFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"WT_BRANCH=feature/Login\n",
+		"WT_PORT_WEB=3001\n",
+		"WT_WORKTREE_PATH=" + worktreePath + "\n",
+		"APP_URL=http://localhost:3001\n",
+		"DATABASE_URL=postgres://localhost/app_feature-login\n",
+	} {
+		if !strings.Contains(string(data), line) {
+			t.Errorf("Expected .wt.env to contain %q:\n%s", line, data)
+		}
+	}
+	if !isGenerated(filepath.Join(worktreePath, envrcFileName)) {
+		t.Error("Expected an .envrc written by wt")
+	}
+	if status := testutil.RunGit(t, worktreePath, "status", "--porcelain"); status != "" {
+		t.Errorf("Expected the generated files to be excluded from git, got status:\n%s", status)
+	}
+
+	// Changed ports reach the files of existing worktrees
+	repo.Ports["web"] = 4000
+	RefreshRepoEnvFiles(appState, repo)
+	if data, _ := os.ReadFile(filepath.Join(worktreePath, envFileName)); !strings.Contains(string(data), "WT_PORT_WEB=4001\n") {
+		t.Errorf("Expected the refreshed port in .wt.env:\n%s", data)
+	}
+}
+
+func TestWriteEnvFiles_KeepsProjectEnvrc(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	repo := &appState.Repos[0]
+	worktreePath := t.TempDir()
+	envrc := filepath.Join(worktreePath, envrcFileName)
+	if err := os.WriteFile(envrc, []byte("use nix\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeEnvFiles(appState, repo, "feature", worktreePath, true); err != nil {
+		t.Fatalf("writeEnvFiles failed: %v", err)
+	}
+	if data, _ := os.ReadFile(envrc); string(data) != "use nix\n" {
+		t.Errorf("Expected the project's .envrc to be left alone, got %q", data)
+	}
+}
+
+func TestFormatExports(t *testing.T) {
+	exports := FormatExports([]string{"PLAIN=a/b:c", "SPACED=hello world", "QUOTE=it's", "EMPTY="})
+	expected := "export PLAIN=a/b:c\nexport SPACED='hello world'\nexport QUOTE='it'\\''s'\nexport EMPTY=''\n"
+	if exports != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, exports)
+	}
+}
+
+func TestValidateRepoEnv(t *testing.T) {
+	tests := map[string]state.EnvTemplates{
+		"invalid name":     {"1X": "a"},
+		"reserved prefix":  {"WT_PORT": "a"},
+		"invalid template": {"DB": "{{.Branch"},
+		"unknown function": {"DB": "{{.Branch | kebab}}"},
+	}
+	for name, env := range tests {
+		if err := ValidateRepoEnv(&state.Repo{Env: env}); err == nil {
+			t.Errorf("%s: expected an error, got nil", name)
+		}
+	}
+	if err := ValidateRepoEnv(&state.Repo{Env: state.EnvTemplates{"DB": "app_{{.Branch | slug}}"}}); err != nil {
+		t.Errorf("Expected a valid env, got %v", err)
+	}
+}
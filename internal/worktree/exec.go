@@ -0,0 +1,231 @@
+package worktree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"worktree-manager/internal/executors"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// ExecOutputModes lists the accepted values for the --output flag of wt exec
+var ExecOutputModes = []string{"prefixed", "grouped"}
+
+// ExecOptions controls which worktrees a command runs in and how its output is shown
+type ExecOptions struct {
+	Repos    []string
+	AllRepos bool
+	Filter   string
+	Parallel int
+	Output   string
+}
+
+// ExecTarget is a worktree a command runs in
+type ExecTarget struct {
+	Repo state.Repo
+	Info WorktreeInfo
+	Env  []string
+}
+
+// Label identifies the target in prefixed output and the summary
+func (t ExecTarget) Label() string {
+	return fmt.Sprintf("%s/%s", t.Repo.Alias, formatBranch(t.Info))
+}
+
+// ExecResult records the outcome of running the command in one worktree
+type ExecResult struct {
+	Target   ExecTarget
+	ExitCode int
+	Duration time.Duration
+	Err      error
+}
+
+// ExecInWorktrees runs a command in every selected worktree and aggregates the exit status
+func ExecInWorktrees(appState *state.State, command []string, opts ExecOptions) error {
+	if len(command) == 0 {
+		return fmt.Errorf("no command specified")
+	}
+
+	if opts.Output == "" {
+		opts.Output = "prefixed"
+	}
+	if !slices.Contains(ExecOutputModes, opts.Output) {
+		return fmt.Errorf("invalid output mode '%s' (valid options: %s)", opts.Output, strings.Join(ExecOutputModes, ", "))
+	}
+
+	if opts.Parallel < 1 {
+		opts.Parallel = 1
+	}
+
+	targets, err := selectExecTargets(appState, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		output.Warning("No worktrees matched")
+		return nil
+	}
+
+	output.Progress("Running '%s' in %d worktree(s)...", strings.Join(command, " "), len(targets))
+
+	results := runInTargets(targets, command, opts)
+	PrintExecSummary(results)
+
+	failed := 0
+	for _, result := range results {
+		if result.ExitCode != 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("command failed in %d of %d worktree(s)", failed, len(results))
+	}
+	return nil
+}
+
+func selectExecTargets(appState *state.State, opts ExecOptions) ([]ExecTarget, error) {
+	repos, err := SelectRepos(appState, opts.Repos, opts.AllRepos)
+	if err != nil {
+		return nil, fmt.Errorf("❌ %v", err)
+	}
+
+	var targets []ExecTarget
+	for _, repo := range repos {
+		worktrees, err := listManagedWorktrees(&repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list worktrees for '%s': %w", repo.Alias, err)
+		}
+
+		var infos []WorktreeInfo
+		if slices.Contains(FilterOptions, opts.Filter) {
+			infos = CollectWorktreeInfo(worktrees)
+		} else {
+			// Branch globs don't need the status of each worktree
+			for _, wt := range worktrees {
+				infos = append(infos, WorktreeInfo{Worktree: wt})
+			}
+		}
+
+		infos, err = FilterWorktreeInfo(infos, opts.Filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, info := range infos {
+			if info.Prunable {
+				continue
+			}
+			targets = append(targets, ExecTarget{
+				Repo: repo,
+				Info: info,
+				Env:  worktreeEnv(appState, &repo, info.ShortBranch()),
+			})
+		}
+	}
+
+	return targets, nil
+}
+
+func runInTargets(targets []ExecTarget, command []string, opts ExecOptions) []ExecResult {
+	results := make([]ExecResult, len(targets))
+
+	var wg sync.WaitGroup
+	var outputMu sync.Mutex
+	semaphore := make(chan struct{}, opts.Parallel)
+
+	for i := range targets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = runInTarget(targets[i], command, opts.Output, &outputMu)
+		}(i)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func runInTarget(target ExecTarget, command []string, outputMode string, outputMu *sync.Mutex) ExecResult {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = target.Info.Path
+	cmd.Env = append(executors.BuildScriptEnvironment(&target.Repo, target.Info.Path), target.Env...)
+
+	var grouped bytes.Buffer
+	var stdout, stderr *output.PrefixWriter
+
+	if outputMode == "grouped" {
+		cmd.Stdout = &grouped
+		cmd.Stderr = &grouped
+	} else {
+		prefix := fmt.Sprintf("[%s] ", target.Label())
+		stdout = output.NewPrefixWriter(output.Stdout(), prefix, outputMu)
+		stderr = output.NewPrefixWriter(output.Stderr(), prefix, outputMu)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	result := ExecResult{
+		Target:   target,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.ExitCode = -1
+	}
+
+	if outputMode == "grouped" {
+		outputMu.Lock()
+		output.Info("%s", target.Label())
+		output.Stdout().Write(grouped.Bytes())
+		outputMu.Unlock()
+	} else {
+		stdout.Flush()
+		stderr.Flush()
+	}
+
+	return result
+}
+
+// PrintExecSummary displays the exit status of the command in each worktree
+func PrintExecSummary(results []ExecResult) {
+	output.Info("Exec summary:")
+
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		status := "ok"
+		if result.ExitCode != 0 {
+			status = fmt.Sprintf("exit %d", result.ExitCode)
+		}
+		if result.ExitCode == -1 && result.Err != nil {
+			status = result.Err.Error()
+		}
+
+		rows = append(rows, []string{
+			result.Target.Repo.Alias,
+			formatBranch(result.Target.Info),
+			status,
+			result.Duration.Round(time.Millisecond).String(),
+		})
+	}
+
+	output.Table([]string{"REPO", "BRANCH", "STATUS", "DURATION"}, rows)
+}
@@ -0,0 +1,37 @@
+package worktree
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+)
+
+func TestExecInWorktrees_WritesThroughOutput(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	if err := AddWorktree(&config.Config{}, appState, "one"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+
+	for _, mode := range ExecOutputModes {
+		var stdout, stderr bytes.Buffer
+		restore := output.Redirect(&stdout, &stderr)
+		err := ExecInWorktrees(appState, []string{"sh", "-c", "echo out; echo err >&2"}, ExecOptions{Output: mode})
+		restore()
+		if err != nil {
+			t.Fatalf("ExecInWorktrees(%s) failed: %v", mode, err)
+		}
+
+		captured := stdout.String() + stderr.String()
+		for _, line := range []string{"out", "err"} {
+			if !strings.Contains(captured, line+"\n") {
+				t.Errorf("Expected %s output to capture %q, got %q", mode, line, captured)
+			}
+		}
+		if mode == "prefixed" && !strings.Contains(stderr.String(), "[app/one] err\n") {
+			t.Errorf("Expected prefixed stderr to go to the redirected stderr, got %q", stderr.String())
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// applyFileRules brings the untracked files matched by the repo's rules from the main checkout into a worktree
+func applyFileRules(repo *state.Repo, worktreePath string, overwrite bool) error {
+	if repo.Files.IsEmpty() {
+		return nil
+	}
+
+	actions := []struct {
+		patterns []string
+		verb     string
+		apply    func(src, dst string) error
+	}{
+		{repo.Files.Copy, "Copied", copyEntry(fileops.CopyFile)},
+		{repo.Files.CloneOnWrite, "Cloned", copyEntry(fileops.CloneFile)},
+		{repo.Files.Symlink, "Linked", symlinkEntry},
+	}
+
+	for _, action := range actions {
+		for _, pattern := range action.patterns {
+			matches, err := filepath.Glob(filepath.Join(repo.Dir, pattern))
+			if err != nil {
+				return fmt.Errorf("invalid file pattern '%s': %w", pattern, err)
+			}
+
+			for _, src := range matches {
+				rel, err := filepath.Rel(repo.Dir, src)
+				if err != nil {
+					return err
+				}
+				dst := filepath.Join(worktreePath, rel)
+
+				if _, err := os.Lstat(dst); err == nil {
+					if !overwrite {
+						continue
+					}
+					if err := os.RemoveAll(dst); err != nil {
+						return fmt.Errorf("failed to replace %s: %w", dst, err)
+					}
+				}
+
+				if err := fileops.EnsureDir(filepath.Dir(dst)); err != nil {
+					return err
+				}
+
+				if err := action.apply(src, dst); err != nil {
+					return fmt.Errorf("failed to bring %s into worktree: %w", rel, err)
+				}
+				output.Item("%s %s", action.verb, rel)
+			}
+		}
+	}
+
+	return nil
+}
+
+func copyEntry(copyFn func(src, dst string) error) func(src, dst string) error {
+	return func(src, dst string) error {
+		return fileops.CopyTree(src, dst, copyFn)
+	}
+}
+
+func symlinkEntry(src, dst string) error {
+	return os.Symlink(src, dst)
+}
+
+// RefreshWorktreeFiles re-applies the repo's copy, symlink and clone-on-write rules to an existing worktree
+func RefreshWorktreeFiles(appState *state.State, branch string) error {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	worktreePath := getWorktreePath(activeRepo, branch)
+
+	if err := validateWorktreeExists(worktreePath, branch); err != nil {
+		return err
+	}
+
+	if activeRepo.Files.IsEmpty() {
+		output.Warning("No file rules configured for repository '%s'", activeRepo.Alias)
+		output.Hint("Add copy, symlink or clone-on-write patterns under 'files' for the repository in %s", consts.GetFilePaths().State)
+		return nil
+	}
+
+	output.Progress("Refreshing files in worktree '%s'...", branch)
+
+	if err := applyFileRules(activeRepo, worktreePath, true); err != nil {
+		return err
+	}
+
+	output.Success("Files refreshed for worktree '%s'", branch)
+	return nil
+}
@@ -0,0 +1,58 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"worktree-manager/internal/state"
+)
+
+func TestApplyFileRules(t *testing.T) {
+	repoDir := t.TempDir()
+	worktreePath := t.TempDir()
+
+	os.WriteFile(filepath.Join(repoDir, ".env"), []byte("SECRET=1"), 0644)
+	os.WriteFile(filepath.Join(repoDir, ".env.local"), []byte("LOCAL=1"), 0644)
+	os.MkdirAll(filepath.Join(repoDir, ".idea"), 0755)
+	os.WriteFile(filepath.Join(repoDir, ".idea", "workspace.xml"), []byte("<xml/>"), 0644)
+	os.MkdirAll(filepath.Join(repoDir, "node_modules", "pkg"), 0755)
+	os.WriteFile(filepath.Join(repoDir, "node_modules", "pkg", "index.js"), []byte("js"), 0644)
+
+	// An existing file in the worktree is kept unless overwriting
+	os.WriteFile(filepath.Join(worktreePath, ".env.local"), []byte("KEEP=1"), 0644)
+
+	repo := &state.Repo{
+		Alias: "app",
+		Dir:   repoDir,
+		Files: &state.FileRules{
+			Copy:         []string{".env*"},
+			Symlink:      []string{".idea"},
+			CloneOnWrite: []string{"node_modules"},
+		},
+	}
+
+	if err := applyFileRules(repo, worktreePath, false); err != nil {
+		t.Fatalf("applyFileRules failed: %v", err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(worktreePath, ".env")); string(data) != "SECRET=1" {
+		t.Errorf("Expected .env to be copied, got %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktreePath, ".env.local")); string(data) != "KEEP=1" {
+		t.Errorf("Expected existing .env.local to be kept, got %q", data)
+	}
+	if link, err := os.Readlink(filepath.Join(worktreePath, ".idea")); err != nil || link != filepath.Join(repoDir, ".idea") {
+		t.Errorf("Expected .idea to be symlinked to the main checkout, got %q (err: %v)", link, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktreePath, "node_modules", "pkg", "index.js")); string(data) != "js" {
+		t.Errorf("Expected node_modules to be cloned, got %q", data)
+	}
+
+	if err := applyFileRules(repo, worktreePath, true); err != nil {
+		t.Fatalf("applyFileRules with overwrite failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktreePath, ".env.local")); string(data) != "LOCAL=1" {
+		t.Errorf("Expected .env.local to be overwritten, got %q", data)
+	}
+}
@@ -0,0 +1,155 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/forge"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// PullRequestOptions describes the pull request opened by 'wt pr open'
+type PullRequestOptions struct {
+	Title string
+	Body  string
+	Base  string
+	Draft bool
+}
+
+func providerForRepo(cfg *config.Config, repo *state.Repo) (forge.Provider, error) {
+	remoteURL, err := git.GetRemoteURL(repo.Dir)
+	if err != nil {
+		return nil, err
+	}
+	return forge.ProviderForRemote(remoteURL, cfg.Forges)
+}
+
+// collectPullRequests looks up the pull request of each worktree's branch concurrently
+func collectPullRequests(provider forge.Provider, infos []WorktreeInfo) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, statusConcurrency)
+
+	for i := range infos {
+		if infos[i].Branch == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(info *WorktreeInfo) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			info.PullRequest, info.ForgeErr = provider.FindPullRequest(context.Background(), info.ShortBranch())
+		}(&infos[i])
+	}
+
+	wg.Wait()
+}
+
+func formatPullRequest(info WorktreeInfo) string {
+	switch {
+	case info.ForgeErr != nil:
+		return "error"
+	case info.PullRequest == nil:
+		return "-"
+	case info.PullRequest.Draft && info.PullRequest.State == forge.StateOpen:
+		return fmt.Sprintf("#%d draft", info.PullRequest.Number)
+	default:
+		return fmt.Sprintf("#%d %s", info.PullRequest.Number, info.PullRequest.State)
+	}
+}
+
+func formatForgeField(info WorktreeInfo, value func(pr *forge.PullRequest) string) string {
+	if info.PullRequest == nil || value(info.PullRequest) == "" {
+		return "-"
+	}
+	return value(info.PullRequest)
+}
+
+// suggestMergedPrunes hints at removing worktrees whose pull request has been merged
+func suggestMergedPrunes(infos []WorktreeInfo) {
+	for _, info := range infos {
+		if info.PullRequest != nil && info.PullRequest.IsMerged() {
+			output.Hint("Pull request #%d for '%s' was merged; remove its worktree with 'wt tree remove %s'", info.PullRequest.Number, info.ShortBranch(), info.ShortBranch())
+		}
+	}
+
+	for _, info := range infos {
+		if info.ForgeErr != nil {
+			output.Warning("Could not fetch pull request for '%s': %v", info.ShortBranch(), info.ForgeErr)
+			return
+		}
+	}
+}
+
+// OpenPullRequest pushes a worktree's branch and opens a pull request for it
+func OpenPullRequest(cfg *config.Config, appState *state.State, branch string, opts PullRequestOptions) error {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	worktreePath := getWorktreePath(activeRepo, branch)
+
+	if err := validateWorktreeExists(worktreePath, branch); err != nil {
+		return err
+	}
+
+	provider, err := providerForRepo(cfg, activeRepo)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	existing, err := provider.FindPullRequest(ctx, branch)
+	if err != nil {
+		return fmt.Errorf("failed to look up existing pull requests: %w", err)
+	}
+	if existing != nil && existing.State == forge.StateOpen {
+		output.Info("Pull request #%d is already open for '%s': %s", existing.Number, branch, existing.URL)
+		return nil
+	}
+
+	if opts.Title == "" {
+		status, err := git.GetWorktreeStatus(worktreePath)
+		if err != nil {
+			return err
+		}
+		opts.Title = status.LastCommitSubject
+	}
+
+	if opts.Base == "" {
+		baseBranch, err := repoBaseBranch(activeRepo)
+		if err != nil {
+			return fmt.Errorf("failed to determine base branch: %w", err)
+		}
+		opts.Base = strings.TrimPrefix(baseBranch, "origin/")
+	}
+
+	output.Progress("Pushing '%s' to origin...", branch)
+	if err := git.PushBranch(worktreePath, branch); err != nil {
+		return fmt.Errorf("failed to push branch: %w", err)
+	}
+
+	output.Progress("Opening pull request on %s...", provider.Name())
+	pr, err := provider.CreatePullRequest(ctx, forge.CreatePullRequestOptions{
+		Title: opts.Title,
+		Body:  opts.Body,
+		Head:  branch,
+		Base:  opts.Base,
+		Draft: opts.Draft,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	output.Success("Opened pull request #%d: %s", pr.Number, pr.URL)
+	return nil
+}
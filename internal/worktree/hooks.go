@@ -0,0 +1,230 @@
+package worktree
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/executors"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// Operations that run hooks, passed to them as WT_OPERATION
+const (
+	operationAdd         = "add"
+	operationPullRequest = "pull-request"
+	operationWorkOn      = "work-on"
+)
+
+// hookLogTimeLayout timestamps the names of hook log files
+const hookLogTimeLayout = "20060102-150405"
+
+// maxHookLogs is how many logs of each hook are kept for a worktree
+const maxHookLogs = 10
+
+// hookRun describes a hook about to run for a worktree
+type hookRun struct {
+	hook        string
+	scriptPath  string
+	workingDir  string
+	progressMsg string
+	operation   string
+	newWorktree bool
+}
+
+// runHook runs the commands declared for a hook in the config and the repository's state, then its script, with the
+// hook's timeout covering them all. Their output is logged to the worktree's logs directory, whose path is returned
+func runHook(cfg *config.Config, appState *state.State, repo *state.Repo, branch string, run hookRun) (string, error) {
+	timeout, err := cfg.GetHookTimeout(run.hook)
+	if err != nil {
+		output.Warning("%v", err)
+	}
+	deadline := time.Now().Add(timeout)
+
+	worktreePath := getWorktreePath(repo, branch)
+	logsDir := consts.GetFilePaths().HookLogsDir(repo.Alias, branch)
+	logPath := filepath.Join(logsDir, fmt.Sprintf("%s-%s.log", run.hook, time.Now().Format(hookLogTimeLayout)))
+	defer pruneHookLogs(logsDir, run.hook)
+
+	env := hookEnv(appState, repo, branch, run.operation, run.newWorktree)
+	execCtx := func() (*executors.ScriptExecutionContext, error) {
+		remaining := time.Duration(0)
+		if timeout > 0 {
+			if remaining = time.Until(deadline); remaining <= 0 {
+				return nil, fmt.Errorf("timed out after %s", timeout)
+			}
+		}
+		return &executors.ScriptExecutionContext{
+			Repo:         repo,
+			WorktreePath: worktreePath,
+			ExtraEnv:     env,
+			Hook:         run.hook,
+			Timeout:      remaining,
+			LogPath:      logPath,
+		}, nil
+	}
+
+	commands := append(append([]state.HookCommand{}, cfg.HookCommands[run.hook]...), repo.Hooks[run.hook]...)
+	for _, command := range commands {
+		if !command.If.Matches(worktreePath, branch, run.operation) {
+			continue
+		}
+		ctx, err := execCtx()
+		if err != nil {
+			return logPath, err
+		}
+		ctx.Command = command.Run
+		ctx.WorkingDir = worktreePath
+		if command.Dir != "" {
+			ctx.WorkingDir = command.Dir
+			if !filepath.IsAbs(command.Dir) {
+				ctx.WorkingDir = filepath.Join(worktreePath, command.Dir)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(command.Env)) {
+			ctx.ExtraEnv = append(slices.Clip(ctx.ExtraEnv), name+"="+command.Env[name])
+		}
+		ctx.ProgressMsg = fmt.Sprintf("Running %s hook command: %%s", run.hook)
+		if err := scriptExecutor.Execute(ctx); err != nil {
+			return logPath, fmt.Errorf("'%s' failed: %w", command.Run, err)
+		}
+	}
+
+	// A hook configured only with commands needs no script file
+	if len(commands) > 0 && !fileops.FileExists(run.scriptPath) {
+		return logPath, nil
+	}
+	ctx, err := execCtx()
+	if err != nil {
+		return logPath, err
+	}
+	ctx.ScriptPath = run.scriptPath
+	ctx.WorkingDir = run.workingDir
+	ctx.ProgressMsg = run.progressMsg
+	return logPath, scriptExecutor.Execute(ctx)
+}
+
+// hookEnv returns the variables describing the worktree and why a hook runs, on top of worktreeEnv
+func hookEnv(appState *state.State, repo *state.Repo, branch, operation string, newWorktree bool) []string {
+	envVars := consts.GetEnvironmentVariables()
+	env := worktreeEnv(appState, repo, branch)
+
+	if head, err := git.ResolveRef(getWorktreePath(repo, branch), "HEAD"); err == nil {
+		env = append(env, fmt.Sprintf("%s=%s", envVars.HeadSHA.Name, head))
+	}
+	env = append(env, fmt.Sprintf("%s=%s", envVars.Operation.Name, operation))
+	env = append(env, fmt.Sprintf("%s=%s", envVars.NewWorktree.Name, strconv.FormatBool(newWorktree)))
+	return env
+}
+
+// HookLog is one run of a hook, as recorded in the logs directory
+type HookLog struct {
+	Branch  string
+	Hook    string
+	Started time.Time
+	Path    string
+	// Result is one of the executors.ScriptResult values, or empty while the hook is still running
+	Result string
+}
+
+// ListHookLogs returns the hook logs of a repository, newest first, limited to one branch unless branch is empty
+func ListHookLogs(repo *state.Repo, branch string) ([]HookLog, error) {
+	repoLogsDir := filepath.Join(consts.GetDirectoryPaths().LogsDir, repo.Alias)
+	root := repoLogsDir
+	if branch != "" {
+		root = consts.GetFilePaths().HookLogsDir(repo.Alias, branch)
+	}
+
+	var logs []HookLog
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			// Logs of branches nested under the one asked for, such as feature/x under feature, are not its own
+			if branch != "" && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		hook, started, ok := parseHookLogName(entry.Name())
+		if !ok {
+			return nil
+		}
+		rel, err := filepath.Rel(repoLogsDir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		logs = append(logs, HookLog{
+			Branch:  filepath.ToSlash(rel),
+			Hook:    hook,
+			Started: started,
+			Path:    path,
+			Result:  executors.ReadScriptResult(path),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hook logs: %w", err)
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Started.After(logs[j].Started)
+	})
+	return logs, nil
+}
+
+// parseHookLogName splits a log file name such as post-worktree-add-20250102-150405.log into hook and start time
+func parseHookLogName(name string) (string, time.Time, bool) {
+	base, ok := strings.CutSuffix(name, ".log")
+	if !ok || len(base) < len(hookLogTimeLayout)+2 {
+		return "", time.Time{}, false
+	}
+
+	stamp := base[len(base)-len(hookLogTimeLayout):]
+	hook, ok := strings.CutSuffix(base[:len(base)-len(hookLogTimeLayout)], "-")
+	if !ok {
+		return "", time.Time{}, false
+	}
+	started, err := time.ParseInLocation(hookLogTimeLayout, stamp, time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return hook, started, true
+}
+
+// pruneHookLogs removes all but the newest maxHookLogs logs of a hook in a logs directory
+func pruneHookLogs(logsDir, hook string) {
+	entries, err := os.ReadDir(logsDir)
+	if err != nil {
+		return
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if name, _, ok := parseHookLogName(entry.Name()); ok && name == hook {
+			names = append(names, entry.Name())
+		}
+	}
+	// The timestamp layout sorts chronologically
+	sort.Strings(names)
+	for len(names) > maxHookLogs {
+		_ = os.Remove(filepath.Join(logsDir, names[0]))
+		names = names[1:]
+	}
+}
@@ -0,0 +1,170 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/executors"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/testutil"
+)
+
+func TestAddWorktree_RunsHookWithEnvironmentAndLog(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	cfg := &config.Config{HookTimeouts: map[string]string{consts.HookPostWorktreeAdd: "1m"}}
+	repo := &appState.Repos[0]
+
+	envFile := filepath.Join(t.TempDir(), "env")
+	script := consts.GetFilePaths().PostWorktreeAddScript(repo.Alias)
+	os.MkdirAll(filepath.Dir(script), 0755)
+	os.WriteFile(script, []byte("env | grep ^WT_ > "+envFile+"\necho hook ran\n"), 0755)
+
+	if err := AddWorktree(cfg, appState, "feature"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+
+	data, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatalf("Expected the hook to run: %v", err)
+	}
+	head := testutil.RunGit(t, getWorktreePath(repo, "feature"), "rev-parse", "HEAD")
+	for _, expected := range []string{
+		"WT_BRANCH=feature",
+		"WT_BASE_REF=origin/main",
+		"WT_HEAD_SHA=" + head,
+		"WT_OPERATION=add",
+		"WT_NEW_WORKTREE=true",
+	} {
+		if !strings.Contains(string(data), expected+"\n") {
+			t.Errorf("Expected %s in the hook environment, got:\n%s", expected, data)
+		}
+	}
+
+	logs, err := ListHookLogs(repo, "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].Hook != consts.HookPostWorktreeAdd || logs[0].Branch != "feature" || logs[0].Result != executors.ScriptResultOK {
+		t.Fatalf("Unexpected hook logs: %+v", logs)
+	}
+	if content, _ := os.ReadFile(logs[0].Path); !strings.Contains(string(content), "hook ran") {
+		t.Errorf("Expected the hook output in its log, got:\n%s", content)
+	}
+}
+
+func TestListHookLogs_NestedBranches(t *testing.T) {
+	t.Setenv("WT_HOME", t.TempDir())
+	repo := &state.Repo{Alias: "app"}
+	for _, branch := range []string{"feature", "feature/x"} {
+		dir := consts.GetFilePaths().HookLogsDir(repo.Alias, branch)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "work-on-20250102-150405.log"), nil, 0644)
+	}
+
+	all, err := ListHookLogs(repo, "")
+	if err != nil || len(all) != 2 {
+		t.Fatalf("Expected the logs of both branches, got %+v (%v)", all, err)
+	}
+	logs, err := ListHookLogs(repo, "feature")
+	if err != nil || len(logs) != 1 || logs[0].Branch != "feature" || logs[0].Hook != "work-on" {
+		t.Errorf("Expected only the feature branch's log, got %+v (%v)", logs, err)
+	}
+	if logs, err := ListHookLogs(repo, "missing"); err != nil || len(logs) != 0 {
+		t.Errorf("Expected no logs for an unknown branch, got %+v (%v)", logs, err)
+	}
+}
+
+func TestPruneHookLogs(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 1, 2, 15, 0, 0, 0, time.Local)
+	for i := 0; i < maxHookLogs+3; i++ {
+		name := "post-worktree-add-" + start.Add(time.Duration(i)*time.Minute).Format(hookLogTimeLayout) + ".log"
+		os.WriteFile(filepath.Join(dir, name), nil, 0644)
+	}
+	os.WriteFile(filepath.Join(dir, "work-on-20250101-000000.log"), nil, 0644)
+
+	pruneHookLogs(dir, consts.HookPostWorktreeAdd)
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != maxHookLogs+1 {
+		t.Fatalf("Expected %d logs left, got %d", maxHookLogs+1, len(entries))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "post-worktree-add-20250102-150000.log")); !os.IsNotExist(err) {
+		t.Error("Expected the oldest log to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "work-on-20250101-000000.log")); err != nil {
+		t.Error("Expected logs of other hooks to be kept")
+	}
+}
+
+func TestAddWorktree_RunsHookCommands(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	repo := &appState.Repos[0]
+	cfg := &config.Config{
+		HookTimeouts: map[string]string{consts.HookPostWorktreeAdd: "1m"},
+		HookCommands: state.HookCommands{
+			consts.HookPostWorktreeAdd: {
+				{Run: "mkdir -p sub && echo \"$GREETING $WT_BRANCH\" > greeting.txt", Env: map[string]string{"GREETING": "hello"}, If: &state.HookCondition{Exists: "README"}},
+				{Run: "touch npm-installed", If: &state.HookCondition{Exists: "package.json"}},
+			},
+		},
+	}
+	repo.Hooks = state.HookCommands{
+		consts.HookPostWorktreeAdd: {{Run: "pwd > where.txt", Dir: "sub"}},
+	}
+
+	// Declared commands are enough without a script file
+	os.Remove(consts.GetFilePaths().PostWorktreeAddScript(repo.Alias))
+
+	if err := AddWorktree(cfg, appState, "feature"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	worktreePath := getWorktreePath(repo, "feature")
+
+	if data, err := os.ReadFile(filepath.Join(worktreePath, "greeting.txt")); err != nil || string(data) != "hello feature\n" {
+		t.Errorf("Expected the config command to run with its env, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "npm-installed")); err == nil {
+		t.Error("Expected the command conditioned on package.json to be skipped")
+	}
+	if data, err := os.ReadFile(filepath.Join(worktreePath, "sub", "where.txt")); err != nil || !strings.HasSuffix(strings.TrimSpace(string(data)), filepath.Join("feature", "sub")) {
+		t.Errorf("Expected the repository command to run in its dir, got %q (%v)", data, err)
+	}
+	if status, _ := ReadBootstrapStatus(repo, "feature"); status.State != BootstrapReady {
+		t.Errorf("Expected the hook to succeed, got %+v", status)
+	}
+}
+
+func TestHookCondition_Matches(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "package.json"), nil, 0644)
+
+	tests := []struct {
+		condition *state.HookCondition
+		expected  bool
+	}{
+		{nil, true},
+		{&state.HookCondition{Exists: "package.json"}, true},
+		{&state.HookCondition{Exists: "*.lock"}, false},
+		{&state.HookCondition{NotExists: "node_modules"}, true},
+		{&state.HookCondition{Branch: "feature/*"}, true},
+		{&state.HookCondition{Branch: "release/*"}, false},
+		{&state.HookCondition{Operation: "add", Exists: "package.json"}, true},
+		{&state.HookCondition{Operation: "work-on"}, false},
+	}
+	for _, tt := range tests {
+		if matched := tt.condition.Matches(dir, "feature/login", "add"); matched != tt.expected {
+			t.Errorf("%+v matched %v, want %v", tt.condition, matched, tt.expected)
+		}
+	}
+
+	invalid := state.HookCommands{"post-add": {{Run: "true"}}}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected an unknown hook name to be rejected")
+	}
+}
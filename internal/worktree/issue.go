@@ -0,0 +1,40 @@
+package worktree
+
+import (
+	"context"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/issues"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// AddIssueWorktree looks up an issue, derives the branch name from the branch template and creates a worktree for it
+func AddIssueWorktree(cfg *config.Config, appState *state.State, key string) error {
+	tracker, err := issues.NewTracker(cfg.IssueTracker)
+	if err != nil {
+		return err
+	}
+
+	output.Progress("Looking up issue %s...", key)
+
+	issue, err := tracker.Lookup(context.Background(), key)
+	if err != nil {
+		return err
+	}
+
+	branch, err := issues.BuildBranchName(cfg.GetBranchTemplate(), issue)
+	if err != nil {
+		return err
+	}
+
+	output.Info("Issue %s: %s", issue.Key, issue.Title)
+	output.Info("Branch name: %s", branch)
+
+	meta := &state.WorktreeMetadata{
+		IssueKey:   issue.Key,
+		IssueTitle: issue.Title,
+	}
+
+	return addWorktree(cfg, appState, branch, meta)
+}
@@ -0,0 +1,48 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"worktree-manager/internal/config"
+)
+
+func TestAddIssueWorktree(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+
+	issuesFile := filepath.Join(t.TempDir(), "issues.json")
+	os.WriteFile(issuesFile, []byte(`{"PROJ-123": {"title": "Fix login redirect"}}`), 0644)
+
+	cfg := &config.Config{
+		BranchTemplate: "feature/{{.Key}}-{{.Title | slug}}",
+		IssueTracker:   &config.IssueTrackerConfig{Type: "file", Path: issuesFile},
+	}
+
+	if err := AddIssueWorktree(cfg, appState, "PROJ-123"); err != nil {
+		t.Fatalf("AddIssueWorktree failed: %v", err)
+	}
+
+	branch := "feature/PROJ-123-fix-login-redirect"
+	repo := &appState.Repos[0]
+	if _, err := os.Stat(getWorktreePath(repo, branch)); err != nil {
+		t.Fatalf("Expected worktree for branch %s: %v", branch, err)
+	}
+
+	meta, ok := appState.GetWorktreeMetadata("app", branch)
+	if !ok || meta.IssueKey != "PROJ-123" || meta.IssueTitle != "Fix login redirect" {
+		t.Errorf("Unexpected worktree metadata: %+v (found: %v)", meta, ok)
+	}
+
+	if env := worktreeEnv(appState, repo, branch); !slices.Contains(env, "WT_ISSUE_KEY=PROJ-123") {
+		t.Errorf("Expected WT_ISSUE_KEY in worktree environment, got %v", env)
+	}
+
+	if err := RemoveWorktree(appState, branch, RemoveOptions{}); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	if _, ok := appState.GetWorktreeMetadata("app", branch); ok {
+		t.Error("Expected worktree metadata to be removed with the worktree")
+	}
+}
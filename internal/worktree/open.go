@@ -0,0 +1,72 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/editors"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// OpenWorktree opens a worktree of the active repo in an editor, the workon editor unless one is given
+func OpenWorktree(cfg *config.Config, appState *state.State, branch, editor string) error {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	worktreePath := getWorktreePath(activeRepo, branch)
+
+	if err := validateWorktreeExists(worktreePath, branch); err != nil {
+		return fmt.Errorf("%w\n\n💡 Use 'wt tree add %s' to create it first", err, branch)
+	}
+
+	if editor == "" {
+		editor = cfg.GetWorkonEditor()
+	}
+
+	touchWorktree(appState, activeRepo, branch)
+	return openInEditor(activeRepo, branch, worktreePath, editor)
+}
+
+// openInEditor prepares the editor's per-worktree project files, titled <alias>:<branch>, and launches it
+func openInEditor(repo *state.Repo, branch, worktreePath, editor string) error {
+	title := fmt.Sprintf("%s:%s", repo.Alias, branch)
+	target := editors.Target{Path: worktreePath, Title: title}
+
+	switch editors.DetectKind(editor) {
+	case editors.KindVSCode:
+		workspaceFile := consts.GetFilePaths().CodeWorkspace(repo.Alias, branch)
+		if err := editors.WriteCodeWorkspace(workspaceFile, worktreePath, title); err != nil {
+			output.Warning("Failed to write workspace file: %v", err)
+		} else {
+			target.WorkspaceFile = workspaceFile
+		}
+	case editors.KindJetBrains:
+		if err := editors.WriteJetBrainsProjectName(worktreePath, title); err != nil {
+			output.Warning("Failed to name the project: %v", err)
+		}
+	}
+
+	launch, err := editors.BuildCommand(editor, target, editors.InTmux())
+	if err != nil {
+		return err
+	}
+
+	output.Progress("Opening '%s' with %s...", title, launch)
+	if err := editors.Launch(launch); err != nil {
+		return fmt.Errorf("failed to open editor '%s': %w", editor, err)
+	}
+	return nil
+}
+
+// removeWorkspaceFiles deletes the editor files generated for a removed worktree
+func removeWorkspaceFiles(repo *state.Repo, branch string) {
+	workspaceFile := consts.GetFilePaths().CodeWorkspace(repo.Alias, branch)
+	if err := os.Remove(workspaceFile); err == nil {
+		output.Cleanup("Deleted workspace file %s", workspaceFile)
+	}
+}
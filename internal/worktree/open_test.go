@@ -0,0 +1,92 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/fileops"
+)
+
+// fakeEditor installs an executable on PATH that records its arguments
+func fakeEditor(t *testing.T, name string) string {
+	t.Helper()
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TMUX", "")
+	return argsFile
+}
+
+func waitForFile(t *testing.T, path string) string {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	t.Fatalf("Timed out waiting for %s", path)
+	return ""
+}
+
+func TestOpenWorktree_VSCode(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	cfg := &config.Config{}
+	argsFile := fakeEditor(t, "code")
+
+	if err := AddWorktree(cfg, appState, "feature"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+
+	if err := OpenWorktree(cfg, appState, "feature", "code"); err != nil {
+		t.Fatalf("OpenWorktree failed: %v", err)
+	}
+
+	workspaceFile := consts.GetFilePaths().CodeWorkspace("app", "feature")
+	if got := waitForFile(t, argsFile); got != "--new-window "+workspaceFile {
+		t.Errorf("Unexpected editor arguments: %q", got)
+	}
+	if !fileops.FileExists(workspaceFile) {
+		t.Fatalf("Expected workspace file at %s", workspaceFile)
+	}
+
+	if err := RemoveWorktree(appState, "feature", RemoveOptions{}); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	if fileops.FileExists(workspaceFile) {
+		t.Error("Expected the workspace file to be removed with the worktree")
+	}
+}
+
+func TestWorkOnWorktree_OpensWorkonEditor(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	argsFile := fakeEditor(t, "myeditor")
+	cfg := &config.Config{WorkonEditor: "myeditor --flag"}
+
+	if err := AddWorktree(&config.Config{}, appState, "feature"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	if err := WorkOnWorktree(cfg, appState, "feature"); err != nil {
+		t.Fatalf("WorkOnWorktree failed: %v", err)
+	}
+
+	expected := "--flag " + getWorktreePath(&appState.Repos[0], "feature")
+	if got := waitForFile(t, argsFile); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestOpenWorktree_Missing(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	if err := OpenWorktree(&config.Config{}, appState, "nope", "code"); err == nil {
+		t.Error("Expected an error for a missing worktree")
+	}
+}
@@ -3,13 +3,18 @@ package worktree
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
 	"worktree-manager/internal/config"
 	"worktree-manager/internal/consts"
 	"worktree-manager/internal/executors"
 	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/forge"
 	"worktree-manager/internal/git"
 	"worktree-manager/internal/output"
 	"worktree-manager/internal/state"
@@ -31,7 +36,20 @@ func getWorktreesDir(repo *state.Repo) string {
 	return filepath.Join(consts.GetDirectoryPaths().DefaultWorktreesDir, repo.Alias)
 }
 
+// repoBaseBranch returns the remote branch new work starts from: the repo's configured base branch, or origin's main or master
+func repoBaseBranch(repo *state.Repo) (string, error) {
+	if repo.BaseBranch != "" {
+		return "origin/" + repo.BaseBranch, nil
+	}
+	return git.GetBaseBranch(repo.Dir)
+}
+
 func AddWorktree(cfg *config.Config, appState *state.State, branch string) error {
+	return addWorktree(cfg, appState, branch, nil)
+}
+
+// addWorktree creates the worktree and records its metadata, if given, before the hooks run
+func addWorktree(cfg *config.Config, appState *state.State, branch string, meta *state.WorktreeMetadata) error {
 	activeRepo, err := appState.GetActiveRepo()
 	if err != nil {
 		return fmt.Errorf("❌ %v", err)
@@ -47,6 +65,7 @@ func AddWorktree(cfg *config.Config, appState *state.State, branch string) error
 		return fmt.Errorf("failed to create worktrees directory: %w", err)
 	}
 
+	var sourceBranch string
 	err = fileops.WithDir(activeRepo.Dir, func() error {
 		output.Progress("Fetching from origin...")
 
@@ -54,14 +73,13 @@ func AddWorktree(cfg *config.Config, appState *state.State, branch string) error
 			return fmt.Errorf("failed to fetch from origin: %w", err)
 		}
 
-		var sourceBranch string
 		var message string
 
 		if git.RemoteBranchExists(activeRepo.Dir, branch) {
 			sourceBranch = fmt.Sprintf("origin/%s", branch)
 			message = fmt.Sprintf("Worktree tracking remote branch '%s' created at %s", branch, worktreePath)
 		} else {
-			baseBranch, err := git.GetBaseBranch(activeRepo.Dir)
+			baseBranch, err := repoBaseBranch(activeRepo)
 			if err != nil {
 				return fmt.Errorf("failed to determine base branch: %w", err)
 			}
@@ -88,33 +106,74 @@ func AddWorktree(cfg *config.Config, appState *state.State, branch string) error
 		return err
 	}
 
-	if err := scriptExecutor.Execute(&executors.ScriptExecutionContext{
-		ScriptPath:   consts.GetFilePaths().PostWorktreeAddScript(activeRepo.Alias),
-		Repo:         activeRepo,
-		WorktreePath: worktreePath,
-		WorkingDir:   consts.GetDirectoryPaths().RepoScriptsDir(activeRepo.Alias),
-		ProgressMsg:  "Executing post-worktree-add script: %s",
+	if meta == nil {
+		meta = &state.WorktreeMetadata{}
+	}
+	if err := appState.UpdateWorktreeMetadata(activeRepo.Alias, branch, func(m *state.WorktreeMetadata) {
+		*m = *meta
+		m.Branch = branch
+		m.BaseRef = sourceBranch
 	}); err != nil {
-		output.Warning("Post-worktree-add script failed: %v", err)
+		output.Warning("Failed to save worktree metadata: %v", err)
+	}
+
+	finishWorktreeAdd(cfg, appState, activeRepo, branch, operationAdd)
+	return nil
+}
+
+// finishWorktreeAdd applies file rules and runs the post-add and work-on scripts for a newly created worktree
+func finishWorktreeAdd(cfg *config.Config, appState *state.State, repo *state.Repo, branch, operation string) {
+	worktreePath := getWorktreePath(repo, branch)
+
+	touchWorktree(appState, repo, branch)
+
+	// The index is allocated before any hook runs, so that they all see the same ports
+	if _, err := appState.AllocateWorktreeIndex(repo.Alias, branch); err != nil {
+		output.Warning("Failed to allocate the worktree's ports: %v", err)
+	}
+
+	if cfg.EnvFile || cfg.Direnv {
+		if err := writeEnvFiles(appState, repo, branch, worktreePath, cfg.Direnv); err != nil {
+			output.Warning("Failed to write the environment file: %v", err)
+		}
+	}
+
+	if err := applyFileRules(repo, worktreePath, false); err != nil {
+		output.Warning("Failed to apply file rules: %v", err)
+	}
+
+	async := false
+	if cfg.AsyncPostWorktreeAdd {
+		if err := startBootstrap(repo, branch, operation); err != nil {
+			output.Warning("Failed to start the post-worktree-add script in the background, running it now: %v", err)
+		} else {
+			output.Info("Post-worktree-add script running in the background")
+			output.Hint("Use 'wt tree wait %s' to wait for it, or 'wt logs %s' to see its output", branch, branch)
+			async = true
+		}
+	}
+	if !async {
+		if err := RunBootstrap(cfg, appState, repo, branch, operation); err != nil {
+			output.Warning("Post-worktree-add script failed: %v", err)
+			output.Hint("Use 'wt logs %s' to see its output", branch)
+		}
 	}
 
 	if cfg.AutomaticWorkOnAfterAdd {
 		output.Progress("Running work-on logic...")
-		if err := scriptExecutor.Execute(&executors.ScriptExecutionContext{
-			ScriptPath:   consts.GetFilePaths().WorkOnScript,
-			Repo:         activeRepo,
-			WorktreePath: worktreePath,
-			WorkingDir:   worktreePath,
-			ProgressMsg:  "Executing work-on script: %s",
-		}); err != nil {
-			output.Warning("Work-on script failed: %v", err)
-		}
+		workOn(cfg, appState, repo, branch, operation, true)
+	} else {
+		autoComposeUp(cfg, appState, repo, branch)
 	}
+}
 
-	return nil
+// RemoveOptions controls what is removed along with a worktree
+type RemoveOptions struct {
+	// Volumes removes the volumes of the worktree's compose stack, such as database data, when it is torn down
+	Volumes bool
 }
 
-func RemoveWorktree(appState *state.State, branch string) error {
+func RemoveWorktree(appState *state.State, branch string, opts RemoveOptions) error {
 	activeRepo, err := appState.GetActiveRepo()
 	if err != nil {
 		return fmt.Errorf("❌ %v", err)
@@ -126,6 +185,9 @@ func RemoveWorktree(appState *state.State, branch string) error {
 		return err
 	}
 
+	stopBootstrap(activeRepo, branch)
+	composeDownOnRemove(appState, activeRepo, branch, worktreePath, opts.Volumes)
+
 	err = fileops.WithDir(activeRepo.Dir, func() error {
 		if err := git.RemoveWorktree(activeRepo.Dir, worktreePath); err != nil {
 			return fmt.Errorf("failed to remove worktree: %w", err)
@@ -144,54 +206,107 @@ func RemoveWorktree(appState *state.State, branch string) error {
 		output.Cleanup("Deleted folder %s", worktreePath)
 	}
 
+	if err := appState.RemoveWorktreeMetadata(activeRepo.Alias, branch); err != nil {
+		output.Warning("Failed to remove worktree metadata: %v", err)
+	}
+
+	removeWorkspaceFiles(activeRepo, branch)
+
 	output.Success("Worktree '%s' removed", branch)
 	return nil
 }
 
-func ListWorktrees(appState *state.State) error {
+// PruneWorktrees removes the administrative files and metadata of worktrees whose directories are gone
+func PruneWorktrees(appState *state.State) error {
 	activeRepo, err := appState.GetActiveRepo()
 	if err != nil {
 		return fmt.Errorf("❌ %v", err)
 	}
 
-	var worktrees []git.Worktree
-	err = fileops.WithDir(activeRepo.Dir, func() error {
-		var err error
-		worktrees, err = git.ListWorktrees(activeRepo.Dir)
-		return err
-	})
-
+	worktrees, err := listManagedWorktrees(activeRepo)
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	PrintWorktreeList(activeRepo.Alias, worktrees)
+	var prunable []git.Worktree
+	for _, wt := range worktrees {
+		if wt.Prunable {
+			prunable = append(prunable, wt)
+		}
+	}
+
+	if len(prunable) == 0 {
+		output.Info("No missing worktrees to prune in '%s'", activeRepo.Alias)
+		return nil
+	}
+
+	if err := git.PruneWorktrees(activeRepo.Dir); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+
+	for _, wt := range prunable {
+		if wt.Branch != "" {
+			if err := appState.RemoveWorktreeMetadata(activeRepo.Alias, wt.ShortBranch()); err != nil {
+				output.Warning("Failed to remove worktree metadata: %v", err)
+			}
+		}
+		output.Cleanup("Pruned %s", wt.Path)
+	}
+
+	output.Success("Pruned %d missing worktree(s) in '%s'", len(prunable), activeRepo.Alias)
 	return nil
 }
 
-func ListWorktreesJSON(appState *state.State) error {
+// ListOptions controls how worktrees are filtered and sorted when listed
+type ListOptions struct {
+	Sort   string
+	Filter string
+	Forge  bool
+}
+
+func ListWorktrees(cfg *config.Config, appState *state.State, opts ListOptions) error {
 	activeRepo, err := appState.GetActiveRepo()
 	if err != nil {
 		return fmt.Errorf("❌ %v", err)
 	}
 
-	var worktrees []git.Worktree
-	err = fileops.WithDir(activeRepo.Dir, func() error {
-		var err error
-		worktrees, err = git.ListWorktrees(activeRepo.Dir)
-		return err
-	})
-
+	worktrees, err := listManagedWorktrees(activeRepo)
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	// Extract just the branch names for autocompletion
-	var branches []string
-	for _, wt := range worktrees {
-		if wt.Branch != "" {
-			branches = append(branches, wt.Branch)
+	infos, err := FilterWorktreeInfo(CollectWorktreeInfo(worktrees), opts.Filter)
+	if err != nil {
+		return err
+	}
+
+	if err := SortWorktreeInfo(infos, opts.Sort); err != nil {
+		return err
+	}
+
+	collectBootstrapStates(activeRepo, infos)
+	collectResources(appState, activeRepo, infos)
+
+	if opts.Forge {
+		provider, err := providerForRepo(cfg, activeRepo)
+		if err != nil {
+			return err
 		}
+		collectPullRequests(provider, infos)
+	}
+
+	PrintWorktreeList(activeRepo.Alias, infos, opts.Forge)
+
+	if opts.Forge {
+		suggestMergedPrunes(infos)
+	}
+	return nil
+}
+
+func ListWorktreesJSON(appState *state.State) error {
+	branches, err := managedBranches(appState)
+	if err != nil {
+		return err
 	}
 
 	jsonOutput, err := json.Marshal(branches)
@@ -203,7 +318,40 @@ func ListWorktreesJSON(appState *state.State) error {
 	return nil
 }
 
-func WorkOnWorktree(appState *state.State, branch string) error {
+// ListWorktreeNames prints the branch of each worktree on its own line, for shell completion without a JSON parser
+func ListWorktreeNames(appState *state.State) error {
+	branches, err := managedBranches(appState)
+	if err != nil {
+		return err
+	}
+	for _, branch := range branches {
+		fmt.Println(branch)
+	}
+	return nil
+}
+
+// managedBranches returns the branches of the managed worktrees of the active repository, for autocompletion
+func managedBranches(appState *state.State) ([]string, error) {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return nil, fmt.Errorf("❌ %v", err)
+	}
+
+	worktrees, err := listManagedWorktrees(activeRepo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	branches := []string{}
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			branches = append(branches, wt.ShortBranch())
+		}
+	}
+	return branches, nil
+}
+
+func WorkOnWorktree(cfg *config.Config, appState *state.State, branch string) error {
 	activeRepo, err := appState.GetActiveRepo()
 	if err != nil {
 		return fmt.Errorf("❌ %v", err)
@@ -218,17 +366,116 @@ func WorkOnWorktree(appState *state.State, branch string) error {
 	output.Progress("Working on branch '%s'...", branch)
 	output.Info("Worktree path: %s", worktreePath)
 
-	if err := scriptExecutor.Execute(&executors.ScriptExecutionContext{
-		ScriptPath:   consts.GetFilePaths().WorkOnScript,
-		Repo:         activeRepo,
-		WorktreePath: worktreePath,
-		WorkingDir:   worktreePath,
-		ProgressMsg:  "Executing work-on script: %s",
+	touchWorktree(appState, activeRepo, branch)
+	workOn(cfg, appState, activeRepo, branch, operationWorkOn, false)
+	return nil
+}
+
+// workOn brings up the compose stack, runs the work-on script and opens the worktree in the workon editor, as configured
+func workOn(cfg *config.Config, appState *state.State, repo *state.Repo, branch, operation string, newWorktree bool) {
+	worktreePath := getWorktreePath(repo, branch)
+
+	refreshEnvFiles(appState, repo, branch, worktreePath)
+	// The stack is up before the work-on script runs, so that it can rely on the services
+	autoComposeUp(cfg, appState, repo, branch)
+
+	if _, err := runHook(cfg, appState, repo, branch, hookRun{
+		hook:        consts.HookWorkOn,
+		scriptPath:  consts.GetFilePaths().WorkOnScript,
+		workingDir:  worktreePath,
+		progressMsg: "Executing work-on script: %s",
+		operation:   operation,
+		newWorktree: newWorktree,
 	}); err != nil {
 		output.Warning("Work-on script failed: %v", err)
 	}
 
-	return nil
+	if cfg.WorkonEditor != "" {
+		if err := openInEditor(repo, branch, worktreePath, cfg.WorkonEditor); err != nil {
+			output.Warning("%v", err)
+		}
+	}
+}
+
+// worktreeEnv returns the WT_* variables derived from a worktree's metadata, on top of the base script environment,
+// followed by the repository's own env
+func worktreeEnv(appState *state.State, repo *state.Repo, branch string) []string {
+	envVars := consts.GetEnvironmentVariables()
+	env := []string{fmt.Sprintf("%s=%s", envVars.Branch.Name, branch)}
+	data := envTemplateData{Alias: repo.Alias, Branch: branch}
+
+	// Detached worktrees, and worktrees not yet allocated an index, have no ports
+	if meta, ok := appState.GetWorktreeMetadata(repo.Alias, branch); ok && branch != "" {
+		if meta.Index > 0 {
+			resources := repo.Resources(branch, meta.Index)
+			env = append(env, resourceEnv(resources)...)
+			data.Index, data.Ports = resources.Index, resources.Ports
+		}
+		if meta.IssueKey != "" {
+			env = append(env, fmt.Sprintf("%s=%s", envVars.IssueKey.Name, meta.IssueKey))
+		}
+		if meta.BaseRef != "" {
+			env = append(env, fmt.Sprintf("%s=%s", envVars.BaseRef.Name, meta.BaseRef))
+		}
+		data.IssueKey = meta.IssueKey
+	}
+	return append(env, repoEnv(repo, data)...)
+}
+
+// AllocateWorktreeIndexes allocates an index to each worktree of a repository on a branch that has none yet, such as
+// worktrees created before indexes were
+func AllocateWorktreeIndexes(appState *state.State, repo *state.Repo) {
+	infos, err := RepoWorktrees(repo)
+	if err != nil {
+		output.Warning("Failed to list the worktrees of %s: %v", repo.Alias, err)
+		return
+	}
+	for _, info := range infos {
+		if info.ShortBranch() == "" {
+			continue
+		}
+		if _, err := appState.AllocateWorktreeIndex(repo.Alias, info.ShortBranch()); err != nil {
+			output.Warning("Failed to allocate the ports of '%s': %v", info.ShortBranch(), err)
+		}
+	}
+}
+
+// collectResources fills in the index and ports of listed worktrees that have been allocated an index
+func collectResources(appState *state.State, repo *state.Repo, infos []WorktreeInfo) {
+	for i := range infos {
+		if meta, ok := appState.GetWorktreeMetadata(repo.Alias, infos[i].ShortBranch()); ok && meta.Index > 0 {
+			resources := repo.Resources(infos[i].ShortBranch(), meta.Index)
+			infos[i].Resources = &resources
+		}
+	}
+}
+
+// formatResources renders the INDEX and PORTS columns of a worktree
+func formatResources(resources *state.WorktreeResources) []string {
+	if resources == nil {
+		return []string{"-", "-"}
+	}
+	ports := make([]string, 0, len(resources.Ports))
+	for _, name := range slices.Sorted(maps.Keys(resources.Ports)) {
+		ports = append(ports, fmt.Sprintf("%s=%d", name, resources.Ports[name]))
+	}
+	if len(ports) == 0 {
+		ports = append(ports, "-")
+	}
+	return []string{strconv.Itoa(resources.Index), strings.Join(ports, " ")}
+}
+
+// resourceEnv returns the variables passing a worktree's index, ports and compose project name
+func resourceEnv(resources state.WorktreeResources) []string {
+	envVars := consts.GetEnvironmentVariables()
+	env := []string{
+		fmt.Sprintf("%s=%d", envVars.WorktreeIndex.Name, resources.Index),
+		fmt.Sprintf("%s=%s", envVars.ComposeProject.Name, resources.ComposeProject),
+	}
+	for _, name := range slices.Sorted(maps.Keys(resources.Ports)) {
+		env = append(env, fmt.Sprintf("%s=%d", state.PortEnvName(name), resources.Ports[name]))
+	}
+	return env
 }
 
 func validateWorktreeExists(worktreePath, branch string) error {
@@ -245,25 +492,79 @@ func validateWorktreeDoesNotExist(worktreePath, branch string) error {
 	return nil
 }
 
-func FormatWorktreeInfo(wt git.Worktree) string {
-	info := fmt.Sprintf("%s\n   Path: %s", filepath.Base(wt.Path), wt.Path)
+// FormatWorktreeRow renders a worktree as the columns of the list table
+func FormatWorktreeRow(info WorktreeInfo, showForge bool) []string {
+	marker := ""
+	if info.Current {
+		marker = "*"
+	}
+
+	row := []string{
+		marker,
+		formatBranch(info),
+		info.ShortHead(),
+		formatState(info),
+		formatUpstream(info.Status),
+		formatAge(info.Status.LastCommitTime),
+		truncate(info.Status.LastCommitSubject, 50),
+		formatFlags(info),
+	}
 
-	if wt.Branch != "" {
-		info += fmt.Sprintf("\n   Branch: %s", wt.Branch)
+	if showForge {
+		row = append(row,
+			formatPullRequest(info),
+			formatForgeField(info, func(pr *forge.PullRequest) string { return pr.CIStatus }),
+			formatForgeField(info, func(pr *forge.PullRequest) string { return pr.ReviewState }),
+		)
 	}
 
-	return info
+	return row
 }
 
-func PrintWorktreeList(repoAlias string, worktrees []git.Worktree) {
+func PrintWorktreeList(repoAlias string, infos []WorktreeInfo, showForge bool) {
 	output.Info("Worktrees for repository '%s':", repoAlias)
 
-	if len(worktrees) == 0 {
+	if len(infos) == 0 {
 		output.Hint("No worktrees found. Use 'wt tree add <branch>' to create one.")
 		return
 	}
 
-	for _, wt := range worktrees {
-		output.Item(FormatWorktreeInfo(wt))
+	headers := []string{"", "BRANCH", "HEAD", "STATE", "UPSTREAM", "AGE", "SUBJECT", "FLAGS"}
+	if showForge {
+		headers = append(headers, "PR", "CI", "REVIEW")
+	}
+
+	// Only worktrees created since post-worktree-add runs are recorded have a setup state
+	showSetup := false
+	for _, info := range infos {
+		if info.Bootstrap != "" {
+			showSetup = true
+		}
 	}
+	if showSetup {
+		headers = append(headers, "SETUP")
+	}
+
+	showResources := slices.ContainsFunc(infos, func(info WorktreeInfo) bool { return info.Resources != nil })
+	if showResources {
+		headers = append(headers, "INDEX", "PORTS")
+	}
+
+	rows := make([][]string, 0, len(infos))
+	for _, info := range infos {
+		row := FormatWorktreeRow(info, showForge)
+		if showSetup {
+			setup := info.Bootstrap
+			if setup == "" {
+				setup = "-"
+			}
+			row = append(row, setup)
+		}
+		if showResources {
+			row = append(row, formatResources(info.Resources)...)
+		}
+		rows = append(rows, row)
+	}
+
+	output.Table(headers, rows)
 }
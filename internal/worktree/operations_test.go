@@ -0,0 +1,99 @@
+package worktree
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/testutil"
+)
+
+func TestPruneWorktrees(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	cfg := &config.Config{}
+
+	if err := AddWorktree(cfg, appState, "gone"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	if err := AddWorktree(cfg, appState, "kept"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+
+	repo := &appState.Repos[0]
+	if err := os.RemoveAll(getWorktreePath(repo, "gone")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := PruneWorktrees(appState); err != nil {
+		t.Fatalf("PruneWorktrees failed: %v", err)
+	}
+
+	infos, err := RepoWorktrees(repo)
+	if err != nil {
+		t.Fatalf("RepoWorktrees failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].ShortBranch() != "kept" {
+		t.Errorf("Expected only 'kept' to remain, got %+v", infos)
+	}
+
+	if _, ok := appState.GetWorktreeMetadata("app", "gone"); ok {
+		t.Error("Expected the metadata of the pruned worktree to be removed")
+	}
+	if _, ok := appState.GetWorktreeMetadata("app", "kept"); !ok {
+		t.Error("Expected the metadata of the remaining worktree to be kept")
+	}
+}
+
+func TestAddWorktree_UsesRepoBaseBranch(t *testing.T) {
+	appState, seed := setupTestRepo(t)
+
+	testutil.RunGit(t, seed, "checkout", "--quiet", "-b", "develop")
+	os.WriteFile(filepath.Join(seed, "develop.txt"), []byte("develop\n"), 0644)
+	testutil.RunGit(t, seed, "add", ".")
+	testutil.RunGit(t, seed, "commit", "--quiet", "-m", "Start develop")
+	testutil.RunGit(t, seed, "push", "--quiet", "origin", "develop")
+
+	appState.Repos[0].BaseBranch = "develop"
+	if err := AddWorktree(&config.Config{}, appState, "feature"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+
+	if !fileops.FileExists(filepath.Join(getWorktreePath(&appState.Repos[0], "feature"), "develop.txt")) {
+		t.Error("Expected the new branch to start from the repo's base branch")
+	}
+}
+
+// BenchmarkListWorktrees compares the git backends on 'wt tree list' for a repository with 50 worktrees
+func BenchmarkListWorktrees(b *testing.B) {
+	appState, _ := setupTestRepo(b)
+	repo := &appState.Repos[0]
+	for i := 0; i < 50; i++ {
+		branch := fmt.Sprintf("feature-%02d", i)
+		testutil.RunGit(b, repo.Dir, "worktree", "add", "--quiet", "-b", branch, getWorktreePath(repo, branch), "origin/main")
+	}
+
+	cfg := &config.Config{}
+	restore := output.Redirect(io.Discard, io.Discard)
+	defer restore()
+
+	for _, backend := range []string{git.BackendCLI, git.BackendGoGit} {
+		b.Run(backend, func(b *testing.B) {
+			if err := git.SetDefaultBackend(backend); err != nil {
+				b.Fatal(err)
+			}
+			defer git.SetDefaultBackend(git.BackendCLI)
+
+			for i := 0; i < b.N; i++ {
+				if err := ListWorktrees(cfg, appState, ListOptions{Sort: "branch"}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/picker"
+	"worktree-manager/internal/state"
+)
+
+// PickWorktrees lets the user choose worktrees of the active repo interactively, most recently used first
+func PickWorktrees(cfg *config.Config, appState *state.State, prompt string, multi bool) ([]WorktreeInfo, error) {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return nil, fmt.Errorf("❌ %v", err)
+	}
+
+	worktrees, err := listManagedWorktrees(activeRepo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var infos []WorktreeInfo
+	for _, info := range CollectWorktreeInfo(worktrees) {
+		// Detached worktrees have no branch to pass to the tree commands
+		if info.Branch != "" {
+			infos = append(infos, info)
+		}
+	}
+
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("no worktrees found for repository '%s'\n\n💡 Use 'wt tree add <branch>' to create one", activeRepo.Alias)
+	}
+
+	entries := make([]pickEntry, len(infos))
+	for i, info := range infos {
+		entries[i].info = info
+		if meta, ok := appState.GetWorktreeMetadata(activeRepo.Alias, info.ShortBranch()); ok && meta.LastUsed != nil {
+			entries[i].lastUsed = *meta.LastUsed
+		}
+	}
+	sortByLastUsed(entries)
+
+	rows := [][]string{{"BRANCH", "STATE", "LAST USED", "PATH"}}
+	for _, entry := range entries {
+		rows = append(rows, []string{entry.info.ShortBranch(), formatState(entry.info), formatAge(entry.lastUsed), entry.info.Path})
+	}
+	lines := output.AlignColumns(rows)
+
+	items := make([]picker.Item, len(entries))
+	byBranch := make(map[string]WorktreeInfo, len(entries))
+	for i, entry := range entries {
+		items[i] = picker.Item{Value: entry.info.ShortBranch(), Label: lines[i+1]}
+		byBranch[entry.info.ShortBranch()] = entry.info
+	}
+
+	selected, err := picker.Pick(items, picker.Options{
+		Prompt:  prompt,
+		Header:  lines[0],
+		Multi:   multi,
+		Command: cfg.GetPicker(),
+	})
+	if errors.Is(err, picker.ErrNoTerminal) {
+		return nil, fmt.Errorf("%w\n\n💡 Pass the branch name as an argument", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	picked := make([]WorktreeInfo, 0, len(selected))
+	for _, item := range selected {
+		picked = append(picked, byBranch[item.Value])
+	}
+	return picked, nil
+}
+
+// PickRepo lets the user choose a managed repository interactively and returns its alias
+func PickRepo(cfg *config.Config, appState *state.State) (string, error) {
+	if len(appState.Repos) == 0 {
+		return "", fmt.Errorf("no repositories found\n\n💡 Use 'wt repo clone <url>' to add one")
+	}
+
+	rows := [][]string{{"", "ALIAS", "DIR"}}
+	for _, repo := range appState.Repos {
+		marker := ""
+		if repo.Alias == appState.ActiveRepo {
+			marker = "*"
+		}
+		rows = append(rows, []string{marker, repo.Alias, repo.Dir})
+	}
+	lines := output.AlignColumns(rows)
+
+	items := make([]picker.Item, len(appState.Repos))
+	for i, repo := range appState.Repos {
+		items[i] = picker.Item{Value: repo.Alias, Label: lines[i+1]}
+	}
+
+	selected, err := picker.Pick(items, picker.Options{
+		Prompt:  "repo> ",
+		Header:  lines[0],
+		Command: cfg.GetPicker(),
+	})
+	if errors.Is(err, picker.ErrNoTerminal) {
+		return "", fmt.Errorf("%w\n\n💡 Pass the repository alias as an argument", err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return selected[0].Value, nil
+}
+
+// pickEntry pairs a worktree with the time it was last worked on, zero if never
+type pickEntry struct {
+	info     WorktreeInfo
+	lastUsed time.Time
+}
+
+// sortByLastUsed orders worktrees by when they were last worked on, never-used ones last by branch
+func sortByLastUsed(entries []pickEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.lastUsed.Equal(b.lastUsed) {
+			return a.lastUsed.After(b.lastUsed)
+		}
+		return a.info.ShortBranch() < b.info.ShortBranch()
+	})
+}
+
+// touchWorktree records that a worktree was just worked on so pickers can list it first
+func touchWorktree(appState *state.State, repo *state.Repo, branch string) {
+	now := time.Now()
+	if err := appState.UpdateWorktreeMetadata(repo.Alias, branch, func(meta *state.WorktreeMetadata) {
+		meta.LastUsed = &now
+	}); err != nil {
+		output.Warning("Failed to record worktree usage: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package worktree
+
+import (
+	"testing"
+
+	"worktree-manager/internal/config"
+)
+
+func TestPickWorktrees_MostRecentlyUsedFirst(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	cfg := &config.Config{}
+
+	for _, branch := range []string{"alpha", "beta"} {
+		if err := AddWorktree(cfg, appState, branch); err != nil {
+			t.Fatalf("AddWorktree(%s) failed: %v", branch, err)
+		}
+	}
+	if err := WorkOnWorktree(cfg, appState, "alpha"); err != nil {
+		t.Fatalf("WorkOnWorktree failed: %v", err)
+	}
+
+	// An external picker that takes the first line stands in for the user
+	cfg.Picker = "head -n 1"
+
+	picked, err := PickWorktrees(cfg, appState, "", false)
+	if err != nil {
+		t.Fatalf("PickWorktrees failed: %v", err)
+	}
+	if len(picked) != 1 || picked[0].ShortBranch() != "alpha" {
+		t.Errorf("Expected the most recently used worktree 'alpha', got %+v", picked)
+	}
+
+	alias, err := PickRepo(cfg, appState)
+	if err != nil || alias != "app" {
+		t.Errorf("Expected PickRepo to return 'app', got %q (err: %v)", alias, err)
+	}
+}
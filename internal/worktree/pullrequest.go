@@ -0,0 +1,162 @@
+package worktree
+
+import (
+	"fmt"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/fileops"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// AddPullRequestWorktree fetches the head of a pull or merge request into a pr/<number> branch and creates a worktree for it
+func AddPullRequestWorktree(cfg *config.Config, appState *state.State, number int) error {
+	if number <= 0 {
+		return fmt.Errorf("invalid pull request number: %d", number)
+	}
+
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	branch := git.PullRequestBranch(number)
+	worktreePath := getWorktreePath(activeRepo, branch)
+
+	if err := validateWorktreeDoesNotExist(worktreePath, branch); err != nil {
+		return err
+	}
+
+	if err := fileops.EnsureDir(getWorktreesDir(activeRepo)); err != nil {
+		return fmt.Errorf("failed to create worktrees directory: %w", err)
+	}
+
+	ref, err := resolvePullRequestRef(activeRepo, number)
+	if err != nil {
+		return err
+	}
+
+	trackingRef := git.PullRequestTrackingRef(number)
+
+	output.Progress("Fetching %s...", ref)
+	if err := git.FetchRef(activeRepo.Dir, ref, trackingRef); err != nil {
+		return fmt.Errorf("failed to fetch pull request #%d: %w", number, err)
+	}
+
+	opts := git.WorktreeCreateOptions{
+		Branch:       branch,
+		WorktreePath: worktreePath,
+		SourceBranch: trackingRef,
+		CreateBranch: true,
+	}
+
+	// A branch left behind by a previously removed worktree is reused rather than recreated
+	existingBranch := git.LocalBranchExists(activeRepo.Dir, branch)
+	if existingBranch {
+		opts.SourceBranch = branch
+		opts.CreateBranch = false
+	}
+
+	if err := git.CreateWorktree(activeRepo.Dir, opts); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+
+	if err := git.SetBranchUpstream(activeRepo.Dir, branch, ref); err != nil {
+		output.Warning("Failed to set upstream for '%s': %v", branch, err)
+	}
+
+	if existingBranch {
+		if err := git.MergeFastForward(worktreePath, trackingRef); err != nil {
+			output.Warning("Existing branch '%s' could not be fast-forwarded to the pull request head", branch)
+			output.Hint("Use 'wt tree refresh %s --force' to reset it", branch)
+		}
+	}
+
+	output.Success("Pull request #%d worktree created at %s on branch '%s'", number, worktreePath, branch)
+
+	if err := appState.UpdateWorktreeMetadata(activeRepo.Alias, branch, func(m *state.WorktreeMetadata) {
+		m.BaseRef = trackingRef
+	}); err != nil {
+		output.Warning("Failed to save worktree metadata: %v", err)
+	}
+
+	finishWorktreeAdd(cfg, appState, activeRepo, branch, operationPullRequest)
+	return nil
+}
+
+// RefreshPullRequestWorktree re-fetches the head of the pull request a worktree was created from
+func RefreshPullRequestWorktree(appState *state.State, branch string, force bool) error {
+	activeRepo, err := appState.GetActiveRepo()
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	worktreePath := getWorktreePath(activeRepo, branch)
+
+	if err := validateWorktreeExists(worktreePath, branch); err != nil {
+		return err
+	}
+
+	ref, err := git.GetBranchMergeRef(activeRepo.Dir, branch)
+	if err != nil {
+		return err
+	}
+
+	number, ok := git.ParsePullRequestRef(ref)
+	if !ok {
+		return fmt.Errorf("worktree '%s' was not created from a pull request (upstream is %s)\n\n💡 Use 'wt sync' to update regular branches", branch, ref)
+	}
+
+	status, err := git.GetWorktreeStatus(worktreePath)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("worktree '%s' has uncommitted changes; commit or stash them first", branch)
+	}
+
+	trackingRef := git.PullRequestTrackingRef(number)
+
+	output.Progress("Fetching %s...", ref)
+	if err := git.FetchRef(activeRepo.Dir, ref, trackingRef); err != nil {
+		return fmt.Errorf("failed to fetch pull request #%d: %w", number, err)
+	}
+
+	if err := git.MergeFastForward(worktreePath, trackingRef); err == nil {
+		output.Success("Worktree '%s' updated to the latest head of pull request #%d", branch, number)
+		return nil
+	}
+
+	if !force {
+		return fmt.Errorf("pull request #%d was rewritten and cannot be fast-forwarded\n\n💡 Use 'wt tree refresh %s --force' to reset the worktree to the new head", number, branch)
+	}
+
+	if err := git.ResetHard(worktreePath, trackingRef); err != nil {
+		return fmt.Errorf("failed to reset worktree: %w", err)
+	}
+
+	output.Success("Worktree '%s' reset to the latest head of pull request #%d", branch, number)
+	return nil
+}
+
+// resolvePullRequestRef picks the pull request ref from the remote URL, probing origin when the forge is unknown
+func resolvePullRequestRef(repo *state.Repo, number int) (string, error) {
+	remoteURL, err := git.GetRemoteURL(repo.Dir)
+	if err != nil {
+		return "", err
+	}
+
+	if provider := git.DetectPullRequestProvider(remoteURL); provider != git.ProviderUnknown {
+		return git.PullRequestRef(provider, number), nil
+	}
+
+	for _, provider := range []git.PullRequestProvider{git.ProviderGitHub, git.ProviderGitLab} {
+		ref := git.PullRequestRef(provider, number)
+		if git.RemoteRefExists(repo.Dir, ref) {
+			return ref, nil
+		}
+	}
+
+	return "", fmt.Errorf("pull request #%d not found on origin (%s)", number, remoteURL)
+}
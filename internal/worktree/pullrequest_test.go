@@ -0,0 +1,88 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/testutil"
+)
+
+// setupTestRepo creates a bare origin with one commit on main, clones it as a managed repo and returns the state
+func setupTestRepo(t testing.TB) (*state.State, string) {
+	t.Helper()
+	testutil.SetupHome(t)
+	origin, seed := testutil.NewOriginRepo(t)
+
+	repoDir := filepath.Join(consts.GetDirectoryPaths().DefaultGitReposDir, "app")
+	testutil.RunGit(t, "", "clone", "--quiet", origin, repoDir)
+
+	appState := &state.State{
+		ActiveRepo: "app",
+		Repos:      []state.Repo{{Alias: "app", Dir: repoDir}},
+	}
+	return appState, seed
+}
+
+func TestAddAndRefreshPullRequestWorktree(t *testing.T) {
+	appState, seed := setupTestRepo(t)
+	cfg := &config.Config{}
+
+	// Publish a pull request head the way GitHub does, without a matching branch
+	testutil.RunGit(t, seed, "checkout", "--quiet", "-b", "contributor")
+	os.WriteFile(filepath.Join(seed, "feature.txt"), []byte("v1\n"), 0644)
+	testutil.RunGit(t, seed, "add", ".")
+	testutil.RunGit(t, seed, "commit", "--quiet", "-m", "Add feature")
+	testutil.RunGit(t, seed, "push", "--quiet", "origin", "HEAD:refs/pull/7/head")
+
+	if err := AddPullRequestWorktree(cfg, appState, 7); err != nil {
+		t.Fatalf("AddPullRequestWorktree failed: %v", err)
+	}
+
+	repo := &appState.Repos[0]
+	worktreePath := getWorktreePath(repo, "pr/7")
+	if data, err := os.ReadFile(filepath.Join(worktreePath, "feature.txt")); err != nil || string(data) != "v1\n" {
+		t.Fatalf("Expected pull request content in worktree, got %q (err: %v)", data, err)
+	}
+
+	if merge := testutil.RunGit(t, repo.Dir, "config", "--get", "branch.pr/7.merge"); merge != "refs/pull/7/head" {
+		t.Errorf("Expected branch upstream refs/pull/7/head, got %q", merge)
+	}
+
+	// A new commit on the pull request is picked up by a refresh
+	os.WriteFile(filepath.Join(seed, "feature.txt"), []byte("v2\n"), 0644)
+	testutil.RunGit(t, seed, "commit", "--quiet", "-am", "Update feature")
+	testutil.RunGit(t, seed, "push", "--quiet", "origin", "HEAD:refs/pull/7/head")
+
+	if err := RefreshPullRequestWorktree(appState, "pr/7", false); err != nil {
+		t.Fatalf("RefreshPullRequestWorktree failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(worktreePath, "feature.txt")); string(data) != "v2\n" {
+		t.Errorf("Expected refreshed content 'v2', got %q", data)
+	}
+
+	// A force-pushed pull request needs --force
+	testutil.RunGit(t, seed, "commit", "--quiet", "--amend", "-m", "Rewritten feature")
+	testutil.RunGit(t, seed, "push", "--quiet", "--force", "origin", "HEAD:refs/pull/7/head")
+
+	if err := RefreshPullRequestWorktree(appState, "pr/7", false); err == nil {
+		t.Error("Expected refresh of a rewritten pull request to fail without force")
+	}
+	if err := RefreshPullRequestWorktree(appState, "pr/7", true); err != nil {
+		t.Fatalf("RefreshPullRequestWorktree with force failed: %v", err)
+	}
+	if subject := testutil.RunGit(t, worktreePath, "log", "-1", "--format=%s"); subject != "Rewritten feature" {
+		t.Errorf("Expected worktree to be reset to the rewritten head, got %q", subject)
+	}
+}
+
+func TestAddPullRequestWorktree_NotFound(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+
+	if err := AddPullRequestWorktree(&config.Config{}, appState, 99); err == nil {
+		t.Error("Expected error for a pull request that does not exist")
+	}
+}
@@ -0,0 +1,175 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// repoConcurrency limits how many repositories are inspected at once
+const repoConcurrency = 4
+
+// RepoStatus summarises the worktrees of a single managed repository
+type RepoStatus struct {
+	Alias            string   `json:"alias"`
+	Dir              string   `json:"dir"`
+	Active           bool     `json:"active"`
+	Missing          bool     `json:"missing"`
+	Worktrees        int      `json:"worktrees"`
+	Dirty            []string `json:"dirty"`
+	Ahead            []string `json:"ahead"`
+	Behind           []string `json:"behind"`
+	MissingWorktrees []string `json:"missing-worktrees"`
+	Error            string   `json:"error,omitempty"`
+}
+
+// StatusOptions controls how the cross-repository status is collected
+type StatusOptions struct {
+	Fetch bool
+	JSON  bool
+}
+
+// CollectRepoStatuses inspects every managed repository concurrently
+func CollectRepoStatuses(appState *state.State, fetch bool) []RepoStatus {
+	statuses := make([]RepoStatus, len(appState.Repos))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, repoConcurrency)
+
+	for i := range appState.Repos {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			repo := appState.Repos[i]
+			statuses[i] = collectRepoStatus(&repo, fetch)
+			statuses[i].Active = repo.Alias == appState.ActiveRepo
+		}(i)
+	}
+
+	wg.Wait()
+	return statuses
+}
+
+func collectRepoStatus(repo *state.Repo, fetch bool) RepoStatus {
+	status := RepoStatus{
+		Alias:            repo.Alias,
+		Dir:              repo.Dir,
+		Dirty:            []string{},
+		Ahead:            []string{},
+		Behind:           []string{},
+		MissingWorktrees: []string{},
+	}
+
+	if _, err := os.Stat(repo.Dir); os.IsNotExist(err) {
+		status.Missing = true
+		return status
+	}
+
+	if fetch {
+		if err := git.FetchFromOrigin(repo.Dir); err != nil {
+			status.Error = fmt.Sprintf("fetch failed: %v", err)
+		}
+	}
+
+	worktrees, err := listManagedWorktrees(repo)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.Worktrees = len(worktrees)
+
+	for _, info := range CollectWorktreeInfo(worktrees) {
+		branch := formatBranch(info)
+
+		if info.Prunable {
+			status.MissingWorktrees = append(status.MissingWorktrees, branch)
+			continue
+		}
+		if info.Status.Dirty {
+			status.Dirty = append(status.Dirty, branch)
+		}
+		if info.Status.Ahead > 0 {
+			status.Ahead = append(status.Ahead, branch)
+		}
+		if info.Status.Behind > 0 {
+			status.Behind = append(status.Behind, branch)
+		}
+	}
+
+	return status
+}
+
+// ShowStatus prints the status of every managed repository
+func ShowStatus(appState *state.State, opts StatusOptions) error {
+	if len(appState.Repos) == 0 && !opts.JSON {
+		output.Warning("No repositories configured")
+		output.Hint("Use 'wt repo clone <url>' to add a repository")
+		return nil
+	}
+
+	if opts.Fetch && !opts.JSON {
+		output.Progress("Fetching %d repositories...", len(appState.Repos))
+	}
+
+	statuses := CollectRepoStatuses(appState, opts.Fetch)
+
+	if opts.JSON {
+		jsonOutput, err := json.MarshalIndent(statuses, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonOutput))
+		return nil
+	}
+
+	PrintRepoStatusTable(statuses)
+	return nil
+}
+
+// PrintRepoStatusTable displays one row per repository
+func PrintRepoStatusTable(statuses []RepoStatus) {
+	output.Info("Status of %d repositories:", len(statuses))
+
+	rows := make([][]string, 0, len(statuses))
+	for _, status := range statuses {
+		marker := ""
+		if status.Active {
+			marker = "*"
+		}
+
+		notes := status.Error
+		if status.Missing {
+			notes = "repository directory missing"
+		}
+
+		rows = append(rows, []string{
+			marker,
+			status.Alias,
+			fmt.Sprintf("%d", status.Worktrees),
+			formatBranchList(status.Dirty),
+			formatBranchList(status.Ahead),
+			formatBranchList(status.Behind),
+			formatBranchList(status.MissingWorktrees),
+			notes,
+		})
+	}
+
+	output.Table([]string{"", "REPO", "WORKTREES", "DIRTY", "AHEAD", "BEHIND", "MISSING", "NOTES"}, rows)
+}
+
+func formatBranchList(branches []string) string {
+	if len(branches) == 0 {
+		return "-"
+	}
+	return truncate(strings.Join(branches, ","), 40)
+}
@@ -0,0 +1,33 @@
+package worktree
+
+import (
+	"path/filepath"
+	"testing"
+
+	"worktree-manager/internal/state"
+)
+
+func TestCollectRepoStatuses_MissingRepo(t *testing.T) {
+	appState := &state.State{
+		ActiveRepo: "api",
+		Repos: []state.Repo{
+			{Alias: "api", Dir: filepath.Join(t.TempDir(), "missing-api")},
+			{Alias: "web", Dir: filepath.Join(t.TempDir(), "missing-web")},
+		},
+	}
+
+	statuses := CollectRepoStatuses(appState, false)
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 statuses, got %d", len(statuses))
+	}
+
+	for _, status := range statuses {
+		if !status.Missing {
+			t.Errorf("Expected repository '%s' to be reported missing", status.Alias)
+		}
+	}
+
+	if !statuses[0].Active || statuses[1].Active {
+		t.Errorf("Expected only 'api' to be active, got %+v", statuses)
+	}
+}
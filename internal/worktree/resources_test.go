@@ -0,0 +1,100 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/state"
+	"worktree-manager/internal/testutil"
+)
+
+func TestAddWorktree_AllocatesResources(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	appState.Repos[0].Ports = state.PortSlots{"web": 3000, "db-primary": 5500}
+	cfg := &config.Config{
+		HookCommands: state.HookCommands{
+			consts.HookPostWorktreeAdd: {{Run: "env | grep -E '^WT_(PORT_|WORKTREE_INDEX|COMPOSE_PROJECT)' | sort > resources.txt"}},
+		},
+	}
+
+	for _, branch := range []string{"one", "two"} {
+		if err := AddWorktree(cfg, appState, branch); err != nil {
+			t.Fatalf("AddWorktree(%s) failed: %v", branch, err)
+		}
+	}
+	repo, _ := appState.FindRepoByAlias(appState.Repos[0].Alias)
+
+	data, err := os.ReadFile(filepath.Join(getWorktreePath(repo, "two"), "resources.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Join([]string{
+		"WT_COMPOSE_PROJECT=" + repo.Alias + "-two",
+		"WT_PORT_DB_PRIMARY=5502",
+		"WT_PORT_WEB=3002",
+		"WT_WORKTREE_INDEX=2",
+	}, "\n") + "\n"
+	if string(data) != expected {
+		t.Errorf("Expected the hook to see\n%s\ngot\n%s", expected, data)
+	}
+
+	// A removed worktree's index goes to the next one added
+	if err := RemoveWorktree(appState, "one", RemoveOptions{}); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	if err := AddWorktree(cfg, appState, "three"); err != nil {
+		t.Fatalf("AddWorktree(three) failed: %v", err)
+	}
+	if meta, _ := appState.GetWorktreeMetadata(repo.Alias, "three"); meta.Index != 1 {
+		t.Errorf("Expected the released index 1, got %d", meta.Index)
+	}
+}
+
+func TestRepoResources(t *testing.T) {
+	repo := &state.Repo{Alias: "My_App", Ports: state.PortSlots{"web": 3000, "top": 65535}}
+
+	resources := repo.Resources("feature/Login.Page", 3)
+	if resources.ComposeProject != "my_app-feature-login-page" {
+		t.Errorf("Expected compose project my_app-feature-login-page, got %s", resources.ComposeProject)
+	}
+	if len(resources.Ports) != 1 || resources.Ports["web"] != 3003 {
+		t.Errorf("Expected only web=3003 within the port range, got %v", resources.Ports)
+	}
+
+	if conflicts := (state.PortSlots{"web": 3000, "api": 3005, "db": 5432}).Conflicts(5); len(conflicts) != 1 {
+		t.Errorf("Expected web and api to conflict with 5 worktrees, got %v", conflicts)
+	}
+	if err := (state.PortSlots{"web port": 3000}).Validate(); err == nil {
+		t.Error("Expected an error for a port name with a space, got nil")
+	}
+}
+
+func TestExecInWorktrees_DetachedWorktreeHasNoIndex(t *testing.T) {
+	appState, _ := setupTestRepo(t)
+	appState.Repos[0].Ports = state.PortSlots{"web": 3000}
+	if err := AddWorktree(&config.Config{}, appState, "one"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	repo, _ := appState.FindRepoByAlias(appState.Repos[0].Alias)
+	testutil.RunGit(t, repo.Dir, "worktree", "add", "--quiet", "--detach", getWorktreePath(repo, "detached"), "main")
+
+	if err := ExecInWorktrees(appState, []string{"true"}, ExecOptions{}); err != nil {
+		t.Fatalf("ExecInWorktrees failed: %v", err)
+	}
+
+	if _, ok := appState.GetWorktreeMetadata(repo.Alias, ""); ok {
+		t.Error("Expected no metadata to be recorded for the detached worktree")
+	}
+	for _, variable := range worktreeEnv(appState, repo, "") {
+		if strings.HasPrefix(variable, "WT_WORKTREE_INDEX=") || strings.HasPrefix(variable, "WT_PORT_") {
+			t.Errorf("Expected no resource variables for a detached worktree, got %s", variable)
+		}
+	}
+	if meta, _ := appState.GetWorktreeMetadata(repo.Alias, "one"); meta.Index != 1 {
+		t.Errorf("Expected 'one' to keep index 1, got %d", meta.Index)
+	}
+}
@@ -0,0 +1,255 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"worktree-manager/internal/forge"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/state"
+)
+
+// statusConcurrency limits how many git status processes run at once
+const statusConcurrency = 8
+
+// WorktreeInfo combines a git worktree with its collected status
+type WorktreeInfo struct {
+	git.Worktree
+	Status      git.WorktreeStatus
+	StatusErr   error
+	Current     bool
+	PullRequest *forge.PullRequest
+	ForgeErr    error
+	// Bootstrap is the state of the worktree's post-worktree-add hook, when recorded
+	Bootstrap string
+	// Resources are the index and ports allocated to the worktree, when it has an index
+	Resources *state.WorktreeResources
+}
+
+// SortOptions lists the accepted values for the --sort flag
+var SortOptions = []string{"branch", "age", "status", "path"}
+
+// FilterOptions lists the accepted keyword values for the --filter flag; any other value is a branch glob
+var FilterOptions = []string{"dirty", "clean", "ahead", "behind", "locked", "prunable"}
+
+// listManagedWorktrees returns the worktrees of a repo, excluding the bare entry and the main checkout
+func listManagedWorktrees(repo *state.Repo) ([]git.Worktree, error) {
+	worktrees, err := git.ListWorktrees(repo.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	mainCheckout := resolvePath(repo.Dir)
+
+	var managed []git.Worktree
+	for _, wt := range worktrees {
+		if wt.Bare || resolvePath(wt.Path) == mainCheckout {
+			continue
+		}
+		managed = append(managed, wt)
+	}
+
+	return managed, nil
+}
+
+// RepoWorktrees returns the managed worktrees of a repo together with their status
+func RepoWorktrees(repo *state.Repo) ([]WorktreeInfo, error) {
+	worktrees, err := listManagedWorktrees(repo)
+	if err != nil {
+		return nil, err
+	}
+	return CollectWorktreeInfo(worktrees), nil
+}
+
+// CollectWorktreeInfo gathers the git status of each worktree concurrently
+func CollectWorktreeInfo(worktrees []git.Worktree) []WorktreeInfo {
+	infos := make([]WorktreeInfo, len(worktrees))
+	currentDir := currentWorkingDir()
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, statusConcurrency)
+
+	for i, wt := range worktrees {
+		infos[i] = WorktreeInfo{
+			Worktree: wt,
+			Current:  isWithinPath(currentDir, resolvePath(wt.Path)),
+		}
+
+		// Prunable worktrees have no directory left to inspect
+		if wt.Prunable {
+			continue
+		}
+
+		wg.Add(1)
+		go func(info *WorktreeInfo) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			info.Status, info.StatusErr = git.GetWorktreeStatus(info.Path)
+		}(&infos[i])
+	}
+
+	wg.Wait()
+	return infos
+}
+
+// FilterWorktreeInfo keeps the worktrees matching a filter keyword or branch glob
+func FilterWorktreeInfo(infos []WorktreeInfo, filter string) ([]WorktreeInfo, error) {
+	if filter == "" {
+		return infos, nil
+	}
+
+	if _, err := path.Match(filter, ""); err != nil {
+		return nil, fmt.Errorf("invalid filter pattern '%s': %w", filter, err)
+	}
+
+	var filtered []WorktreeInfo
+	for _, info := range infos {
+		if matchesFilter(info, filter) {
+			filtered = append(filtered, info)
+		}
+	}
+
+	return filtered, nil
+}
+
+func matchesFilter(info WorktreeInfo, filter string) bool {
+	switch filter {
+	case "dirty":
+		return info.Status.Dirty
+	case "clean":
+		return !info.Status.Dirty && info.StatusErr == nil && !info.Prunable
+	case "ahead":
+		return info.Status.Ahead > 0
+	case "behind":
+		return info.Status.Behind > 0
+	case "locked":
+		return info.Locked
+	case "prunable":
+		return info.Prunable
+	}
+
+	matched, _ := path.Match(filter, info.ShortBranch())
+	return matched
+}
+
+// SortWorktreeInfo orders worktrees in place by the given sort key
+func SortWorktreeInfo(infos []WorktreeInfo, sortBy string) error {
+	var less func(a, b WorktreeInfo) bool
+
+	switch sortBy {
+	case "", "branch":
+		less = func(a, b WorktreeInfo) bool { return a.ShortBranch() < b.ShortBranch() }
+	case "age":
+		less = func(a, b WorktreeInfo) bool { return a.Status.LastCommitTime.After(b.Status.LastCommitTime) }
+	case "status":
+		less = func(a, b WorktreeInfo) bool { return a.Status.Dirty && !b.Status.Dirty }
+	case "path":
+		less = func(a, b WorktreeInfo) bool { return a.Path < b.Path }
+	default:
+		return fmt.Errorf("invalid sort option '%s' (valid options: %s)", sortBy, strings.Join(SortOptions, ", "))
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool { return less(infos[i], infos[j]) })
+	return nil
+}
+
+func formatBranch(info WorktreeInfo) string {
+	if info.Detached || info.Branch == "" {
+		return "(detached)"
+	}
+	return info.ShortBranch()
+}
+
+func formatState(info WorktreeInfo) string {
+	switch {
+	case info.Prunable:
+		return "missing"
+	case info.StatusErr != nil:
+		return "error"
+	case info.Status.Dirty:
+		return "dirty"
+	default:
+		return "clean"
+	}
+}
+
+func formatUpstream(status git.WorktreeStatus) string {
+	if status.Upstream == "" {
+		return "-"
+	}
+	if status.Ahead == 0 && status.Behind == 0 {
+		return "up to date"
+	}
+	return fmt.Sprintf("↑%d ↓%d", status.Ahead, status.Behind)
+}
+
+func formatFlags(info WorktreeInfo) string {
+	var flags []string
+	if info.Locked {
+		flags = append(flags, "locked")
+	}
+	if info.Prunable {
+		flags = append(flags, "prunable")
+	}
+	return strings.Join(flags, ",")
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	elapsed := time.Since(t)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	case elapsed < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(elapsed.Hours()/24))
+	case elapsed < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(elapsed.Hours()/(24*30)))
+	default:
+		return fmt.Sprintf("%dy ago", int(elapsed.Hours()/(24*365)))
+	}
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+func currentWorkingDir() string {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return resolvePath(pwd)
+}
+
+func resolvePath(p string) string {
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+	return filepath.Clean(p)
+}
+
+func isWithinPath(dir, root string) bool {
+	if dir == "" {
+		return false
+	}
+	return dir == root || strings.HasPrefix(dir, root+string(filepath.Separator))
+}
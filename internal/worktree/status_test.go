@@ -0,0 +1,97 @@
+package worktree
+
+import (
+	"testing"
+	"time"
+
+	"worktree-manager/internal/git"
+)
+
+func testWorktreeInfos() []WorktreeInfo {
+	now := time.Now()
+	return []WorktreeInfo{
+		{
+			Worktree: git.Worktree{Path: "/wt/b", Branch: "refs/heads/feature/b"},
+			Status:   git.WorktreeStatus{Dirty: true, LastCommitTime: now.Add(-2 * time.Hour)},
+		},
+		{
+			Worktree: git.Worktree{Path: "/wt/a", Branch: "refs/heads/feature/a", Locked: true},
+			Status:   git.WorktreeStatus{Upstream: "origin/feature/a", Ahead: 1, LastCommitTime: now.Add(-time.Hour)},
+		},
+		{
+			Worktree: git.Worktree{Path: "/wt/c", Branch: "refs/heads/bugfix/c", Prunable: true},
+		},
+	}
+}
+
+func TestFilterWorktreeInfo(t *testing.T) {
+	tests := []struct {
+		filter   string
+		expected []string
+	}{
+		{filter: "", expected: []string{"feature/b", "feature/a", "bugfix/c"}},
+		{filter: "dirty", expected: []string{"feature/b"}},
+		{filter: "clean", expected: []string{"feature/a"}},
+		{filter: "ahead", expected: []string{"feature/a"}},
+		{filter: "locked", expected: []string{"feature/a"}},
+		{filter: "prunable", expected: []string{"bugfix/c"}},
+		{filter: "feature/*", expected: []string{"feature/b", "feature/a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filtered, err := FilterWorktreeInfo(testWorktreeInfos(), tt.filter)
+			if err != nil {
+				t.Fatalf("FilterWorktreeInfo(%q) failed: %v", tt.filter, err)
+			}
+
+			if len(filtered) != len(tt.expected) {
+				t.Fatalf("FilterWorktreeInfo(%q) returned %d worktrees, want %d", tt.filter, len(filtered), len(tt.expected))
+			}
+
+			for i, info := range filtered {
+				if info.ShortBranch() != tt.expected[i] {
+					t.Errorf("FilterWorktreeInfo(%q)[%d] = %q, want %q", tt.filter, i, info.ShortBranch(), tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterWorktreeInfo_InvalidPattern(t *testing.T) {
+	if _, err := FilterWorktreeInfo(testWorktreeInfos(), "feature/["); err == nil {
+		t.Error("Expected error for invalid glob pattern, got nil")
+	}
+}
+
+func TestSortWorktreeInfo(t *testing.T) {
+	tests := []struct {
+		sortBy   string
+		expected []string
+	}{
+		{sortBy: "branch", expected: []string{"bugfix/c", "feature/a", "feature/b"}},
+		{sortBy: "age", expected: []string{"feature/a", "feature/b", "bugfix/c"}},
+		{sortBy: "status", expected: []string{"feature/b", "feature/a", "bugfix/c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			infos := testWorktreeInfos()
+			if err := SortWorktreeInfo(infos, tt.sortBy); err != nil {
+				t.Fatalf("SortWorktreeInfo(%q) failed: %v", tt.sortBy, err)
+			}
+
+			for i, info := range infos {
+				if info.ShortBranch() != tt.expected[i] {
+					t.Errorf("SortWorktreeInfo(%q)[%d] = %q, want %q", tt.sortBy, i, info.ShortBranch(), tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSortWorktreeInfo_InvalidOption(t *testing.T) {
+	if err := SortWorktreeInfo(testWorktreeInfos(), "size"); err == nil {
+		t.Error("Expected error for invalid sort option, got nil")
+	}
+}
@@ -0,0 +1,203 @@
+package worktree
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"worktree-manager/internal/config"
+	"worktree-manager/internal/consts"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/output"
+	"worktree-manager/internal/state"
+)
+
+// SyncOptions controls how worktrees are updated from their upstream
+type SyncOptions struct {
+	Repos    []string
+	All      bool
+	Strategy string
+	OntoBase bool
+}
+
+// SyncResult records the outcome of syncing a single worktree
+type SyncResult struct {
+	Repo   string
+	Branch string
+	Result string
+	Detail string
+}
+
+const (
+	syncUpdated  = "updated"
+	syncUpToDate = "up to date"
+	syncSkipped  = "skipped"
+	syncFailed   = "failed"
+)
+
+// SelectRepos resolves the repositories a command operates on: all of them, the named ones, or the active one
+func SelectRepos(appState *state.State, aliases []string, all bool) ([]state.Repo, error) {
+	if all {
+		if len(appState.Repos) == 0 {
+			return nil, fmt.Errorf("no repositories configured")
+		}
+		return appState.Repos, nil
+	}
+
+	if len(aliases) == 0 {
+		activeRepo, err := appState.GetActiveRepo()
+		if err != nil {
+			return nil, err
+		}
+		return []state.Repo{*activeRepo}, nil
+	}
+
+	repos := make([]state.Repo, 0, len(aliases))
+	for _, alias := range aliases {
+		repo, err := appState.FindRepoByAlias(alias)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, *repo)
+	}
+	return repos, nil
+}
+
+// SyncWorktrees fetches each selected repository once and updates its clean worktrees
+func SyncWorktrees(cfg *config.Config, appState *state.State, opts SyncOptions) error {
+	strategy := opts.Strategy
+	if strategy == "" {
+		strategy = cfg.GetSyncStrategy()
+	}
+	if !slices.Contains(consts.SyncStrategies, strategy) {
+		return fmt.Errorf("invalid sync strategy '%s' (valid options: %s)", strategy, strings.Join(consts.SyncStrategies, ", "))
+	}
+
+	repos, err := SelectRepos(appState, opts.Repos, opts.All)
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	var results []SyncResult
+	for i := range repos {
+		results = append(results, syncRepo(&repos[i], strategy, opts.OntoBase)...)
+	}
+
+	PrintSyncSummary(results)
+
+	failed := 0
+	for _, result := range results {
+		if result.Result == syncFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d worktree(s) failed to sync", failed)
+	}
+	return nil
+}
+
+func syncRepo(repo *state.Repo, strategy string, ontoBase bool) []SyncResult {
+	output.Progress("Fetching '%s' from origin...", repo.Alias)
+
+	if err := git.FetchFromOrigin(repo.Dir); err != nil {
+		return []SyncResult{{Repo: repo.Alias, Result: syncFailed, Detail: fmt.Sprintf("fetch failed: %v", err)}}
+	}
+
+	worktrees, err := listManagedWorktrees(repo)
+	if err != nil {
+		return []SyncResult{{Repo: repo.Alias, Result: syncFailed, Detail: err.Error()}}
+	}
+
+	var baseBranch string
+	if ontoBase {
+		baseBranch, err = repoBaseBranch(repo)
+		if err != nil {
+			return []SyncResult{{Repo: repo.Alias, Result: syncFailed, Detail: fmt.Sprintf("failed to determine base branch: %v", err)}}
+		}
+	}
+
+	var results []SyncResult
+	for _, info := range CollectWorktreeInfo(worktrees) {
+		result := syncWorktree(info, strategy, baseBranch)
+		result.Repo = repo.Alias
+		result.Branch = formatBranch(info)
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func syncWorktree(info WorktreeInfo, strategy, baseBranch string) SyncResult {
+	switch {
+	case info.Prunable:
+		return SyncResult{Result: syncSkipped, Detail: "worktree directory missing"}
+	case info.StatusErr != nil:
+		return SyncResult{Result: syncFailed, Detail: info.StatusErr.Error()}
+	case info.Detached || info.Branch == "":
+		return SyncResult{Result: syncSkipped, Detail: "detached HEAD"}
+	case info.Status.Dirty:
+		return SyncResult{Result: syncSkipped, Detail: "uncommitted changes"}
+	}
+
+	// Feature branches are rebased onto the base branch; the base branch itself follows its upstream
+	if baseBranch != "" && "origin/"+info.ShortBranch() != baseBranch {
+		return rebaseOntoBase(info, baseBranch)
+	}
+
+	if info.Status.Upstream == "" {
+		return SyncResult{Result: syncSkipped, Detail: "no upstream branch"}
+	}
+
+	if info.Status.Behind == 0 {
+		return SyncResult{Result: syncUpToDate}
+	}
+
+	output.Progress("Updating '%s' from %s...", info.ShortBranch(), info.Status.Upstream)
+
+	if strategy == "rebase" {
+		if err := git.Rebase(info.Path, info.Status.Upstream); err != nil {
+			return SyncResult{Result: syncSkipped, Detail: "rebase conflicts, rebase aborted"}
+		}
+		return SyncResult{Result: syncUpdated, Detail: fmt.Sprintf("rebased onto %s", info.Status.Upstream)}
+	}
+
+	if info.Status.Ahead > 0 {
+		return SyncResult{Result: syncSkipped, Detail: fmt.Sprintf("diverged from %s, cannot fast-forward", info.Status.Upstream)}
+	}
+
+	if err := git.MergeFastForward(info.Path, info.Status.Upstream); err != nil {
+		return SyncResult{Result: syncFailed, Detail: fmt.Sprintf("fast-forward failed: %v", err)}
+	}
+	return SyncResult{Result: syncUpdated, Detail: fmt.Sprintf("fast-forwarded %d commit(s)", info.Status.Behind)}
+}
+
+func rebaseOntoBase(info WorktreeInfo, baseBranch string) SyncResult {
+	if git.IsAncestor(info.Path, baseBranch, "HEAD") {
+		return SyncResult{Result: syncUpToDate, Detail: fmt.Sprintf("already contains %s", baseBranch)}
+	}
+
+	output.Progress("Rebasing '%s' onto %s...", info.ShortBranch(), baseBranch)
+
+	if err := git.Rebase(info.Path, baseBranch); err != nil {
+		return SyncResult{Result: syncSkipped, Detail: "rebase conflicts, rebase aborted"}
+	}
+	return SyncResult{Result: syncUpdated, Detail: fmt.Sprintf("rebased onto %s", baseBranch)}
+}
+
+// PrintSyncSummary displays the outcome of a sync run
+func PrintSyncSummary(results []SyncResult) {
+	if len(results) == 0 {
+		output.Hint("No worktrees to sync. Use 'wt tree add <branch>' to create one.")
+		return
+	}
+
+	output.Info("Sync summary:")
+
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, []string{result.Repo, result.Branch, result.Result, result.Detail})
+	}
+
+	output.Table([]string{"REPO", "BRANCH", "RESULT", "DETAIL"}, rows)
+}
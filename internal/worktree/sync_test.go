@@ -0,0 +1,136 @@
+package worktree
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"worktree-manager/internal/executors"
+	"worktree-manager/internal/git"
+	"worktree-manager/internal/state"
+)
+
+func TestSyncWorktree_SkipsWithoutRunningGit(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     WorktreeInfo
+		expected SyncResult
+	}{
+		{
+			name:     "Missing directory",
+			info:     WorktreeInfo{Worktree: git.Worktree{Branch: "refs/heads/a", Prunable: true}},
+			expected: SyncResult{Result: syncSkipped, Detail: "worktree directory missing"},
+		},
+		{
+			name:     "Status error",
+			info:     WorktreeInfo{Worktree: git.Worktree{Branch: "refs/heads/a"}, StatusErr: errors.New("boom")},
+			expected: SyncResult{Result: syncFailed, Detail: "boom"},
+		},
+		{
+			name:     "Detached HEAD",
+			info:     WorktreeInfo{Worktree: git.Worktree{Detached: true}},
+			expected: SyncResult{Result: syncSkipped, Detail: "detached HEAD"},
+		},
+		{
+			name:     "Dirty worktree",
+			info:     WorktreeInfo{Worktree: git.Worktree{Branch: "refs/heads/a"}, Status: git.WorktreeStatus{Dirty: true}},
+			expected: SyncResult{Result: syncSkipped, Detail: "uncommitted changes"},
+		},
+		{
+			name:     "No upstream",
+			info:     WorktreeInfo{Worktree: git.Worktree{Branch: "refs/heads/a"}},
+			expected: SyncResult{Result: syncSkipped, Detail: "no upstream branch"},
+		},
+		{
+			name:     "Up to date",
+			info:     WorktreeInfo{Worktree: git.Worktree{Branch: "refs/heads/a"}, Status: git.WorktreeStatus{Upstream: "origin/a"}},
+			expected: SyncResult{Result: syncUpToDate},
+		},
+		{
+			name:     "Diverged with fast-forward strategy",
+			info:     WorktreeInfo{Worktree: git.Worktree{Branch: "refs/heads/a"}, Status: git.WorktreeStatus{Upstream: "origin/a", Ahead: 1, Behind: 1}},
+			expected: SyncResult{Result: syncSkipped, Detail: "diverged from origin/a, cannot fast-forward"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := syncWorktree(tt.info, "ff-only", "")
+			if result != tt.expected {
+				t.Errorf("syncWorktree() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSelectRepos(t *testing.T) {
+	appState := &state.State{
+		ActiveRepo: "api",
+		Repos:      []state.Repo{{Alias: "api"}, {Alias: "web"}, {Alias: "docs"}},
+	}
+
+	repos, err := SelectRepos(appState, nil, false)
+	if err != nil || len(repos) != 1 || repos[0].Alias != "api" {
+		t.Errorf("Expected active repository only, got %+v (err: %v)", repos, err)
+	}
+
+	repos, err = SelectRepos(appState, []string{"web", "docs"}, false)
+	if err != nil || len(repos) != 2 || repos[1].Alias != "docs" {
+		t.Errorf("Expected named repositories, got %+v (err: %v)", repos, err)
+	}
+
+	repos, err = SelectRepos(appState, nil, true)
+	if err != nil || len(repos) != 3 {
+		t.Errorf("Expected all repositories, got %+v (err: %v)", repos, err)
+	}
+
+	if _, err := SelectRepos(appState, []string{"unknown"}, false); err == nil {
+		t.Error("Expected error for unknown repository alias, got nil")
+	}
+}
+
+func TestSyncRepo_Replay(t *testing.T) {
+	gitCall := func(dir string, stdout string, args ...string) executors.Interaction {
+		return executors.Interaction{Command: "git", Args: args, WorkingDir: dir, Stdout: stdout}
+	}
+	worktreeList := `worktree /repos/app
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /worktrees/app/behind
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/behind
+
+worktree /worktrees/app/dirty
+HEAD 3333333333333333333333333333333333333333
+branch refs/heads/dirty
+`
+	replay := executors.NewReplayExecutor(
+		gitCall("/repos/app", "", "fetch", "origin"),
+		gitCall("/repos/app", worktreeList, "worktree", "list", "--porcelain"),
+		gitCall("/worktrees/app/behind", "# branch.upstream origin/behind\n# branch.ab +0 -2\n", "status", "--porcelain=v2", "--branch"),
+		gitCall("/worktrees/app/behind", "1700000000\x00Behind work\n", "log", "-1", "--format=%ct%x00%s"),
+		gitCall("/worktrees/app/dirty", "# branch.upstream origin/dirty\n# branch.ab +0 -1\n? notes.txt\n", "status", "--porcelain=v2", "--branch"),
+		gitCall("/worktrees/app/dirty", "1700000000\x00Dirty work\n", "log", "-1", "--format=%ct%x00%s"),
+		gitCall("/worktrees/app/behind", "", "merge", "--ff-only", "origin/behind"),
+	)
+	t.Cleanup(git.SetDefaultExecutor(replay))
+
+	results := syncRepo(&state.Repo{Alias: "app", Dir: "/repos/app"}, "ff-only", false)
+
+	expected := []SyncResult{
+		{Repo: "app", Branch: "behind", Result: syncUpdated, Detail: "fast-forwarded 2 commit(s)"},
+		{Repo: "app", Branch: "dirty", Result: syncSkipped, Detail: "uncommitted changes"},
+	}
+	if !slices.Equal(results, expected) {
+		t.Errorf("syncRepo() = %+v, want %+v", results, expected)
+	}
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Errorf("Expected every git command to run, missing %+v", unused)
+	}
+	for _, call := range replay.Calls() {
+		if call.WorkingDir == "/worktrees/app/dirty" && call.Args[0] == "merge" {
+			t.Error("Expected the dirty worktree not to be merged")
+		}
+	}
+}